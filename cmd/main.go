@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/isd-sgcu/sucu-backend-2024/cmd/server"
 	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/usecases"
@@ -11,6 +12,8 @@ import (
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/database"
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/logger"
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/s3client"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/scheduler"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/tusstore"
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/validator"
 )
 
@@ -28,18 +31,36 @@ import (
 func main() {
 	cfg := config.GetConfig()
 	db := database.NewGormDatabase(cfg)
-	s3 := s3client.NewS3Client(cfg)
+	s3 := s3client.NewStorage(cfg)
+	tus := tusstore.NewTusStore(cfg)
 	logger := logger.NewLogger(cfg)
 	validator, err := validator.NewDtoValidator()
 	if err != nil {
 		panic(fmt.Sprintf("Failed to create dto validator: %v", err))
 	}
 
-	repositories := repositories.NewRepository(cfg, db, s3)
+	repositories := repositories.NewRepository(cfg, db, s3, tus)
 	usecases := usecases.NewUsecase(repositories, cfg, logger)
 	handlers := handlers.NewHandler(usecases, validator)
 
 	servers := server.NewFiberHttpServer(cfg, logger, handlers)
 
+	// background jobs
+	jobs := scheduler.NewScheduler(logger.Named("Scheduler"))
+	jobs.Every("PurgeTrash", time.Duration(cfg.GetJob().TrashPurgeInterval)*time.Second, func() {
+		usecases.Document().PurgeExpiredTrash()
+		usecases.Attachment().PurgeExpiredAttachments()
+	})
+	jobs.Every("ArchiveDocuments", time.Duration(cfg.GetJob().ArchiveInterval)*time.Second, func() {
+		usecases.Document().ArchiveExpiredDocuments()
+	})
+	jobs.Every("CleanupUploads", time.Duration(cfg.GetJob().UploadCleanupInterval)*time.Second, func() {
+		usecases.Attachment().CleanupExpiredUploads()
+	})
+	jobs.Every("GenerateImageVariants", time.Duration(cfg.GetJob().ImageVariantInterval)*time.Second, func() {
+		usecases.Attachment().GenerateImageVariants()
+	})
+
 	servers.Start()
+	jobs.Stop()
 }
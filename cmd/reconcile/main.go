@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/usecases"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/repositories"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/database"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/logger"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/s3client"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/tusstore"
+)
+
+// reconcile reports bucket objects no attachment owns and attachments whose object is gone.
+// With -remove it also deletes them.
+func main() {
+	remove := flag.Bool("remove", false, "delete orphan objects and attachments whose object is gone")
+	flag.Parse()
+
+	cfg := config.GetConfig()
+	if cfg.GetStorage().Driver == s3client.DRIVER_MEMORY {
+		// a new process starts with an empty bucket, every attachment would look broken
+		fmt.Fprintln(os.Stderr, "The memory storage driver cannot be reconciled")
+		os.Exit(1)
+	}
+
+	db := database.NewGormDatabase(cfg)
+	s3 := s3client.NewStorage(cfg)
+	tus := tusstore.NewTusStore(cfg)
+	logger := logger.NewLogger(cfg)
+
+	repositories := repositories.NewRepository(cfg, db, s3, tus)
+	usecases := usecases.NewUsecase(repositories, cfg, logger)
+
+	report, apperr := usecases.Attachment().ReconcileStorage(*remove)
+	if apperr != nil {
+		fmt.Fprintln(os.Stderr, apperr.Error())
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		panic(fmt.Sprintf("Failed to print report: %v", err))
+	}
+}
@@ -11,6 +11,8 @@ import (
 	_ "github.com/isd-sgcu/sucu-backend-2024/docs"
 	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/handlers"
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/s3client"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -29,7 +31,13 @@ type FiberHttpServer struct {
 
 func NewFiberHttpServer(cfg config.Config, logger *zap.Logger, handlers handlers.Handler) *FiberHttpServer {
 	return &FiberHttpServer{
-		app:      fiber.New(),
+		app: fiber.New(fiber.Config{
+			// bodies above BodyLimit and chunked bodies are read as a stream without a cap, the BodyLimit middleware of each route caps them.
+			// Multipart forms are parsed by the handler, after that check, and their files spill to disk.
+			StreamRequestBody:            true,
+			BodyLimit:                    constant.MAX_BODY_SIZE,
+			DisablePreParseMultipartForm: true,
+		}),
 		cfg:      cfg,
 		logger:   logger,
 		handlers: handlers,
@@ -47,6 +55,11 @@ func (s *FiberHttpServer) Start() {
 	s.initUserRouter(router, s.handlers)
 	s.initAttachmentRouter(router, s.handlers)
 	s.initDocumentRouter(router, s.handlers)
+	s.initTagRouter(router, s.handlers)
+	s.initDocumentTypeRouter(router, s.handlers)
+	s.initBudgetRouter(router, s.handlers)
+	s.initDatasetRouter(router, s.handlers)
+	s.initFileRouter(router, s.handlers)
 
 	// Setup signal capturing for graceful shutdown
 	quit := make(chan os.Signal, 1)
@@ -83,9 +96,9 @@ func (s *FiberHttpServer) initHttpServer() fiber.Router {
 	// enable cors
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:3000",
-		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS,PATCH",
-		AllowHeaders:     "Origin,X-PINGOTHER,Accept,Authorization,Content-Type,X-CSRF-Token",
-		ExposeHeaders:    "Link",
+		AllowMethods:     "GET,HEAD,POST,PUT,DELETE,OPTIONS,PATCH",
+		AllowHeaders:     "Origin,X-PINGOTHER,Accept,Authorization,Content-Type,X-CSRF-Token,Tus-Resumable,Upload-Length,Upload-Metadata,Upload-Offset",
+		ExposeHeaders:    "Link,Location,Tus-Resumable,Tus-Version,Tus-Extension,Tus-Max-Size,Upload-Offset,Upload-Length,Upload-Expires",
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -109,14 +122,14 @@ func (s *FiberHttpServer) initHttpServer() fiber.Router {
 }
 
 func (s *FiberHttpServer) initAuthRouter(router fiber.Router, httpHandler handlers.Handler) {
-	authRouter := router.Group("/auth")
+	authRouter := router.Group("/auth", httpHandler.Middleware().BodyLimit(constant.MAX_BODY_SIZE))
 
 	authRouter.Post("/login", httpHandler.Auth().Login)
 	authRouter.Get("/me", httpHandler.Middleware().IsLogin, httpHandler.Auth().GetMe)
 }
 
 func (s *FiberHttpServer) initUserRouter(router fiber.Router, httpHandler handlers.Handler) {
-	userRouter := router.Group("/users")
+	userRouter := router.Group("/users", httpHandler.Middleware().BodyLimit(constant.MAX_BODY_SIZE))
 
 	userRouter.Get("/", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.User().GetAllUsers)
 	userRouter.Get("/:user_id", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.User().GetUserByID)
@@ -129,17 +142,124 @@ func (s *FiberHttpServer) initUserRouter(router fiber.Router, httpHandler handle
 func (s *FiberHttpServer) initAttachmentRouter(router fiber.Router, httpHandler handlers.Handler) {
 	attachmentRouter := router.Group("/attachments")
 
-	attachmentRouter.Post("/:document_id", httpHandler.Attachment().CreateAttachments)
+	attachmentRouter.Get("/", httpHandler.Attachment().GetAllAttachments)
+	attachmentRouter.Get("/role/:role_id", httpHandler.Middleware().IsLogin, httpHandler.Attachment().GetAllAttachmentsByRole)
+	attachmentRouter.Get("/storage/savings", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Attachment().GetStorageSavings)
+	attachmentRouter.Get("/:attachment_id/url", httpHandler.Middleware().IsLogin, httpHandler.Attachment().GetAttachmentURL)
+	attachmentRouter.Post("/uploads/:upload_id/complete", httpHandler.Middleware().IsLogin, httpHandler.Attachment().CompleteAttachmentUpload)
+	attachmentRouter.Post("/:document_id/uploads", httpHandler.Middleware().BodyLimit(constant.MAX_BODY_SIZE), httpHandler.Middleware().IsLogin, httpHandler.Attachment().CreateAttachmentUploads)
+	attachmentRouter.Options("/:document_id/tus", httpHandler.Attachment().TusResumable, httpHandler.Attachment().OptionsTusUpload)
+	attachmentRouter.Options("/:document_id/tus/:upload_id", httpHandler.Attachment().TusResumable, httpHandler.Attachment().OptionsTusUpload)
+	attachmentRouter.Post("/:document_id/tus", httpHandler.Attachment().TusResumable, httpHandler.Middleware().IsLogin, httpHandler.Attachment().CreateTusUpload)
+	attachmentRouter.Head("/:document_id/tus/:upload_id", httpHandler.Attachment().TusResumable, httpHandler.Middleware().IsLogin, httpHandler.Attachment().HeadTusUpload)
+	attachmentRouter.Patch("/:document_id/tus/:upload_id", httpHandler.Attachment().TusResumable, httpHandler.Middleware().BodyLimit(int(constant.MAX_FILE_SIZE)), httpHandler.Middleware().IsLogin, httpHandler.Attachment().PatchTusUpload)
+	attachmentRouter.Post("/:document_id", httpHandler.Middleware().BodyLimit(constant.MAX_UPLOAD_REQUEST_SIZE), httpHandler.Middleware().IsLogin, httpHandler.Attachment().CreateAttachments)
 	attachmentRouter.Delete("/:attachment_id", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Attachment().DeleteAttachment)
 }
 
 func (s *FiberHttpServer) initDocumentRouter(router fiber.Router, httpHandler handlers.Handler) {
-	documentRouter := router.Group("/documents")
+	documentRouter := router.Group("/documents", httpHandler.Middleware().BodyLimit(constant.MAX_BODY_SIZE))
 
 	documentRouter.Get("/", httpHandler.Document().GetAllDocuments)
 	documentRouter.Get("/role/:role_id", httpHandler.Document().GetDocumentsByRole)
+	documentRouter.Get("/slug/:slug", httpHandler.Document().GetDocumentBySlug)
+	documentRouter.Get("/archive", httpHandler.Document().GetArchivedDocuments)
 	documentRouter.Post("/", httpHandler.Middleware().IsLogin, httpHandler.Document().CreateDocument)
-	documentRouter.Patch("/:document_id", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().UpdateDocumentByID)
+	documentRouter.Patch("/:document_id", httpHandler.Middleware().IsLogin, httpHandler.Document().UpdateDocumentByID)
 	documentRouter.Delete("/:document_id", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().DeleteDocumentByID)
+	documentRouter.Put("/:document_id/tags", httpHandler.Middleware().IsLogin, httpHandler.Document().UpdateDocumentTags)
+	documentRouter.Put("/:document_id/slug", httpHandler.Middleware().IsLogin, httpHandler.Document().UpdateDocumentSlug)
+	documentRouter.Put("/:document_id/expiry", httpHandler.Middleware().IsLogin, httpHandler.Document().UpdateDocumentExpiry)
+	documentRouter.Post("/bulk", httpHandler.Middleware().IsLogin, httpHandler.Document().BulkUpdateDocuments)
 
+	// translation
+	documentRouter.Get("/:document_id/translations", httpHandler.Middleware().IsLogin, httpHandler.Document().GetDocumentTranslations)
+	documentRouter.Put("/:document_id/translations/:locale", httpHandler.Middleware().IsLogin, httpHandler.Document().UpdateDocumentTranslation)
+	documentRouter.Delete("/:document_id/translations/:locale", httpHandler.Middleware().IsLogin, httpHandler.Document().DeleteDocumentTranslation)
+
+	// featured
+	documentRouter.Get("/featured", httpHandler.Document().GetFeaturedDocuments)
+	documentRouter.Get("/featured/:scope", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().GetFeaturedSlots)
+	documentRouter.Put("/featured/:scope", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().UpdateFeaturedDocuments)
+	documentRouter.Delete("/featured/:scope/:document_id", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().DeleteFeaturedDocument)
+
+	// link
+	documentRouter.Get("/:document_id/links", httpHandler.Middleware().IsLogin, httpHandler.Document().GetDocumentLinks)
+	documentRouter.Put("/:document_id/links", httpHandler.Middleware().IsLogin, httpHandler.Document().UpdateDocumentLinks)
+
+	// review
+	documentRouter.Get("/reviews/pending", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().GetPendingDocuments)
+	documentRouter.Get("/:document_id/reviews", httpHandler.Middleware().IsLogin, httpHandler.Document().GetDocumentReviews)
+	documentRouter.Post("/:document_id/submit", httpHandler.Middleware().IsLogin, httpHandler.Document().SubmitDocument)
+	documentRouter.Post("/:document_id/approve", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().ApproveDocument)
+	documentRouter.Post("/:document_id/reject", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().RejectDocument)
+	documentRouter.Post("/:document_id/request-changes", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().RequestDocumentChanges)
+
+	// revision
+	documentRouter.Get("/:document_id/revisions", httpHandler.Middleware().IsLogin, httpHandler.Document().GetDocumentRevisions)
+	documentRouter.Get("/:document_id/revisions/diff", httpHandler.Middleware().IsLogin, httpHandler.Document().DiffDocumentRevisions)
+	documentRouter.Get("/:document_id/revisions/:rev", httpHandler.Middleware().IsLogin, httpHandler.Document().GetDocumentRevision)
+	documentRouter.Post("/:document_id/revisions/:rev/restore", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().RestoreDocumentRevision)
+
+	// trash
+	documentRouter.Get("/trash", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().GetTrashedDocuments)
+	documentRouter.Post("/:document_id/restore", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().RestoreDocumentByID)
+	documentRouter.Delete("/:document_id/purge", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Document().PurgeDocumentByID)
+
+	// registered last so it does not shadow the static routes above
+	documentRouter.Get("/:document_id", httpHandler.Document().GetDocumentByID)
+}
+
+func (s *FiberHttpServer) initTagRouter(router fiber.Router, httpHandler handlers.Handler) {
+	tagRouter := router.Group("/tags", httpHandler.Middleware().BodyLimit(constant.MAX_BODY_SIZE))
+
+	tagRouter.Get("/", httpHandler.Tag().GetAllTags)
+	tagRouter.Get("/usage", httpHandler.Tag().GetTagUsage)
+	tagRouter.Post("/", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Tag().CreateTag)
+	tagRouter.Put("/:tag_id", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Tag().UpdateTagByID)
+	tagRouter.Delete("/:tag_id", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.Tag().DeleteTagByID)
+}
+
+func (s *FiberHttpServer) initDocumentTypeRouter(router fiber.Router, httpHandler handlers.Handler) {
+	documentTypeRouter := router.Group("/document-types", httpHandler.Middleware().BodyLimit(constant.MAX_BODY_SIZE))
+
+	documentTypeRouter.Get("/", httpHandler.DocumentType().GetAllDocumentTypes)
+	documentTypeRouter.Get("/:type_id", httpHandler.DocumentType().GetDocumentTypeByID)
+	documentTypeRouter.Post("/", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.DocumentType().CreateDocumentType)
+	documentTypeRouter.Patch("/:type_id", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.DocumentType().UpdateDocumentTypeByID)
+	documentTypeRouter.Delete("/:type_id", httpHandler.Middleware().IsLogin, httpHandler.Middleware().SuperAdmin, httpHandler.DocumentType().DeleteDocumentTypeByID)
+}
+
+func (s *FiberHttpServer) initBudgetRouter(router fiber.Router, httpHandler handlers.Handler) {
+	budgetRouter := router.Group("/budgets", httpHandler.Middleware().BodyLimit(constant.MAX_BODY_SIZE))
+
+	budgetRouter.Get("/", httpHandler.Budget().GetBudgets)
+	budgetRouter.Get("/:document_id", httpHandler.Budget().GetBudget)
+	budgetRouter.Get("/:document_id/summary", httpHandler.Budget().GetBudgetSummary)
+	budgetRouter.Put("/:document_id", httpHandler.Middleware().IsLogin, httpHandler.Budget().UpdateBudget)
+	budgetRouter.Post("/:document_id/import", httpHandler.Middleware().IsLogin, httpHandler.Budget().ImportBudget)
+	budgetRouter.Delete("/:document_id", httpHandler.Middleware().IsLogin, httpHandler.Budget().DeleteBudget)
+}
+
+func (s *FiberHttpServer) initDatasetRouter(router fiber.Router, httpHandler handlers.Handler) {
+	datasetRouter := router.Group("/datasets", httpHandler.Middleware().BodyLimit(constant.MAX_BODY_SIZE))
+
+	datasetRouter.Get("/:document_id", httpHandler.Dataset().GetDataset)
+	datasetRouter.Get("/:document_id/chart", httpHandler.Dataset().GetDatasetChart)
+	datasetRouter.Get("/:document_id/aggregate", httpHandler.Dataset().AggregateDataset)
+	datasetRouter.Put("/:document_id", httpHandler.Middleware().IsLogin, httpHandler.Dataset().UpdateDataset)
+	datasetRouter.Post("/:document_id/import", httpHandler.Middleware().IsLogin, httpHandler.Dataset().ImportDataset)
+	datasetRouter.Delete("/:document_id", httpHandler.Middleware().IsLogin, httpHandler.Dataset().DeleteDataset)
+}
+
+// initFileRouter serves the files of storage drivers that do not serve them themselves
+func (s *FiberHttpServer) initFileRouter(router fiber.Router, httpHandler handlers.Handler) {
+	if s.cfg.GetStorage().Driver == s3client.DRIVER_S3 {
+		return
+	}
+
+	fileRouter := router.Group("/files")
+
+	fileRouter.Get("/:bucket/*", httpHandler.Attachment().GetStoredFile)
+	fileRouter.Put("/:bucket/*", httpHandler.Middleware().BodyLimit(int(constant.MAX_FILE_SIZE)), httpHandler.Attachment().PutStoredFile)
 }
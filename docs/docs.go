@@ -66,81 +66,90 @@ const docTemplate = `{
                 "tags": [
                     "Attachments"
                 ],
-                "summary": "Get all attachments",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.AttachmentDTO"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {}
-                    }
-                }
-            }
-        },
-        "/attachments/role/{role_id}": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Attachments"
-                ],
-                "summary": "Get all attachments by role",
+                "summary": "Get attachments of published documents",
                 "parameters": [
                     {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "image",
+                            "docs"
+                        ],
                         "type": "string",
-                        "description": "Role of the user",
-                        "name": "role_id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.AttachmentDTO"
-                            }
-                        }
+                        "description": "Attachment type",
+                        "name": "type",
+                        "in": "query"
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {}
-                    }
-                }
-            }
-        },
-        "/attachments/{attachment_id}": {
-            "delete": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Attachments"
-                ],
-                "summary": "Delete an attachment by ID",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "Attachment ID",
-                        "name": "attachment_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sgcu",
+                            "sccu"
+                        ],
+                        "type": "string",
+                        "description": "Organization",
+                        "name": "organization",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Uploaded at or after, RFC 3339",
+                        "name": "start_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Uploaded at or before, RFC 3339",
+                        "name": "end_time",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {}
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/dtos.PaginationResponse"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "data": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dtos.AttachmentDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -153,10 +162,12 @@ const docTemplate = `{
                 }
             }
         },
-        "/attachments/{document_id}": {
-            "post": {
-                "consumes": [
-                    "multipart/form-data"
+        "/attachments/role/{role_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
@@ -164,60 +175,64 @@ const docTemplate = `{
                 "tags": [
                     "Attachments"
                 ],
-                "summary": "Create new attachments",
+                "summary": "Get attachments of documents written by a role, including unpublished ones",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Document ID",
-                        "name": "document_id",
+                        "description": "Role of the document author",
+                        "name": "role_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "Attachment files",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {}
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {}
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {}
-                    }
-                }
-            }
-        },
-        "/auth/login": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Authentication"
-                ],
-                "summary": "Log in user",
-                "parameters": [
                     {
-                        "description": "Login credentials",
-                        "name": "loginUserDTO",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.LoginUserDTO"
-                        }
+                        "enum": [
+                            "image",
+                            "docs"
+                        ],
+                        "type": "string",
+                        "description": "Attachment type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sgcu",
+                            "sccu"
+                        ],
+                        "type": "string",
+                        "description": "Organization",
+                        "name": "organization",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Uploaded at or after, RFC 3339",
+                        "name": "start_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Uploaded at or before, RFC 3339",
+                        "name": "end_time",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -230,7 +245,22 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dtos.LoginResponseDTO"
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/dtos.PaginationResponse"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "data": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dtos.AttachmentDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
                                         }
                                     }
                                 }
@@ -241,27 +271,32 @@ const docTemplate = `{
                         "description": "Bad Request",
                         "schema": {}
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
             }
         },
-        "/auth/me": {
+        "/attachments/storage/savings": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Attachments with the same content and visibility share a stored object. Files uploaded before deduplication are not counted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Attachments"
                 ],
-                "summary": "Get current user profile",
+                "summary": "Get the storage saved by sharing one object between attachments of the same content",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -272,13 +307,21 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dtos.UserDTO"
+                                            "$ref": "#/definitions/dtos.StorageSavingsDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -286,18 +329,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents": {
-            "get": {
+        "/attachments/uploads/{upload_id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The file in storage must have the size and content type given when the upload was started.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Documents"
+                    "Attachments"
+                ],
+                "summary": "Complete a direct upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "upload_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all documents",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "allOf": [
                                 {},
@@ -305,47 +363,53 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dtos.DocumentDTO"
-                                            }
+                                            "$ref": "#/definitions/dtos.AttachmentDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/attachments/{attachment_id}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Documents"
+                    "Attachments"
                 ],
-                "summary": "Create a new document",
+                "summary": "Delete an attachment by ID",
                 "parameters": [
                     {
-                        "description": "Document data",
-                        "name": "document",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.CreateDocumentDTO"
-                        }
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {}
                     },
                     "400": {
@@ -359,22 +423,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/role/{role}": {
+        "/attachments/{attachment_id}/url": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Public attachments have a stable URL. Private ones get a presigned URL that expires after 15 minutes, issued to the author or a superadmin of the author's organization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Documents"
+                    "Attachments"
                 ],
-                "summary": "Get documents by user role",
+                "summary": "Get a download URL of an attachment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User role",
-                        "name": "role_id",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Width of an image variant, the original when empty",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "jpeg",
+                            "png",
+                            "webp"
+                        ],
+                        "type": "string",
+                        "description": "Format of the image variant, the original format when empty",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -387,16 +474,17 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dtos.DocumentDTO"
-                                            }
+                                            "$ref": "#/definitions/dtos.AttachmentURLDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {}
@@ -408,15 +496,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/documents/{document_id}": {
-            "get": {
+        "/attachments/{document_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The content of each file must match its extension. SVGs with script, event handlers or external links are rejected. Every rejected file is named in the error.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Documents"
+                    "Attachments"
                 ],
-                "summary": "Get document by ID",
+                "summary": "Create new attachments",
                 "parameters": [
                     {
                         "type": "string",
@@ -424,43 +521,69 @@ const docTemplate = `{
                         "name": "document_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Attachment files",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "public",
+                            "private"
+                        ],
+                        "type": "string",
+                        "default": "public",
+                        "description": "Private files are only reachable through a signed URL",
+                        "name": "visibility",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {},
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dtos.DocumentDTO"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {}
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/attachments/{document_id}/tus": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "tus 1.0 creation. Upload-Metadata must carry the base64 encoded filename, or name, and may carry the visibility, public by default.\nSend the file with PATCH requests to the returned Location. An upload that gets no chunk for a day is removed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Documents"
+                    "Attachments"
                 ],
-                "summary": "Delete document by ID",
+                "summary": "Start a resumable upload",
                 "parameters": [
                     {
                         "type": "string",
@@ -468,33 +591,120 @@ const docTemplate = `{
                         "name": "document_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "1.0.0"
+                        ],
+                        "type": "string",
+                        "description": "tus version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "File size in bytes",
+                        "name": "Upload-Length",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "e.g. filename cmVwb3J0LnBkZg==,visibility cHJpdmF0ZQ==",
+                        "name": "Upload-Metadata",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the upload"
+                            },
+                            "Upload-Expires": {
+                                "type": "string",
+                                "description": "When the upload is removed unless another chunk arrives"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {}
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {}
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
             },
-            "patch": {
-                "consumes": [
-                    "application/json"
+            "options": {
+                "description": "tus 1.0 discovery, lists the supported versions, extensions and the largest upload.",
+                "tags": [
+                    "Attachments"
                 ],
-                "produces": [
-                    "application/json"
+                "summary": "Describe the resumable upload endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "headers": {
+                            "Tus-Extension": {
+                                "type": "string",
+                                "description": "Supported tus extensions"
+                            },
+                            "Tus-Max-Size": {
+                                "type": "integer",
+                                "description": "Largest upload in bytes"
+                            },
+                            "Tus-Version": {
+                                "type": "string",
+                                "description": "Supported tus versions"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/attachments/{document_id}/tus/{upload_id}": {
+            "head": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "tus 1.0 offset query, only the user who started the upload may resume it.",
                 "tags": [
-                    "Documents"
+                    "Attachments"
                 ],
-                "summary": "Update document by ID",
+                "summary": "Get the offset of a resumable upload",
                 "parameters": [
                     {
                         "type": "string",
@@ -504,28 +714,152 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated document data",
-                        "name": "document",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.UpdateDocumentDTO"
-                        }
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "upload_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "1.0.0"
+                        ],
+                        "type": "string",
+                        "description": "tus version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "headers": {
+                            "Upload-Expires": {
+                                "type": "string",
+                                "description": "When the upload is removed unless another chunk arrives"
+                            },
+                            "Upload-Length": {
+                                "type": "integer",
+                                "description": "File size in bytes"
+                            },
+                            "Upload-Offset": {
+                                "type": "integer",
+                                "description": "Bytes received so far"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {}
                     },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {}
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "tus 1.0 PATCH. Upload-Offset must be the offset of the upload. Once the last byte arrives the file is checked like CreateAttachments and becomes an attachment of the document.\nWhen that fails the upload stays one byte short, so resuming it sends the last byte again and tries once more.",
+                "consumes": [
+                    "application/offset+octet-stream"
+                ],
+                "tags": [
+                    "Attachments"
+                ],
+                "summary": "Send a chunk of a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload ID",
+                        "name": "upload_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "1.0.0"
+                        ],
+                        "type": "string",
+                        "description": "tus version",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of the chunk in bytes",
+                        "name": "Upload-Offset",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "headers": {
+                            "Upload-Expires": {
+                                "type": "string",
+                                "description": "When the upload is removed unless another chunk arrives"
+                            },
+                            "Upload-Offset": {
+                                "type": "integer",
+                                "description": "Bytes received so far"
+                            }
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {}
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {}
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {}
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {}
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -533,18 +867,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
-            "get": {
+        "/attachments/{document_id}/uploads": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Each file gets a presigned PUT URL valid for an hour. Upload the file with the returned headers, then complete the upload. Uploads that are not completed in time are removed.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Attachments"
+                ],
+                "summary": "Start uploading files straight to storage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Files to upload",
+                        "name": "uploads",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateAttachmentUploadsDTO"
+                        }
+                    }
                 ],
-                "summary": "Get all users",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "allOf": [
                                 {},
@@ -554,7 +915,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/dtos.UserDTO"
+                                                "$ref": "#/definitions/dtos.AttachmentUploadDTO"
                                             }
                                         }
                                     }
@@ -562,12 +923,26 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            },
+            }
+        },
+        "/auth/login": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -576,24 +951,141 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentication"
                 ],
-                "summary": "Create a new user",
+                "summary": "Log in user",
                 "parameters": [
                     {
-                        "description": "User data",
-                        "name": "user",
+                        "description": "Login credentials",
+                        "name": "loginUserDTO",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dtos.CreateUserDTO"
+                            "$ref": "#/definitions/dtos.LoginUserDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LoginResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Get current user profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.UserDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {}
+                    }
+                }
+            }
+        },
+        "/budgets": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Get budgets of published documents with their totals",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sgcu",
+                            "sccu"
+                        ],
+                        "type": "string",
+                        "description": "Organization",
+                        "name": "organization",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Fiscal year in the Buddhist era, e.g. 2567",
+                        "name": "fiscal_year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.PaginationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -606,20 +1098,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user_id}": {
+        "/budgets/{document_id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Budgets"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get the budget of a published document with every line item",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Document ID",
+                        "name": "document_id",
                         "in": "path",
                         "required": true
                     }
@@ -634,7 +1126,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dtos.UserDTO"
+                                            "$ref": "#/definitions/dtos.BudgetDTO"
                                         }
                                     }
                                 }
@@ -652,6 +1144,12 @@ const docTemplate = `{
                 }
             },
             "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Totals are computed from the line items, declared totals are checked against them.",
                 "consumes": [
                     "application/json"
                 ],
@@ -659,36 +1157,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Budgets"
                 ],
-                "summary": "Update user by ID",
+                "summary": "Create or replace the budget of a BUDGET document",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Document ID",
+                        "name": "document_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated user data",
-                        "name": "user",
+                        "description": "Budget data",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dtos.UpdateUserDTO"
+                            "$ref": "#/definitions/dtos.UpdateBudgetDTO"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {}
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.BudgetDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {}
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {}
@@ -700,18 +1214,23 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Budgets"
                 ],
-                "summary": "Delete user by ID",
+                "summary": "Delete the budget of a document, the document itself is kept",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
+                        "description": "Document ID",
+                        "name": "document_id",
                         "in": "path",
                         "required": true
                     }
@@ -720,6 +1239,14 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {}
@@ -730,144 +1257,4448 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dtos.AttachmentDTO": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "document_id": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "role_id": {
-                    "description": "role_id จะเอาไว้ให้ client ดูว่าไฟล์นี้มาจาก org อะไร sgcu or sucu",
-                    "type": "string"
-                },
-                "type_id": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
+        },
+        "/budgets/{document_id}/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The first row names the columns: category, description, planned, actual. Only category and planned are required.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Replace the line items of a budget from a CSV or XLSX file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Fiscal year in the Buddhist era, e.g. 2567",
+                        "name": "fiscal_year",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "THB",
+                            "USD",
+                            "EUR"
+                        ],
+                        "type": "string",
+                        "description": "Currency",
+                        "name": "currency",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Declared total of the planned amounts",
+                        "name": "total_planned",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Declared total of the actual amounts",
+                        "name": "total_actual",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.BudgetDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
                 }
             }
         },
-        "dtos.CreateDocumentDTO": {
-            "type": "object",
-            "required": [
+        "/budgets/{document_id}/summary": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Get category totals and planned-vs-actual variance of a budget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.BudgetSummaryDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/datasets/{document_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Datasets"
+                ],
+                "summary": "Get the dataset of a published document with every series and point",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DatasetDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Datasets"
+                ],
+                "summary": "Create or replace the dataset of a STATISTIC document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dataset data",
+                        "name": "dataset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateDatasetDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DatasetDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Datasets"
+                ],
+                "summary": "Delete the dataset of a document, the document itself is kept",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/datasets/{document_id}/aggregate": {
+            "get": {
+                "description": "Without group_by every series is reduced to a single group.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Datasets"
+                ],
+                "summary": "Group the points of a dataset by label or dimensions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields, label or dimension names",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sum",
+                            "avg",
+                            "min",
+                            "max",
+                            "count"
+                        ],
+                        "type": "string",
+                        "default": "sum",
+                        "description": "Aggregate of each group",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only these series",
+                        "name": "series",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only points with this value, as name:value",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DatasetAggregateDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/datasets/{document_id}/chart": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Datasets"
+                ],
+                "summary": "Get a dataset as chart data, one value per label and series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "label",
+                        "description": "Field giving the labels, label or a dimension name",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sum",
+                            "avg",
+                            "min",
+                            "max",
+                            "count"
+                        ],
+                        "type": "string",
+                        "default": "sum",
+                        "description": "Aggregate of the points sharing a label",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only these series",
+                        "name": "series",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only points with this value, as name:value",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DatasetChartDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/datasets/{document_id}/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "One point per row. The first row names the columns: value is required, series, label and unit are optional and every other column is a dimension.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Datasets"
+                ],
+                "summary": "Replace the dataset of a STATISTIC document from a CSV or XLSX file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "value",
+                        "description": "Series name when the file has no series column",
+                        "name": "series",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unit when the file has no unit column",
+                        "name": "unit",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DatasetDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/document-types": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DocumentTypes"
+                ],
+                "summary": "Get all document types in display order",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DocumentTypeDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DocumentTypes"
+                ],
+                "summary": "Create a new document type",
+                "parameters": [
+                    {
+                        "description": "Document type data",
+                        "name": "document_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateDocumentTypeDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DocumentTypeDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/document-types/{type_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DocumentTypes"
+                ],
+                "summary": "Get document type by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document type ID",
+                        "name": "type_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DocumentTypeDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DocumentTypes"
+                ],
+                "summary": "Delete an unused document type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document type ID",
+                        "name": "type_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DocumentTypes"
+                ],
+                "summary": "Update document type by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document type ID",
+                        "name": "type_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated document type data",
+                        "name": "document_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateDocumentTypeDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get all documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document type ID from /document-types",
+                        "name": "document_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search title and content, results are ranked by relevance",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tag names",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Match any or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Preferred locale, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Only documents readable in this locale",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DocumentDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Create a new document",
+                "parameters": [
+                    {
+                        "description": "Document data",
+                        "name": "document",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateDocumentDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/archive": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get archived documents, those past their expiry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document type ID from /document-types",
+                        "name": "document_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search title and content, results are ranked by relevance",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tag names",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Match any or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Preferred locale, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Only documents readable in this locale",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DocumentDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Every document is authorized on its own and gets its own result. Publish approves a pending document and archive takes a published one.\nIn atomic mode one failure leaves every document untouched, in best_effort mode the others are still applied.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Apply one operation to many documents",
+                "parameters": [
+                    {
+                        "description": "Documents and operation",
+                        "name": "bulk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.BulkDocumentsDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.BulkDocumentsResultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/featured": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get featured documents, global slots first then those of the organization",
+                "parameters": [
+                    {
+                        "enum": [
+                            "sgcu",
+                            "sccu"
+                        ],
+                        "type": "string",
+                        "description": "Organization",
+                        "name": "organization",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Preferred locale, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.FeaturedDocumentDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/featured/{scope}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get every featured slot of a scope, expired ones included",
+                "parameters": [
+                    {
+                        "enum": [
+                            "global",
+                            "sgcu",
+                            "sccu"
+                        ],
+                        "type": "string",
+                        "description": "Scope",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.FeaturedDocumentDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Replace the featured slots of a scope",
+                "parameters": [
+                    {
+                        "enum": [
+                            "global",
+                            "sgcu",
+                            "sccu"
+                        ],
+                        "type": "string",
+                        "description": "Scope",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Featured documents in display order",
+                        "name": "slots",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateFeaturedDocumentsDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/featured/{scope}/{document_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Unpin a document from a scope",
+                "parameters": [
+                    {
+                        "enum": [
+                            "global",
+                            "sgcu",
+                            "sccu"
+                        ],
+                        "type": "string",
+                        "description": "Scope",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/reviews/pending": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get documents waiting for review in the reviewer's organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.PaginationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/role/{role}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get documents by user role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User role",
+                        "name": "role_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document type ID from /document-types",
+                        "name": "document_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search title and content, results are ranked by relevance",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tag names",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Match any or all of the tags",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Preferred locale, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Only documents readable in this locale",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DocumentDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/slug/{slug}": {
+            "get": {
+                "description": "A former slug answers with a permanent redirect to the current one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get document by its permalink",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Preferred locale, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DocumentDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get deleted documents of the superadmin's organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.PaginationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get document by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Preferred locale, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DocumentDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Delete document by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Only the author or a superadmin of the author's organization may edit, the author not while the document is pending review.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Update document by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated document data",
+                        "name": "document",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateDocumentDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Approve a pending document and publish it",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/expiry": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Set or remove the expiry of a published or archived document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New expiry, null to never expire",
+                        "name": "expiry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateDocumentExpiryDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/links": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get the links and backlinks of a document, including unpublished documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DocumentLinksDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Each linked document shows the link as a backlink: amends as amended_by, supersedes as superseded_by, follow_up as followed_up_by.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Replace the links going out of a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Document links",
+                        "name": "links",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateDocumentLinksDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/purge": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Permanently delete a document in trash with its attachments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Reject a pending document with a comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for rejection",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ReviewDocumentDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/request-changes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Send a pending document back to its author for changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Requested changes",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ReviewDocumentDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Restore a deleted document and the attachments deleted with it",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get review history of a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DocumentReviewDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get revision history of a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DocumentRevisionDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/revisions/diff": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Diff two revisions of a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Base revision",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target revision",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DocumentRevisionDiffDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/revisions/{rev}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get a single revision of a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DocumentRevisionDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/revisions/{rev}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Restore a document to an earlier revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/slug": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The previous slug keeps redirecting to the document.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Change the permalink of a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New slug",
+                        "name": "slug",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateDocumentSlugDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/submit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Submit a document for review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/tags": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Replace the tags of a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag IDs",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateDocumentTagsDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/translations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get every translation of a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DocumentTranslationDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/documents/{document_id}/translations/{locale}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Create or replace the translation of a document in a locale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Locale",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Translated title, content and excerpt",
+                        "name": "translation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateDocumentTranslationDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Delete the translation of a document in a locale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "th",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Locale",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/files/{bucket}/{key}": {
+            "get": {
+                "description": "Only served by the local and memory storage drivers. Private files need the query of a presigned URL.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Download a stored file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Object key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiry of a presigned URL, unix seconds",
+                        "name": "expires",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of a presigned URL",
+                        "name": "signature",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "description": "Only served by the local and memory storage drivers. The Content-Type header must be the one the URL was signed for.",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Files"
+                ],
+                "summary": "Upload a file to a presigned URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucket",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Object key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiry of the presigned URL, unix seconds",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether the file is public",
+                        "name": "public",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of the presigned URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Get all tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.TagDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Create a new tag",
+                "parameters": [
+                    {
+                        "description": "Tag data",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateTagDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.TagDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/tags/usage": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Count published documents per tag and organization",
+                "parameters": [
+                    {
+                        "enum": [
+                            "sgcu",
+                            "sccu"
+                        ],
+                        "type": "string",
+                        "description": "Organization",
+                        "name": "organization",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.TagUsageDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/tags/{tag_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Rename a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated tag data",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateTagDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Delete a tag and remove it from every document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.UserDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateUserDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/users/{user_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {},
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.UserDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated user data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateUserDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dtos.AttachmentDTO": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "hash": {
+                    "description": "hex SHA-256, empty for files uploaded before hashing",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role_id": {
+                    "description": "role_id จะเอาไว้ให้ client ดูว่าไฟล์นี้มาจาก org อะไร sgcu or sucu",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "bytes, 0 for files uploaded before sizes were kept",
+                    "type": "integer"
+                },
+                "type_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variant_status": {
+                    "description": "variant_status: none, pending, ready, failed",
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.AttachmentVariantDTO"
+                    }
+                },
+                "visibility": {
+                    "description": "visibility: public, private",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.AttachmentURLDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "empty for the stable URL of a public attachment",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "visibility: public, private",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.AttachmentUploadDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "headers": {
+                    "description": "the client must send these with the upload",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.AttachmentUploadFileDTO": {
+            "type": "object",
+            "required": [
+                "name",
+                "size"
+            ],
+            "properties": {
+                "content_type": {
+                    "description": "guessed from the extension when empty",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "size": {
+                    "description": "bytes",
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.AttachmentVariantDTO": {
+            "type": "object",
+            "properties": {
+                "format": {
+                    "description": "format: jpeg, png, webp",
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.BudgetCategoryDTO": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "planned": {
+                    "type": "string"
+                },
+                "variance": {
+                    "type": "string"
+                },
+                "variance_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.BudgetDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "currency: THB, USD, EUR",
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "fiscal_year": {
+                    "description": "Buddhist era, e.g. 2567",
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BudgetItemDTO"
+                    }
+                },
+                "organization": {
+                    "description": "organization: sgcu, sccu",
+                    "type": "string"
+                },
+                "total_actual": {
+                    "type": "string"
+                },
+                "total_planned": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "variance": {
+                    "description": "total actual minus total planned",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.BudgetItemDTO": {
+            "type": "object",
+            "required": [
+                "category",
+                "planned"
+            ],
+            "properties": {
+                "actual": {
+                    "description": "0 when empty",
+                    "type": "string"
+                },
+                "category": {
+                    "description": "e.g. activities, equipment",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "planned": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.BudgetSummaryDTO": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "description": "in order of first appearance",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BudgetCategoryDTO"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "fiscal_year": {
+                    "type": "integer"
+                },
+                "organization": {
+                    "type": "string"
+                },
+                "total_actual": {
+                    "type": "string"
+                },
+                "total_planned": {
+                    "type": "string"
+                },
+                "variance": {
+                    "type": "string"
+                },
+                "variance_percent": {
+                    "description": "variance relative to planned, null when nothing was planned",
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.BulkDocumentResultDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "HTTP status the item would get on its own",
+                    "type": "integer"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.BulkDocumentsDTO": {
+            "type": "object",
+            "required": [
+                "document_ids",
+                "operation"
+            ],
+            "properties": {
+                "document_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mode": {
+                    "description": "mode: atomic, best_effort; defaults to atomic",
+                    "type": "string"
+                },
+                "operation": {
+                    "description": "operation: delete, restore, publish, archive, change_type, add_tag",
+                    "type": "string"
+                },
+                "tag_ids": {
+                    "description": "tags for add_tag",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type_id": {
+                    "description": "new type for change_type",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.BulkDocumentsResultDTO": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "results": {
+                    "description": "in the order of document_ids",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BulkDocumentResultDTO"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CreateAttachmentUploadsDTO": {
+            "type": "object",
+            "required": [
+                "files"
+            ],
+            "properties": {
+                "files": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.AttachmentUploadFileDTO"
+                    }
+                },
+                "visibility": {
+                    "description": "visibility: public, private",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreateDocumentDTO": {
+            "type": "object",
+            "required": [
                 "content",
                 "title",
                 "type_id",
                 "user_id"
             ],
             "properties": {
-                "banner": {
+                "banner": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "cover": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "archived once passed, never when empty",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "format of content: markdown, html; defaults to markdown",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "locale: th, en; defaults to th",
+                    "type": "string"
+                },
+                "slug": {
+                    "description": "generated from the title when empty",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreateDocumentTypeDTO": {
+            "type": "object",
+            "required": [
+                "id",
+                "name_en",
+                "name_th"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "letters, digits and underscores",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "name_en": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "name_th": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "requires_review": {
+                    "type": "boolean"
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CreateTagDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "e.g. scholarship, sports day, fy2567",
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dtos.CreateUserDTO": {
+            "type": "object",
+            "required": [
+                "first_name",
+                "id",
+                "last_name",
+                "password"
+            ],
+            "properties": {
+                "first_name": {
+                    "description": "user's first name",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "student id",
+                    "type": "string"
+                },
+                "last_name": {
+                    "description": "user's last name",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "user's password",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "role: sgcu-admin, sgcu-superadmin, sccu-admin, sccu-superadmin",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DatasetAggregateDTO": {
+            "type": "object",
+            "properties": {
+                "aggregate": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DatasetGroupedSeriesDTO"
+                    }
+                }
+            }
+        },
+        "dtos.DatasetChartDTO": {
+            "type": "object",
+            "properties": {
+                "aggregate": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DatasetChartSeriesDTO"
+                    }
+                }
+            }
+        },
+        "dtos.DatasetChartSeriesDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "aligned with labels, null where the series has no point",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DatasetDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "dimensions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DatasetSeriesDTO"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DatasetGroupDTO": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "description": "grouped name -\u003e value",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "points": {
+                    "description": "points in the group",
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.DatasetGroupedSeriesDTO": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "description": "in order of first appearance",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DatasetGroupDTO"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DatasetPointDTO": {
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "dimensions": {
+                    "description": "dimension name -\u003e value, declared by the dataset",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "label": {
+                    "description": "e.g. 2567, Faculty of Engineering",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.DatasetSeriesDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "e.g. new students",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DatasetPointDTO"
+                    }
+                },
+                "unit": {
+                    "description": "e.g. students, THB, %",
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "dtos.DocumentDTO": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
+                "author": {
+                    "$ref": "#/definitions/dtos.UserDTO"
+                },
+                "available_locales": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "backlinks": {
+                    "description": "documents linking to this one",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DocumentLinkDTO"
+                    }
+                },
+                "banner": {
+                    "type": "string"
+                },
+                "content": {
+                    "description": "sanitized HTML",
+                    "type": "string"
+                },
+                "cover": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "docs": {
+                    "description": "docs file eg. pdf xlsx pptx",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.AttachmentDTO"
+                    }
+                },
+                "excerpt": {
+                    "description": "plain text, generated from the content unless the author wrote one",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "images": {
+                    "description": "images file eg. jpeg jpg png",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.AttachmentDTO"
+                    }
+                },
+                "links": {
+                    "description": "documents this one links to",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DocumentLinkDTO"
+                    }
+                },
+                "locale": {
+                    "description": "locale the title, content and excerpt are in",
+                    "type": "string"
+                },
+                "reading_time": {
+                    "description": "minutes",
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "status: draft, pending, published, rejected, changes_requested, archived",
+                    "type": "string"
+                },
+                "superseded_by": {
+                    "description": "ID of the newest document superseding this one",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TagDTO"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DocumentFieldDiffDTO": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "field: title, banner, cover",
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DocumentLineDiffDTO": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "description": "op: equal, insert, delete",
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DocumentLinkDTO": {
+            "type": "object",
+            "properties": {
+                "document": {
+                    "$ref": "#/definitions/dtos.LinkedDocumentDTO"
+                },
+                "type": {
+                    "description": "type: amends, supersedes, related, follow_up; for backlinks amended_by, superseded_by, related, followed_up_by",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DocumentLinkTargetDTO": {
+            "type": "object",
+            "required": [
+                "document_id",
+                "type"
+            ],
+            "properties": {
+                "document_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "type: amends, supersedes, related, follow_up",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DocumentLinksDTO": {
+            "type": "object",
+            "properties": {
+                "backlinks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DocumentLinkDTO"
+                    }
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DocumentLinkDTO"
+                    }
+                }
+            }
+        },
+        "dtos.DocumentReviewDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "action: submit, approve, reject, request_changes",
+                    "type": "string"
+                },
+                "actor": {
+                    "$ref": "#/definitions/dtos.UserDTO"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "description": "status before the transition",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "description": "status after the transition",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DocumentRevisionDTO": {
+            "type": "object",
+            "properties": {
+                "banner": {
+                    "type": "string"
+                },
+                "content": {
+                    "description": "omitted in revision listings",
+                    "type": "string"
+                },
+                "cover": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "editor": {
+                    "$ref": "#/definitions/dtos.UserDTO"
+                },
+                "format": {
+                    "description": "format of source: markdown, html",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "restored_from": {
+                    "description": "revision this one was restored from",
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "content as written, omitted in revision listings",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DocumentRevisionDiffDTO": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "line-level diff of the content",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DocumentLineDiffDTO"
+                    }
+                },
+                "document_id": {
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "changed fields only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DocumentFieldDiffDTO"
+                    }
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.DocumentTranslationDTO": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "sanitized HTML",
                     "type": "string"
                 },
-                "content": {
+                "created_at": {
                     "type": "string"
                 },
-                "cover": {
+                "excerpt": {
                     "type": "string"
                 },
-                "id": {
+                "format": {
+                    "description": "format of source: markdown, html",
                     "type": "string"
                 },
-                "title": {
+                "locale": {
+                    "description": "locale: th, en",
                     "type": "string"
                 },
-                "type_id": {
+                "reading_time": {
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "content as written",
                     "type": "string"
                 },
-                "user_id": {
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "dtos.CreateUserDTO": {
+        "dtos.DocumentTypeDTO": {
             "type": "object",
-            "required": [
-                "first_name",
-                "id",
-                "last_name",
-                "password"
-            ],
             "properties": {
-                "first_name": {
-                    "description": "user's first name",
+                "core": {
+                    "description": "built-in types cannot be deleted",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
                     "type": "string"
                 },
                 "id": {
-                    "description": "student id",
+                    "description": "e.g. announcement, meeting_minutes",
                     "type": "string"
                 },
-                "last_name": {
-                    "description": "user's last name",
+                "name_en": {
                     "type": "string"
                 },
-                "password": {
-                    "description": "user's password",
+                "name_th": {
                     "type": "string"
                 },
-                "role": {
-                    "description": "role: sgcu-admin, sgcu-superadmin, sccu-admin, sccu-superadmin",
+                "requires_review": {
+                    "description": "documents of ordinary admins wait for approval",
+                    "type": "boolean"
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "dtos.DocumentDTO": {
+        "dtos.FeaturedDocumentDTO": {
             "type": "object",
             "properties": {
-                "author": {
-                    "$ref": "#/definitions/dtos.UserDTO"
+                "document": {
+                    "description": "same fields as a listing item",
+                    "type": "object",
+                    "additionalProperties": true
                 },
-                "banner": {
+                "expires_at": {
                     "type": "string"
                 },
-                "content": {
-                    "type": "string"
+                "position": {
+                    "description": "starts at 1 within a scope",
+                    "type": "integer"
                 },
-                "cover": {
+                "scope": {
+                    "description": "scope: global, sgcu, sccu",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.FeaturedSlotDTO": {
+            "type": "object",
+            "required": [
+                "document_id"
+            ],
+            "properties": {
+                "document_id": {
                     "type": "string"
                 },
+                "expires_at": {
+                    "description": "never expires when empty",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.LinkedDocumentDTO": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "docs": {
-                    "description": "docs file eg. pdf xlsx pptx",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dtos.AttachmentDTO"
-                    }
-                },
                 "id": {
                     "type": "string"
                 },
-                "images": {
-                    "description": "images file eg. jpeg jpg png",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dtos.AttachmentDTO"
-                    }
-                },
-                "title": {
+                "slug": {
                     "type": "string"
                 },
-                "type_id": {
+                "status": {
                     "type": "string"
                 },
-                "updated_at": {
+                "title": {
                     "type": "string"
                 },
-                "user_id": {
+                "type_id": {
                     "type": "string"
                 }
             }
@@ -893,6 +5724,178 @@ const docTemplate = `{
                 }
             }
         },
+        "dtos.PaginationResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "limit": {
+                    "description": "page size",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "current page",
+                    "type": "string"
+                },
+                "total_pages": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.ReviewDocumentDTO": {
+            "type": "object",
+            "required": [
+                "comment"
+            ],
+            "properties": {
+                "comment": {
+                    "description": "reason shown to the author",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SharedObjectDTO": {
+            "type": "object",
+            "properties": {
+                "hash": {
+                    "type": "string"
+                },
+                "object_key": {
+                    "type": "string"
+                },
+                "references": {
+                    "type": "integer"
+                },
+                "saved_bytes": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "description": "visibility: public, private",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.StorageSavingsDTO": {
+            "type": "object",
+            "properties": {
+                "most_shared": {
+                    "description": "objects saving the most bytes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.SharedObjectDTO"
+                    }
+                },
+                "objects": {
+                    "description": "stored objects",
+                    "type": "integer"
+                },
+                "referenced_bytes": {
+                    "description": "bytes the attachments would take without sharing",
+                    "type": "integer"
+                },
+                "references": {
+                    "description": "attachments referring to them",
+                    "type": "integer"
+                },
+                "saved_bytes": {
+                    "type": "integer"
+                },
+                "stored_bytes": {
+                    "description": "bytes in storage",
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.TagDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.TagUsageDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organizations": {
+                    "description": "organization: sccu, sgcu -\u003e published documents",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "description": "published documents carrying the tag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.UpdateBudgetDTO": {
+            "type": "object",
+            "required": [
+                "fiscal_year"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "THB when empty",
+                    "type": "string"
+                },
+                "fiscal_year": {
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "in display order, replaces every item",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BudgetItemDTO"
+                    }
+                },
+                "total_actual": {
+                    "description": "must equal the sum of the items when given",
+                    "type": "string"
+                },
+                "total_planned": {
+                    "description": "must equal the sum of the items when given",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.UpdateDatasetDTO": {
+            "type": "object",
+            "properties": {
+                "dimensions": {
+                    "description": "e.g. faculty, gender",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "series": {
+                    "description": "in display order, replaces every series",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DatasetSeriesDTO"
+                    }
+                }
+            }
+        },
         "dtos.UpdateDocumentDTO": {
             "type": "object",
             "properties": {
@@ -905,8 +5908,132 @@ const docTemplate = `{
                 "cover": {
                     "type": "string"
                 },
+                "excerpt": {
+                    "type": "string"
+                },
+                "format": {
+                    "description": "format of content: markdown, html; keeps the current one when empty",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.UpdateDocumentExpiryDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "null removes the expiry and brings an archived document back",
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.UpdateDocumentLinksDTO": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "description": "replaces every link going out of the document",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DocumentLinkTargetDTO"
+                    }
+                }
+            }
+        },
+        "dtos.UpdateDocumentSlugDTO": {
+            "type": "object",
+            "required": [
+                "slug"
+            ],
+            "properties": {
+                "slug": {
+                    "description": "normalized with the same rules as generated slugs",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dtos.UpdateDocumentTagsDTO": {
+            "type": "object",
+            "properties": {
+                "tag_ids": {
+                    "description": "replaces every tag of the document, empty clears them",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.UpdateDocumentTranslationDTO": {
+            "type": "object",
+            "required": [
+                "content",
+                "title"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
+                "format": {
+                    "description": "format of content: markdown, html; defaults to markdown",
+                    "type": "string"
+                },
                 "title": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dtos.UpdateDocumentTypeDTO": {
+            "type": "object",
+            "properties": {
+                "description": {
                     "type": "string"
+                },
+                "name_en": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "name_th": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "requires_review": {
+                    "type": "boolean"
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.UpdateFeaturedDocumentsDTO": {
+            "type": "object",
+            "properties": {
+                "slots": {
+                    "description": "in display order, replaces every slot of the scope",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.FeaturedSlotDTO"
+                    }
+                }
+            }
+        },
+        "dtos.UpdateTagDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
                 }
             }
         },
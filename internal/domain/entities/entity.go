@@ -30,29 +30,198 @@ type Role struct {
 }
 
 type DocumentType struct {
-	ID        string         `gorm:"primaryKey;type:varchar(100)"`
-	CreatedAt time.Time      ``
-	UpdatedAt time.Time      ``
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID             string         `gorm:"primaryKey;type:varchar(100)"` // e.g. ANNOUNCEMENT, MEETING_MINUTES
+	NameTH         string         `gorm:"type:varchar(255);not null;default:''"`
+	NameEN         string         `gorm:"type:varchar(255);not null;default:''"`
+	Description    *string        `gorm:"type:text"`
+	SortOrder      int            `gorm:"not null;default:0"`
+	RequiresReview bool           `gorm:"not null;default:false"` // documents of ordinary admins wait for approval
+	CreatedAt      time.Time      ``
+	UpdatedAt      time.Time      ``
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
 
 	Documents []Document `gorm:"foreignKey:TypeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
 
 type Document struct {
-	ID        string         `gorm:"primaryKey;type:varchar(100)"`
-	Title     string         `gorm:"type:varchar(255);not null"`
-	Content   string         `gorm:"type:text;not null"`
-	Banner    *string        `gorm:"type:varchar(255)"`
-	Cover     *string        `gorm:"type:varchar(255)"`
-	UserID    string         `gorm:"type:varchar(10);not null"`
-	TypeID    string         `gorm:"type:varchar(100);not null"`
-	CreatedAt time.Time      ``
-	UpdatedAt time.Time      ``
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID          string         `gorm:"primaryKey;type:varchar(100)"`
+	Title       string         `gorm:"type:varchar(255);not null"`
+	Content     string         `gorm:"type:text;not null"`                     // sanitized HTML, see utils.RenderContent
+	Source      string         `gorm:"type:text;not null;default:''"`          // content as written by the author
+	Format      string         `gorm:"type:varchar(20);not null;default:html"` // format of source: markdown, html
+	Excerpt     *string        `gorm:"type:text"`                              // set by the author, AutoExcerpt is shown otherwise
+	AutoExcerpt string         `gorm:"type:text;not null;default:''"`          // start of the plain text of content
+	ReadingTime int            `gorm:"not null;default:0"`                     // minutes, see utils.ReadingTime
+	Locale      string         `gorm:"type:varchar(10);not null;default:th"`   // locale of title, content and excerpt
+	Banner      *string        `gorm:"type:varchar(255)"`
+	Cover       *string        `gorm:"type:varchar(255)"`
+	UserID      string         `gorm:"type:varchar(10);not null"`
+	TypeID      string         `gorm:"type:varchar(100);not null"`
+	Slug        string         `gorm:"type:varchar(255);uniqueIndex"`                     // permalink, see utils.Slugify
+	Status      string         `gorm:"type:varchar(20);not null;default:PUBLISHED;index"` // status: DRAFT, PENDING, PUBLISHED, REJECTED, CHANGES_REQUESTED, ARCHIVED
+	ExpiresAt   *time.Time     `gorm:"index"`                                             // archived once passed, never when empty
+	ArchivedAt  *time.Time     ``                                                         // set by the archive job
+	SearchTitle string         `gorm:"type:text;not null;default:''"`                     // search terms of the title, see utils.SearchText
+	SearchBody  string         `gorm:"type:text;not null;default:''"`                     // search terms of the content
+	CreatedAt   time.Time      ``
+	UpdatedAt   time.Time      ``
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+
+	Author       User                  `gorm:"foreignKey:UserID"`
+	Type         DocumentType          `gorm:"foreignKey:TypeID"`
+	Attachments  []Attachment          `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Reviews      []DocumentReview      `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Revisions    []DocumentRevision    `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Tags         []Tag                 `gorm:"many2many:document_tags;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	OldSlugs     []DocumentSlug        `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Translations []DocumentTranslation `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Links        []DocumentLink        `gorm:"foreignKey:SourceID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Backlinks    []DocumentLink        `gorm:"foreignKey:TargetID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
 
-	Author      User         `gorm:"foreignKey:UserID"`
-	Type        DocumentType `gorm:"foreignKey:TypeID"`
-	Attachments []Attachment `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+// DocumentTranslation is the title, content and excerpt of a document in a locale other than its own
+type DocumentTranslation struct {
+	DocumentID  string    `gorm:"primaryKey;type:varchar(100)"`
+	Locale      string    `gorm:"primaryKey;type:varchar(10)"` // locale: th, en
+	Title       string    `gorm:"type:varchar(255);not null"`
+	Content     string    `gorm:"type:text;not null"` // same pipeline as Document.Content
+	Source      string    `gorm:"type:text;not null;default:''"`
+	Format      string    `gorm:"type:varchar(20);not null;default:html"`
+	Excerpt     *string   `gorm:"type:text"`
+	AutoExcerpt string    `gorm:"type:text;not null;default:''"`
+	ReadingTime int       `gorm:"not null;default:0"`
+	CreatedAt   time.Time ``
+	UpdatedAt   time.Time ``
+}
+
+// DocumentSlug is a former slug of a document, kept so that old permalinks keep working
+type DocumentSlug struct {
+	Slug       string    `gorm:"primaryKey;type:varchar(255)"`
+	DocumentID string    `gorm:"type:varchar(100);not null;index"`
+	CreatedAt  time.Time `` // when the document stopped using this slug
+}
+
+// DocumentReview is an immutable record of a single review state transition
+type DocumentReview struct {
+	ID         string    `gorm:"primaryKey;type:varchar(100)"`
+	DocumentID string    `gorm:"type:varchar(100);not null;index"`
+	ActorID    string    `gorm:"type:varchar(10);not null"`
+	Action     string    `gorm:"type:varchar(20);not null"` // action: SUBMIT, APPROVE, REJECT, REQUEST_CHANGES
+	FromStatus string    `gorm:"type:varchar(20);not null"`
+	ToStatus   string    `gorm:"type:varchar(20);not null"`
+	Comment    *string   `gorm:"type:text"`
+	CreatedAt  time.Time ``
+
+	Actor User `gorm:"foreignKey:ActorID"`
+}
+
+// DocumentRevision is an immutable snapshot of a document written on every update
+type DocumentRevision struct {
+	ID           string    `gorm:"primaryKey;type:varchar(100)"`
+	DocumentID   string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_document_revision"`
+	Revision     int       `gorm:"not null;uniqueIndex:idx_document_revision"` // starts at 1 for each document
+	Title        string    `gorm:"type:varchar(255);not null"`
+	Content      string    `gorm:"type:text;not null"`
+	Source       string    `gorm:"type:text;not null;default:''"`
+	Format       string    `gorm:"type:varchar(20);not null;default:html"`
+	Banner       *string   `gorm:"type:varchar(255)"`
+	Cover        *string   `gorm:"type:varchar(255)"`
+	EditorID     string    `gorm:"type:varchar(10);not null"`
+	RestoredFrom *int      `` // revision this snapshot was restored from, if any
+	CreatedAt    time.Time ``
+
+	Editor User `gorm:"foreignKey:EditorID"`
+}
+
+// Tag is a free-form label shared by documents of every organization
+type Tag struct {
+	ID        string    `gorm:"primaryKey;type:varchar(100)"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex"` // normalized by utils.NormalizeTagName
+	CreatedAt time.Time ``
+	UpdatedAt time.Time ``
+}
+
+// DocumentLink is a typed reference from one document to another, the target shows it as a backlink
+type DocumentLink struct {
+	SourceID  string    `gorm:"primaryKey;type:varchar(100)"`
+	TargetID  string    `gorm:"primaryKey;type:varchar(100);index"`
+	Type      string    `gorm:"type:varchar(20);not null"` // type: AMENDS, SUPERSEDES, RELATED, FOLLOW_UP
+	CreatedBy string    `gorm:"type:varchar(10);not null"`
+	CreatedAt time.Time ``
+
+	Source Document `gorm:"foreignKey:SourceID"`
+	Target Document `gorm:"foreignKey:TargetID"`
+}
+
+// Budget is the structured data of a document of type BUDGET, amounts are in minor units of Currency
+type Budget struct {
+	DocumentID   string    `gorm:"primaryKey;type:varchar(100)"`
+	FiscalYear   int       `gorm:"not null;index"`                       // Buddhist era, e.g. 2567
+	Organization string    `gorm:"type:varchar(10);not null;index"`      // organization of the author: SGCU, SCCU
+	Currency     string    `gorm:"type:varchar(3);not null;default:THB"` // currency: THB, USD, EUR
+	TotalPlanned int64     `gorm:"not null;default:0"`                   // sum of the planned amounts of the items
+	TotalActual  int64     `gorm:"not null;default:0"`                   // sum of the actual amounts of the items
+	UpdatedBy    string    `gorm:"type:varchar(10);not null"`
+	CreatedAt    time.Time ``
+	UpdatedAt    time.Time ``
+
+	Items    []BudgetItem `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Document Document     `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// BudgetItem is one line of a budget
+type BudgetItem struct {
+	DocumentID  string `gorm:"primaryKey;type:varchar(100)"`
+	Position    int    `gorm:"primaryKey"` // starts at 1
+	Category    string `gorm:"type:varchar(100);not null"`
+	Description string `gorm:"type:varchar(255);not null;default:''"`
+	Planned     int64  `gorm:"not null;default:0"`
+	Actual      int64  `gorm:"not null;default:0"`
+}
+
+// Dataset is the structured data of a document of type STATISTIC
+type Dataset struct {
+	DocumentID string    `gorm:"primaryKey;type:varchar(100)"`
+	Dimensions []string  `gorm:"type:text;not null;serializer:json"` // names the points are broken down by, e.g. faculty, year
+	UpdatedBy  string    `gorm:"type:varchar(10);not null"`
+	CreatedAt  time.Time ``
+	UpdatedAt  time.Time ``
+
+	Series   []DatasetSeries `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Points   []DatasetPoint  `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Document Document        `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// DatasetSeries is a named list of data points measured in one unit
+type DatasetSeries struct {
+	DocumentID string `gorm:"primaryKey;type:varchar(100)"`
+	Position   int    `gorm:"primaryKey"` // starts at 1
+	Name       string `gorm:"type:varchar(100);not null"`
+	Unit       string `gorm:"type:varchar(50);not null;default:''"` // e.g. students, THB, %
+}
+
+// DatasetPoint is one labeled value of a series
+type DatasetPoint struct {
+	DocumentID string            `gorm:"primaryKey;type:varchar(100)"`
+	Series     int               `gorm:"primaryKey"` // position of the series
+	Position   int               `gorm:"primaryKey"` // starts at 1 within a series
+	Label      string            `gorm:"type:varchar(255);not null;default:''"`
+	Value      float64           `gorm:"not null"`
+	Dimensions map[string]string `gorm:"type:text;not null;serializer:json"` // dimension name -> value
+}
+
+// FeaturedDocument pins a document to an ordered slot of a homepage
+type FeaturedDocument struct {
+	ID         string     `gorm:"primaryKey;type:varchar(100)"`
+	Scope      string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_featured_document"` // scope: GLOBAL, SGCU, SCCU
+	DocumentID string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_featured_document"`
+	Position   int        `gorm:"not null"` // starts at 1 within a scope
+	ExpiresAt  *time.Time `gorm:"index"`    // the slot is ignored from then on
+	CreatedBy  string     `gorm:"type:varchar(10);not null"`
+	CreatedAt  time.Time  ``
+	UpdatedAt  time.Time  ``
+
+	Document Document `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
 
 type AttachmentType struct {
@@ -65,14 +234,75 @@ type AttachmentType struct {
 }
 
 type Attachment struct {
-	ID          string         `gorm:"primaryKey;type:varchar(100)"`
-	DisplayName string         `gorm:"type:varchar(255);not null"`
-	DocumentID  string         `gorm:"type:varchar(100);not null"`
-	TypeID      string         `gorm:"type:varchar(100);not null"`
-	CreatedAt   time.Time      ``
-	UpdatedAt   time.Time      ``
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	ID            string         `gorm:"primaryKey;type:varchar(100)"`
+	DisplayName   string         `gorm:"type:varchar(255);not null"`
+	DocumentID    string         `gorm:"type:varchar(100);not null"`
+	TypeID        string         `gorm:"type:varchar(100);not null"`
+	Visibility    string         `gorm:"type:varchar(10);not null;default:PUBLIC"`                    // visibility: PUBLIC, PRIVATE
+	ContentType   string         `gorm:"type:varchar(255);not null;default:application/octet-stream"` // detected from the content
+	VariantStatus string         `gorm:"type:varchar(10);not null;default:NONE;index"`                // variant_status: NONE, PENDING, READY, FAILED
+	State         string         `gorm:"type:varchar(10);not null;default:COMMITTED"`                 // state: PENDING while the file is uploaded, then COMMITTED
+	ObjectKey     string         `gorm:"type:varchar(100);not null;default:'';index"`                 // the stored object, shared with attachments of the same content
+	Hash          string         `gorm:"type:varchar(64);not null;default:''"`                        // hex SHA-256 of the content, empty for files uploaded before hashing
+	Size          int64          `gorm:"not null;default:0"`                                          // bytes
+	CreatedAt     time.Time      ``
+	UpdatedAt     time.Time      ``
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+
+	Document Document            `gorm:"foreignKey:DocumentID"`
+	Type     AttachmentType      `gorm:"foreignKey:TypeID"`
+	Variants []AttachmentVariant `gorm:"foreignKey:AttachmentID"`
+}
+
+// AttachmentVariant is a resized copy of an image attachment, stored next to the original
+type AttachmentVariant struct {
+	ID           string    `gorm:"primaryKey;type:varchar(150)"` // object key, e.g. variants/320w/photo-12345678.jpg.webp
+	AttachmentID string    `gorm:"type:varchar(100);not null;index"`
+	Width        int       `gorm:"not null"`
+	Height       int       `gorm:"not null"`
+	Format       string    `gorm:"type:varchar(10);not null"` // format: jpeg, png, webp
+	Size         int64     `gorm:"not null"`
+	CreatedAt    time.Time ``
+}
+
+// AttachmentUpload is a file a client uploads straight to storage, it becomes an Attachment once completed
+type AttachmentUpload struct {
+	ID          string    `gorm:"primaryKey;type:varchar(20)"`            // e.g. UPL-12345678
+	ObjectKey   string    `gorm:"type:varchar(100);not null;uniqueIndex"` // becomes the attachment ID
+	DisplayName string    `gorm:"type:varchar(255);not null"`
+	DocumentID  string    `gorm:"type:varchar(100);not null;index"`
+	TypeID      string    `gorm:"type:varchar(100);not null"`
+	Visibility  string    `gorm:"type:varchar(10);not null"`
+	Size        int64     `gorm:"not null"` // bytes the client announced
+	ContentType string    `gorm:"type:varchar(255);not null"`
+	CreatedBy   string    `gorm:"type:varchar(10);not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+	CreatedAt   time.Time ``
+
+	Document Document `gorm:"foreignKey:DocumentID"`
+}
+
+// TusUpload is a resumable upload in progress, its bytes stay on the server's disk until the last chunk arrives
+type TusUpload struct {
+	ID          string    `gorm:"primaryKey;type:varchar(20)"` // e.g. TUS-12345678
+	DocumentID  string    `gorm:"type:varchar(100);not null;index"`
+	DisplayName string    `gorm:"type:varchar(255);not null"`
+	Visibility  string    `gorm:"type:varchar(10);not null"`
+	Length      int64     `gorm:"not null"` // bytes the client announced
+	Offset      int64     `gorm:"not null"` // bytes received so far
+	CreatedBy   string    `gorm:"type:varchar(10);not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"` // pushed back by every chunk
+	CreatedAt   time.Time ``
+}
 
-	Document Document       `gorm:"foreignKey:DocumentID"`
-	Type     AttachmentType `gorm:"foreignKey:TypeID"`
+// StoredObject is a file in storage shared by the attachments with the same content and visibility.
+// The object is deleted when the last attachment referring to it is purged.
+type StoredObject struct {
+	ObjectKey  string    `gorm:"primaryKey;type:varchar(100)"`                                    // key of the first attachment uploaded with this content
+	Hash       string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_stored_object_content"` // hex SHA-256
+	Visibility string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_stored_object_content"` // a public and a private attachment never share an object
+	Size       int64     `gorm:"not null"`
+	RefCount   int64     `gorm:"not null"` // attachments referring to the object, trashed ones included
+	CreatedAt  time.Time ``
+	UpdatedAt  time.Time ``
 }
@@ -1,18 +1,40 @@
 package usecases
 
 import (
+	"io"
 	"mime/multipart"
+	"net/url"
 
 	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/apperror"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/s3client"
 )
 
 type AttachmentUsecase interface {
 	// client side
-	GetAllAttachments() (*[]dtos.AttachmentDTO, *apperror.AppError)
+	GetAllAttachments(getAttachmentsDTO *dtos.GetAllAttachmentsDTO) (*dtos.PaginationResponse, *apperror.AppError)
 
 	// back office
-	GetAllAttachmentsByRole(req dtos.UserDTO) (*[]dtos.AttachmentDTO, *apperror.AppError)
-	CreateAttachments(documentID string, files map[string][]*multipart.FileHeader) *apperror.AppError
+	GetAllAttachmentsByRole(req *dtos.UserDTO, getAttachmentsDTO *dtos.GetAllAttachmentsByRoleDTO) (*dtos.PaginationResponse, *apperror.AppError)
+	CreateAttachments(req *dtos.UserDTO, documentID, visibility string, files map[string][]*multipart.FileHeader) *apperror.AppError
+	GetAttachmentURL(req *dtos.UserDTO, ID string, width int, format string) (*dtos.AttachmentURLDTO, *apperror.AppError)
 	DeleteAttachment(ID string) *apperror.AppError
+	CreateAttachmentUploads(req *dtos.UserDTO, documentID string, createUploadsDTO *dtos.CreateAttachmentUploadsDTO) ([]dtos.AttachmentUploadDTO, *apperror.AppError)
+	CompleteAttachmentUpload(req *dtos.UserDTO, ID string) (*dtos.AttachmentDTO, *apperror.AppError)
+	CreateTusUpload(req *dtos.UserDTO, documentID string, createTusUploadDTO *dtos.CreateTusUploadDTO) (*dtos.TusUploadDTO, *apperror.AppError)
+	GetTusUpload(req *dtos.UserDTO, documentID, ID string) (*dtos.TusUploadDTO, *apperror.AppError)
+	AppendTusUpload(req *dtos.UserDTO, documentID, ID string, offset int64, body io.Reader) (*dtos.TusUploadDTO, *apperror.AppError)
+	GetStorageSavings() (*dtos.StorageSavingsDTO, *apperror.AppError)
+
+	// files served through the API by the local and memory storage drivers
+	GetStoredFile(bucketName, objectKey string, query url.Values) (io.ReadCloser, *s3client.ObjectInfo, *apperror.AppError)
+	PutStoredFile(bucketName, objectKey, contentType string, query url.Values, body io.Reader) *apperror.AppError
+
+	// job
+	PurgeExpiredAttachments()
+	CleanupExpiredUploads()
+	GenerateImageVariants()
+
+	// maintenance
+	ReconcileStorage(remove bool) (*dtos.StorageReconcileDTO, *apperror.AppError)
 }
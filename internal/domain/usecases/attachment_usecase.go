@@ -1,17 +1,28 @@
 package usecases
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"mime"
 	"mime/multipart"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
 	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
 	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/repositories"
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/apperror"
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/s3client"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/tusstore"
 	"github.com/isd-sgcu/sucu-backend-2024/utils"
 	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
 	"go.uber.org/zap"
@@ -22,83 +33,293 @@ type attachmentUsecase struct {
 	cfg                  config.Config
 	logger               *zap.Logger
 	attachmentRepository repositories.AttachmentRepository
+	documentRepository   repositories.DocumentRepository
+	tusLocks             sync.Map // IDs of the resumable uploads receiving a chunk
 }
 
-func NewAttachmentUsecase(cfg config.Config, logger *zap.Logger, attachmentRepository repositories.AttachmentRepository) AttachmentUsecase {
+func NewAttachmentUsecase(cfg config.Config, logger *zap.Logger, attachmentRepository repositories.AttachmentRepository, documentRepository repositories.DocumentRepository) AttachmentUsecase {
 	return &attachmentUsecase{
 		cfg:                  cfg,
 		logger:               logger,
 		attachmentRepository: attachmentRepository,
+		documentRepository:   documentRepository,
 	}
 }
 
-func (u *attachmentUsecase) GetAllAttachments() (*[]dtos.AttachmentDTO, *apperror.AppError) {
-	return nil, nil
+// GetAllAttachments lists the public attachments of documents anyone may open
+func (u *attachmentUsecase) GetAllAttachments(getAttachmentsDTO *dtos.GetAllAttachmentsDTO) (*dtos.PaginationResponse, *apperror.AppError) {
+	args := &repositories.FindAllAttachmentsArgs{
+		Offset:       (getAttachmentsDTO.Page - 1) * getAttachmentsDTO.PageSize,
+		Limit:        getAttachmentsDTO.PageSize,
+		TypeID:       getAttachmentsDTO.TypeID,
+		DocumentID:   getAttachmentsDTO.DocumentID,
+		Organization: getAttachmentsDTO.Organization,
+		Visibility:   constant.ATTACHMENT_PUBLIC,
+		PublicOnly:   true,
+		StartTime:    getAttachmentsDTO.StartTime,
+		EndTime:      getAttachmentsDTO.EndTime,
+	}
+
+	return u.findAttachments("GetAllAttachments", args, getAttachmentsDTO.Page, getAttachmentsDTO.PageSize)
+}
+
+// GetAllAttachmentsByRole lists the attachments of documents written by a role, whatever their status.
+// Only members of the role's organization may list them.
+func (u *attachmentUsecase) GetAllAttachmentsByRole(req *dtos.UserDTO, getAttachmentsDTO *dtos.GetAllAttachmentsByRoleDTO) (*dtos.PaginationResponse, *apperror.AppError) {
+	if utils.GetOrg(req.Role) != utils.GetOrg(getAttachmentsDTO.Role) {
+		u.logger.Named("GetAllAttachmentsByRole").Error(constant.ErrNotAttachmentOrg, zap.String("role", getAttachmentsDTO.Role), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotAttachmentOrg)
+	}
+
+	args := &repositories.FindAllAttachmentsArgs{
+		Offset:       (getAttachmentsDTO.Page - 1) * getAttachmentsDTO.PageSize,
+		Limit:        getAttachmentsDTO.PageSize,
+		TypeID:       getAttachmentsDTO.TypeID,
+		DocumentID:   getAttachmentsDTO.DocumentID,
+		Organization: getAttachmentsDTO.Organization,
+		Role:         getAttachmentsDTO.Role,
+		StartTime:    getAttachmentsDTO.StartTime,
+		EndTime:      getAttachmentsDTO.EndTime,
+	}
+
+	return u.findAttachments("GetAllAttachmentsByRole", args, getAttachmentsDTO.Page, getAttachmentsDTO.PageSize)
+}
+
+func (u *attachmentUsecase) findAttachments(name string, args *repositories.FindAllAttachmentsArgs, page, pageSize int) (*dtos.PaginationResponse, *apperror.AppError) {
+	attachments, total, err := u.attachmentRepository.FindAllAttachments(args)
+	if err != nil {
+		u.logger.Named(name).Error(constant.ErrGetAttachmentFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetAttachmentFailed)
+	}
+
+	data := make([]dtos.AttachmentDTO, len(*attachments))
+	for i, a := range *attachments {
+		data[i] = toAttachmentDTO(&a, a.Document.Author.RoleID)
+	}
+
+	paginationResponse := dtos.PaginationResponse{
+		Data:      data,
+		Page:      fmt.Sprintf("%d", page),
+		Limit:     fmt.Sprintf("%d", pageSize),
+		TotalPage: fmt.Sprintf("%d", int(math.Ceil(float64(total)/float64(pageSize)))),
+	}
+
+	return &paginationResponse, nil
+}
+
+// attachmentObjectKeys are the stored objects of an attachment, the original and its variants
+func attachmentObjectKeys(a *entities.Attachment) []string {
+	return append([]string{a.ObjectKey}, variantObjectKeys(a)...)
+}
+
+// variantObjectKeys are the stored variants of an attachment, unlike the original they are never shared
+func variantObjectKeys(a *entities.Attachment) []string {
+	keys := make([]string, len(a.Variants))
+	for i, v := range a.Variants {
+		keys[i] = v.ID
+	}
+	return keys
+}
+
+// purgeAttachment deletes the variants and the row of an attachment, then its original once no other attachment shares it
+func (u *attachmentUsecase) purgeAttachment(name string, a *entities.Attachment) error {
+	for _, objectKey := range variantObjectKeys(a) {
+		if err := u.attachmentRepository.DeleteAttachmentFromS3(u.cfg.GetAws().BucketName, objectKey); err != nil {
+			u.logger.Named(name).Error(constant.ErrDeleteAttachmentFromS3, zap.String("attachment_id", a.ID), zap.Error(err))
+			return err
+		}
+	}
+
+	released, err := u.attachmentRepository.PurgeAttachmentByID(a.ID)
+	if err != nil {
+		u.logger.Named(name).Error(constant.ErrPurgeAttachmentFailed, zap.String("attachment_id", a.ID), zap.Error(err))
+		return err
+	}
+	deleteReleasedObjects(u.cfg, u.logger.Named(name), u.attachmentRepository, released)
+	return nil
+}
+
+// deleteReleasedObjects deletes objects no attachment refers to anymore, one that fails is left for the reconcile command
+func deleteReleasedObjects(cfg config.Config, logger *zap.Logger, attachmentRepository repositories.AttachmentRepository, objectKeys []string) {
+	for _, objectKey := range objectKeys {
+		if err := attachmentRepository.DeleteAttachmentFromS3(cfg.GetAws().BucketName, objectKey); err != nil {
+			logger.Error(constant.ErrDeleteAttachmentFromS3, zap.String("object_key", objectKey), zap.Error(err))
+		}
+	}
+}
+
+// variantStatusFor tells whether variants will be made of an attachment with this content type
+func variantStatusFor(contentType string) string {
+	for _, t := range constant.VariantContentTypes {
+		if t == contentType {
+			return constant.VARIANT_PENDING
+		}
+	}
+	return constant.VARIANT_NONE
+}
+
+// toAttachmentDTO converts an attachment, authorRole is the role of the document's author whose organization tells where the file comes from
+func toAttachmentDTO(a *entities.Attachment, authorRole string) dtos.AttachmentDTO {
+	return dtos.AttachmentDTO{
+		ID:            a.ID,
+		DisplayName:   a.DisplayName,
+		DocumentID:    a.DocumentID,
+		TypeID:        strings.ToLower(a.TypeID),
+		Visibility:    strings.ToLower(a.Visibility),
+		ContentType:   a.ContentType,
+		Size:          a.Size,
+		Hash:          a.Hash,
+		VariantStatus: strings.ToLower(a.VariantStatus),
+		Variants:      toAttachmentVariantDTOs(a.Variants),
+		RoleID:        strings.ToLower(utils.GetOrg(authorRole)),
+		CreatedAt:     a.CreatedAt,
+		UpdatedAt:     a.UpdatedAt,
+	}
 }
 
-func (u *attachmentUsecase) GetAllAttachmentsByRole(req dtos.UserDTO) (*[]dtos.AttachmentDTO, *apperror.AppError) {
-	return nil, nil
+func toAttachmentVariantDTOs(variants []entities.AttachmentVariant) []dtos.AttachmentVariantDTO {
+	data := make([]dtos.AttachmentVariantDTO, len(variants))
+	for i, v := range variants {
+		data[i] = dtos.AttachmentVariantDTO{
+			ID:     v.ID,
+			Width:  v.Width,
+			Height: v.Height,
+			Format: v.Format,
+		}
+	}
+	return data
 }
 
-func (u *attachmentUsecase) CreateAttachments(documentID string, files map[string][]*multipart.FileHeader) *apperror.AppError {
+// CreateAttachments lets a document's editor upload files to it, all with the same visibility which defaults to public
+func (u *attachmentUsecase) CreateAttachments(req *dtos.UserDTO, documentID, visibility string, files map[string][]*multipart.FileHeader) *apperror.AppError {
+	document, err := u.documentRepository.FindDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("CreateAttachments").Error(constant.ErrDocumentNotFound, zap.String("document_id", documentID))
+			return apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named("CreateAttachments").Error(constant.ErrFindDocumentByID, zap.String("document_id", documentID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("CreateAttachments").Error(constant.ErrNotDocumentEditor, zap.String("document_id", documentID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	visibility = strings.ToUpper(visibility)
+	if visibility == "" {
+		visibility = constant.ATTACHMENT_PUBLIC
+	}
+	if !utils.ValidateAttachmentVisibility(visibility) {
+		return apperror.BadRequestError(constant.ErrInvalidAttachmentVisibility)
+	}
+
 	var attachments []entities.Attachment
-	fileReaders := make(map[string]io.Reader)
+	uploads := make(map[string]*attachmentFile)
 
+	// every file is checked so that all rejections are reported at once
+	var rejections []string
 	for _, fileHeaders := range files {
 		for _, fileHeader := range fileHeaders {
-			if err := u.validateAndProcessFile(fileHeader, documentID, &attachments, fileReaders); err != nil {
-				u.logger.Named("CreateAttachments").Error("Validate and process file: ", zap.Error(err))
-				return err
+			if err := u.validateAndProcessFile(newMultipartFile(fileHeader), documentID, visibility, &attachments, uploads); err != nil {
+				if err.HttpCode != http.StatusBadRequest {
+					u.logger.Named("CreateAttachments").Error("Validate and process file: ", zap.Error(err))
+					return err
+				}
+				rejections = append(rejections, err.Error())
 			}
 		}
 	}
+	if len(rejections) > 0 {
+		u.logger.Named("CreateAttachments").Error("Validate and process file: ", zap.Strings("rejections", rejections))
+		return apperror.BadRequestError(strings.Join(rejections, ", "))
+	}
 
-	if err := u.uploadAndSaveAttachments(fileReaders, attachments); err != nil {
+	if err := u.uploadAndSaveAttachments(uploads, attachments, visibility); err != nil {
 		u.logger.Named("CreateAttachments").Error("Upload and save attachments: ", zap.Error(err))
 		return err
 	}
 
-	u.logger.Named("CreateAttachments").Info("Success: ", zap.String("document_id", documentID), zap.Any("files", fileReaders))
+	names := make([]string, 0, len(uploads))
+	for name := range uploads {
+		names = append(names, name)
+	}
+	u.logger.Named("CreateAttachments").Info("Success: ", zap.String("document_id", documentID), zap.Strings("files", names))
 	return nil
 }
 
-func (u *attachmentUsecase) validateAndProcessFile(fileHeader *multipart.FileHeader, documentID string, attachments *[]entities.Attachment, fileReaders map[string]io.Reader) *apperror.AppError {
-	if fileHeader.Size > constant.MAX_FILE_SIZE {
-		return apperror.BadRequestError("file size exceeds the allowed limit")
+// attachmentFile is a file waiting to become an attachment, from a multipart form or a finished resumable upload
+type attachmentFile struct {
+	name string
+	size int64
+	open func() (io.ReadCloser, error)
+}
+
+func newMultipartFile(fileHeader *multipart.FileHeader) *attachmentFile {
+	return &attachmentFile{
+		name: fileHeader.Filename,
+		size: fileHeader.Size,
+		open: func() (io.ReadCloser, error) { return fileHeader.Open() },
 	}
+}
 
-	src, err := fileHeader.Open()
-	if err != nil {
-		u.logger.Named("CreateAttachments").Error("Open uploaded file: ", zap.Error(err))
-		return apperror.InternalServerError(fmt.Sprintf("failed to open the uploaded file: %s", err.Error()))
+func (u *attachmentUsecase) validateAndProcessFile(file *attachmentFile, documentID, visibility string, attachments *[]entities.Attachment, uploads map[string]*attachmentFile) *apperror.AppError {
+	if file.size > constant.MAX_FILE_SIZE {
+		return apperror.BadRequestError(fmt.Sprintf("%s: %s", file.name, constant.ErrAttachmentTooLarge))
 	}
-	defer src.Close()
 
-	typeID, err := utils.ValidateFileType(fileHeader.Filename)
+	typeID, err := utils.ValidateFileType(file.name)
 	if err != nil {
 		u.logger.Named("CreateAttachments").Error("Validate file type: ", zap.Error(err))
-		return apperror.BadRequestError(fmt.Sprintf("invalid file type for %s: %s", fileHeader.Filename, err.Error()))
+		return apperror.BadRequestError(fmt.Sprintf("invalid file type for %s: %s", file.name, err.Error()))
+	}
+
+	contentType, apperr := u.sniffFile(file)
+	if apperr != nil {
+		return apperr
 	}
 
-	fileName := fileHeader.Filename
+	fileName := file.name
 	name, err := u.generateNewFileName(fileName)
 	if err != nil {
 		return apperror.InternalServerError(fmt.Sprintf("failed to generate new file name: %s", err.Error()))
 	}
 
 	attachment := entities.Attachment{
-		ID:          name,
-		DisplayName: fileName,
-		DocumentID:  documentID,
-		TypeID:      *typeID,
+		ID:            name,
+		DisplayName:   fileName,
+		DocumentID:    documentID,
+		TypeID:        *typeID,
+		Visibility:    visibility,
+		ContentType:   contentType,
+		VariantStatus: variantStatusFor(contentType),
+		ObjectKey:     name,
+		Size:          file.size,
 	}
 	*attachments = append(*attachments, attachment)
 
-	fileReaders[name] = src
+	uploads[name] = file
 
 	return nil
 }
 
+// sniffFile reads the start of an uploaded file, or all of an svg, to check that the content matches the extension
+func (u *attachmentUsecase) sniffFile(file *attachmentFile) (string, *apperror.AppError) {
+	src, err := file.open()
+	if err != nil {
+		u.logger.Named("CreateAttachments").Error("Open uploaded file: ", zap.Error(err))
+		return "", apperror.InternalServerError(fmt.Sprintf("failed to open the uploaded file: %s", err.Error()))
+	}
+	defer src.Close()
+
+	contentType, err := utils.SniffFile(file.name, file.size, src)
+	if err != nil {
+		return "", apperror.BadRequestError(fmt.Sprintf("%s: %s", file.name, err.Error()))
+	}
+	return contentType, nil
+}
+
 func (u *attachmentUsecase) generateNewFileName(fileName string) (string, error) {
 	lastDotIndex := strings.LastIndex(fileName, ".")
 	if lastDotIndex == -1 {
@@ -110,19 +331,124 @@ func (u *attachmentUsecase) generateNewFileName(fileName string) (string, error)
 	return fmt.Sprintf("%s-%s.%s", nameWithoutExt, randomString, ext), nil
 }
 
-func (u *attachmentUsecase) uploadAndSaveAttachments(fileReaders map[string]io.Reader, attachments []entities.Attachment) *apperror.AppError {
-	if err := u.attachmentRepository.UploadAttachmentToS3(u.cfg.GetAws().BucketName, fileReaders); err != nil {
-		u.logger.Named("CreateAttachments").Error("Upload attachment to s3", zap.Error(err))
-		return apperror.InternalServerError(fmt.Sprintf("failed to upload attachment to s3: %s", err.Error()))
+// uploadAndSaveAttachments saves the rows as pending, streams the files to storage one at a time and then commits the rows.
+// When a step fails the files already uploaded and the pending rows are removed again.
+// A file whose content is stored already ends up sharing that object, its own copy is deleted.
+func (u *attachmentUsecase) uploadAndSaveAttachments(uploads map[string]*attachmentFile, attachments []entities.Attachment, visibility string) *apperror.AppError {
+	IDs := make([]string, len(attachments))
+	for i := range attachments {
+		attachments[i].State = constant.ATTACHMENT_PENDING
+		IDs[i] = attachments[i].ID
 	}
 
 	if err := u.attachmentRepository.InsertAttachments(&attachments); err != nil {
 		return apperror.InternalServerError(fmt.Sprintf("failed to insert attachments: %s", err.Error()))
 	}
 
+	for i := range attachments {
+		hash, err := u.uploadFile(attachments[i].ID, attachments[i].ContentType, uploads[attachments[i].ID], visibility == constant.ATTACHMENT_PUBLIC)
+		if err != nil {
+			// the failed file is deleted too, in case part of it was stored
+			u.rollbackAttachments(IDs, IDs[:i+1])
+			return err
+		}
+		attachments[i].Hash = hash
+	}
+
+	duplicates, err := u.attachmentRepository.CommitAttachments(&attachments)
+	if err != nil {
+		u.logger.Named("CreateAttachments").Error(constant.ErrCommitAttachmentsFailed, zap.Strings("attachment_ids", IDs), zap.Error(err))
+		u.rollbackAttachments(IDs, IDs)
+		return apperror.InternalServerError(constant.ErrCommitAttachmentsFailed)
+	}
+	deleteReleasedObjects(u.cfg, u.logger.Named("CreateAttachments"), u.attachmentRepository, duplicates)
+
 	return nil
 }
 
+// rollbackAttachments removes the uploaded files and the pending rows of a failed upload, whatever is left is found by the reconcile command
+func (u *attachmentUsecase) rollbackAttachments(IDs, uploaded []string) {
+	for _, objectKey := range uploaded {
+		if err := u.attachmentRepository.DeleteAttachmentFromS3(u.cfg.GetAws().BucketName, objectKey); err != nil {
+			u.logger.Named("CreateAttachments").Error(constant.ErrDeleteAttachmentFromS3, zap.String("object_key", objectKey), zap.Error(err))
+		}
+	}
+	if err := u.attachmentRepository.DeletePendingAttachments(IDs); err != nil {
+		u.logger.Named("CreateAttachments").Error(constant.ErrDeleteAttachmentFailed, zap.Strings("attachment_ids", IDs), zap.Error(err))
+	}
+}
+
+// uploadFile streams a file to storage and gives the hex SHA-256 of what was uploaded
+func (u *attachmentUsecase) uploadFile(name, contentType string, file *attachmentFile, public bool) (string, *apperror.AppError) {
+	src, err := file.open()
+	if err != nil {
+		u.logger.Named("CreateAttachments").Error("Open uploaded file: ", zap.Error(err))
+		return "", apperror.InternalServerError(fmt.Sprintf("failed to open the uploaded file: %s", err.Error()))
+	}
+	defer src.Close()
+
+	hash := sha256.New()
+	if err := u.attachmentRepository.UploadAttachmentToS3(u.cfg.GetAws().BucketName, name, contentType, io.TeeReader(src, hash), public); err != nil {
+		u.logger.Named("CreateAttachments").Error("Upload attachment to s3", zap.String("object_key", name), zap.Error(err))
+		return "", apperror.InternalServerError(fmt.Sprintf("failed to upload attachment to s3: %s", err.Error()))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetAttachmentURL gives the stable URL of a public attachment, or a presigned URL of a private one to the document's editors.
+// A width above zero asks for the variant of that width, in the original format unless another is named.
+func (u *attachmentUsecase) GetAttachmentURL(req *dtos.UserDTO, ID string, width int, format string) (*dtos.AttachmentURLDTO, *apperror.AppError) {
+	attachment, err := u.attachmentRepository.FindAttachmentByID(ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("GetAttachmentURL").Error(constant.ErrAttachmentNotFound, zap.String("attachment_id", ID))
+			return nil, apperror.NotFoundError(constant.ErrAttachmentNotFound)
+		}
+		u.logger.Named("GetAttachmentURL").Error(constant.ErrFindAttachmentByID, zap.String("attachment_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindAttachmentByID)
+	}
+
+	objectKey := attachment.ObjectKey
+	if width > 0 {
+		objectKey = ""
+		for _, v := range attachment.Variants {
+			if v.Width == width && (v.Format == format || format == "" && v.Format != constant.VARIANT_WEBP) {
+				objectKey = v.ID
+			}
+		}
+		if objectKey == "" {
+			return nil, apperror.NotFoundError(constant.ErrVariantNotFound)
+		}
+	}
+
+	bucketName := u.cfg.GetAws().BucketName
+	if attachment.Visibility != constant.ATTACHMENT_PRIVATE {
+		return &dtos.AttachmentURLDTO{
+			URL:        u.attachmentRepository.AttachmentURL(bucketName, objectKey),
+			Visibility: strings.ToLower(constant.ATTACHMENT_PUBLIC),
+		}, nil
+	}
+
+	if !isDocumentEditor(req, &attachment.Document) {
+		u.logger.Named("GetAttachmentURL").Error(constant.ErrNotAttachmentViewer, zap.String("attachment_id", ID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotAttachmentViewer)
+	}
+
+	expiresAt := time.Now().Add(constant.ATTACHMENT_URL_EXPIRY)
+	signed, err := u.attachmentRepository.SignAttachmentURL(bucketName, objectKey, constant.ATTACHMENT_URL_EXPIRY)
+	if err != nil {
+		u.logger.Named("GetAttachmentURL").Error(constant.ErrSignAttachmentURLFailed, zap.String("attachment_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrSignAttachmentURLFailed)
+	}
+
+	return &dtos.AttachmentURLDTO{
+		URL:        signed,
+		Visibility: strings.ToLower(constant.ATTACHMENT_PRIVATE),
+		ExpiresAt:  &expiresAt,
+	}, nil
+}
+
 func (u *attachmentUsecase) DeleteAttachment(ID string) *apperror.AppError {
 	if _, err := u.attachmentRepository.FindAttachmentByID(ID); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -143,3 +469,764 @@ func (u *attachmentUsecase) DeleteAttachment(ID string) *apperror.AppError {
 	u.logger.Named("DeleteAttachment").Info("Success: ", zap.String("attachment_id", ID))
 	return nil
 }
+
+// CreateAttachmentUploads lets a document's editor upload files straight to storage.
+// Each file gets a presigned PUT URL, the upload has to be completed before it expires.
+func (u *attachmentUsecase) CreateAttachmentUploads(req *dtos.UserDTO, documentID string, createUploadsDTO *dtos.CreateAttachmentUploadsDTO) ([]dtos.AttachmentUploadDTO, *apperror.AppError) {
+	document, err := u.documentRepository.FindDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("CreateAttachmentUploads").Error(constant.ErrDocumentNotFound, zap.String("document_id", documentID))
+			return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named("CreateAttachmentUploads").Error(constant.ErrFindDocumentByID, zap.String("document_id", documentID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("CreateAttachmentUploads").Error(constant.ErrNotDocumentEditor, zap.String("document_id", documentID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	visibility := strings.ToUpper(createUploadsDTO.Visibility)
+	if visibility == "" {
+		visibility = constant.ATTACHMENT_PUBLIC
+	}
+	if !utils.ValidateAttachmentVisibility(visibility) {
+		return nil, apperror.BadRequestError(constant.ErrInvalidAttachmentVisibility)
+	}
+	if len(createUploadsDTO.Files) > constant.MAX_UPLOAD_FILES {
+		return nil, apperror.BadRequestError(constant.ErrTooManyUploadFiles)
+	}
+
+	bucketName := u.cfg.GetAws().BucketName
+	expiresAt := time.Now().Add(constant.ATTACHMENT_UPLOAD_EXPIRY)
+	uploads := make([]entities.AttachmentUpload, len(createUploadsDTO.Files))
+	data := make([]dtos.AttachmentUploadDTO, len(createUploadsDTO.Files))
+
+	// every file is checked so that all rejections are reported at once
+	typeIDs := make([]string, len(createUploadsDTO.Files))
+	contentTypes := make([]string, len(createUploadsDTO.Files))
+	var rejections []string
+	for i, file := range createUploadsDTO.Files {
+		typeID, contentType, err := validateUploadFile(&file)
+		if err != nil {
+			rejections = append(rejections, fmt.Sprintf("%s: %s", file.Name, err.Error()))
+			continue
+		}
+		typeIDs[i] = typeID
+		contentTypes[i] = contentType
+	}
+	if len(rejections) > 0 {
+		u.logger.Named("CreateAttachmentUploads").Error("Validate upload file: ", zap.Strings("rejections", rejections))
+		return nil, apperror.BadRequestError(strings.Join(rejections, ", "))
+	}
+
+	for i, file := range createUploadsDTO.Files {
+		typeID, contentType := typeIDs[i], contentTypes[i]
+
+		objectKey, err := u.generateNewFileName(file.Name)
+		if err != nil {
+			return nil, apperror.InternalServerError(fmt.Sprintf("failed to generate new file name: %s", err.Error()))
+		}
+
+		url, headers, err := u.attachmentRepository.SignAttachmentUploadURL(bucketName, objectKey, contentType, visibility == constant.ATTACHMENT_PUBLIC, constant.ATTACHMENT_UPLOAD_EXPIRY)
+		if err != nil {
+			u.logger.Named("CreateAttachmentUploads").Error(constant.ErrSignAttachmentURLFailed, zap.String("object_key", objectKey), zap.Error(err))
+			return nil, apperror.InternalServerError(constant.ErrSignAttachmentURLFailed)
+		}
+
+		uploads[i] = entities.AttachmentUpload{
+			ID:          fmt.Sprintf("UPL-%v", utils.GenerateRandomString("0123456789", 8)),
+			ObjectKey:   objectKey,
+			DisplayName: file.Name,
+			DocumentID:  documentID,
+			TypeID:      typeID,
+			Visibility:  visibility,
+			Size:        file.Size,
+			ContentType: contentType,
+			CreatedBy:   req.ID,
+			ExpiresAt:   expiresAt,
+		}
+		data[i] = dtos.AttachmentUploadDTO{
+			ID:        uploads[i].ID,
+			Name:      file.Name,
+			URL:       url,
+			Method:    "PUT",
+			Headers:   headers,
+			ExpiresAt: expiresAt,
+		}
+	}
+
+	if err := u.attachmentRepository.InsertAttachmentUploads(&uploads); err != nil {
+		u.logger.Named("CreateAttachmentUploads").Error(constant.ErrCreateUploadFailed, zap.String("document_id", documentID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrCreateUploadFailed)
+	}
+
+	u.logger.Named("CreateAttachmentUploads").Info("Success: ", zap.String("document_id", documentID), zap.Int("files", len(uploads)))
+	return data, nil
+}
+
+// validateUploadFile checks an announced file and picks the type and content type it must be uploaded with,
+// a content type the client names has to agree with the one its extension implies
+func validateUploadFile(file *dtos.AttachmentUploadFileDTO) (string, string, error) {
+	if file.Size <= 0 {
+		return "", "", errors.New(constant.ErrInvalidUploadSize)
+	}
+	if file.Size > constant.MAX_FILE_SIZE {
+		return "", "", errors.New(constant.ErrAttachmentTooLarge)
+	}
+	if utils.IsSvg(file.Name) && file.Size > constant.MAX_SVG_SIZE {
+		return "", "", errors.New(constant.ErrSvgTooLarge)
+	}
+
+	typeID, err := utils.ValidateFileType(file.Name)
+	if err != nil {
+		return "", "", err
+	}
+
+	expected := utils.ContentTypeByExtension(file.Name)
+	if file.ContentType == "" {
+		return *typeID, expected, nil
+	}
+
+	given, _, err := mime.ParseMediaType(file.ContentType)
+	if err != nil {
+		return "", "", errors.New(constant.ErrContentTypeMismatch)
+	}
+	want, _, _ := mime.ParseMediaType(expected)
+	if given != want {
+		return "", "", errors.New(constant.ErrContentTypeMismatch)
+	}
+	return *typeID, file.ContentType, nil
+}
+
+// CompleteAttachmentUpload checks that the file in storage is the one that was announced, then attaches it to the document
+func (u *attachmentUsecase) CompleteAttachmentUpload(req *dtos.UserDTO, ID string) (*dtos.AttachmentDTO, *apperror.AppError) {
+	upload, err := u.attachmentRepository.FindAttachmentUploadByID(ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("CompleteAttachmentUpload").Error(constant.ErrUploadNotFound, zap.String("upload_id", ID))
+			return nil, apperror.NotFoundError(constant.ErrUploadNotFound)
+		}
+		u.logger.Named("CompleteAttachmentUpload").Error(constant.ErrCompleteUploadFailed, zap.String("upload_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrCompleteUploadFailed)
+	}
+
+	if upload.CreatedBy != req.ID {
+		u.logger.Named("CompleteAttachmentUpload").Error(constant.ErrNotUploadOwner, zap.String("upload_id", ID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotUploadOwner)
+	}
+	if time.Now().After(upload.ExpiresAt) {
+		return nil, apperror.BadRequestError(constant.ErrUploadExpired)
+	}
+
+	info, err := u.attachmentRepository.StatAttachmentObject(u.cfg.GetAws().BucketName, upload.ObjectKey)
+	if err != nil {
+		if errors.Is(err, s3client.ErrObjectNotFound) {
+			return nil, apperror.BadRequestError(constant.ErrUploadedFileNotFound)
+		}
+		u.logger.Named("CompleteAttachmentUpload").Error(constant.ErrCompleteUploadFailed, zap.String("upload_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrCompleteUploadFailed)
+	}
+	if info.Size != upload.Size || info.ContentType != upload.ContentType {
+		u.logger.Named("CompleteAttachmentUpload").Error(constant.ErrUploadedFileMismatch, zap.String("upload_id", ID), zap.Int64("size", info.Size), zap.String("content_type", info.ContentType))
+		return nil, apperror.BadRequestError(constant.ErrUploadedFileMismatch)
+	}
+
+	contentType, hash, apperr := u.sniffAndHashObject(upload, info.Size)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	attachment := entities.Attachment{
+		ID:            upload.ObjectKey,
+		DisplayName:   upload.DisplayName,
+		DocumentID:    upload.DocumentID,
+		TypeID:        upload.TypeID,
+		Visibility:    upload.Visibility,
+		ContentType:   contentType,
+		VariantStatus: variantStatusFor(contentType),
+		State:         constant.ATTACHMENT_COMMITTED, // the file is already in storage
+		ObjectKey:     upload.ObjectKey,
+		Hash:          hash,
+		Size:          info.Size,
+	}
+	duplicate, err := u.attachmentRepository.CompleteAttachmentUpload(upload, &attachment)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFoundError(constant.ErrUploadNotFound)
+		}
+		u.logger.Named("CompleteAttachmentUpload").Error(constant.ErrCompleteUploadFailed, zap.String("upload_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrCompleteUploadFailed)
+	}
+	if duplicate != "" {
+		deleteReleasedObjects(u.cfg, u.logger.Named("CompleteAttachmentUpload"), u.attachmentRepository, []string{duplicate})
+	}
+
+	u.logger.Named("CompleteAttachmentUpload").Info("Success: ", zap.String("upload_id", ID), zap.String("attachment_id", attachment.ID))
+	data := toAttachmentDTO(&attachment, upload.Document.Author.RoleID)
+	return &data, nil
+}
+
+// CreateTusUpload starts a resumable upload of a file to a document, the chunks are sent with AppendTusUpload
+func (u *attachmentUsecase) CreateTusUpload(req *dtos.UserDTO, documentID string, createTusUploadDTO *dtos.CreateTusUploadDTO) (*dtos.TusUploadDTO, *apperror.AppError) {
+	document, err := u.documentRepository.FindDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("CreateTusUpload").Error(constant.ErrDocumentNotFound, zap.String("document_id", documentID))
+			return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named("CreateTusUpload").Error(constant.ErrFindDocumentByID, zap.String("document_id", documentID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("CreateTusUpload").Error(constant.ErrNotDocumentEditor, zap.String("document_id", documentID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	visibility := strings.ToUpper(createTusUploadDTO.Visibility)
+	if visibility == "" {
+		visibility = constant.ATTACHMENT_PUBLIC
+	}
+	if !utils.ValidateAttachmentVisibility(visibility) {
+		return nil, apperror.BadRequestError(constant.ErrInvalidAttachmentVisibility)
+	}
+
+	// tus answers an upload above Tus-Max-Size with 413
+	if createTusUploadDTO.Length > constant.MAX_FILE_SIZE {
+		return nil, apperror.RequestEntityTooLargeError(constant.ErrAttachmentTooLarge)
+	}
+	if _, _, err := validateUploadFile(&dtos.AttachmentUploadFileDTO{Name: createTusUploadDTO.Name, Size: createTusUploadDTO.Length}); err != nil {
+		u.logger.Named("CreateTusUpload").Error("Validate upload file: ", zap.String("name", createTusUploadDTO.Name), zap.Error(err))
+		return nil, apperror.BadRequestError(fmt.Sprintf("%s: %s", createTusUploadDTO.Name, err.Error()))
+	}
+
+	upload := entities.TusUpload{
+		ID:          fmt.Sprintf("TUS-%v", utils.GenerateRandomString("0123456789", 8)),
+		DocumentID:  documentID,
+		DisplayName: createTusUploadDTO.Name,
+		Visibility:  visibility,
+		Length:      createTusUploadDTO.Length,
+		CreatedBy:   req.ID,
+		ExpiresAt:   time.Now().Add(constant.TUS_UPLOAD_EXPIRY),
+	}
+	if err := u.attachmentRepository.InsertTusUpload(&upload); err != nil {
+		u.logger.Named("CreateTusUpload").Error(constant.ErrCreateUploadFailed, zap.String("document_id", documentID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrCreateUploadFailed)
+	}
+
+	u.logger.Named("CreateTusUpload").Info("Success: ", zap.String("document_id", documentID), zap.String("upload_id", upload.ID))
+	return toTusUploadDTO(&upload), nil
+}
+
+func toTusUploadDTO(upload *entities.TusUpload) *dtos.TusUploadDTO {
+	return &dtos.TusUploadDTO{
+		ID:        upload.ID,
+		Offset:    upload.Offset,
+		Length:    upload.Length,
+		ExpiresAt: upload.ExpiresAt,
+	}
+}
+
+// GetTusUpload tells the user who started a resumable upload how much of it has arrived
+func (u *attachmentUsecase) GetTusUpload(req *dtos.UserDTO, documentID, ID string) (*dtos.TusUploadDTO, *apperror.AppError) {
+	upload, apperr := u.findTusUpload("GetTusUpload", req, documentID, ID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	data := toTusUploadDTO(upload)
+	data.Offset = tusOffset(upload)
+	return data, nil
+}
+
+// tusOffset is where the next chunk of an upload starts. The row of a fully received upload is only left
+// when its attachment failed, it is reported one byte short so that a client resends the last byte and the attachment is tried again.
+func tusOffset(upload *entities.TusUpload) int64 {
+	if upload.Offset == upload.Length {
+		return upload.Length - 1
+	}
+	return upload.Offset
+}
+
+// findTusUpload finds an unexpired resumable upload of the document that the user started
+func (u *attachmentUsecase) findTusUpload(name string, req *dtos.UserDTO, documentID, ID string) (*entities.TusUpload, *apperror.AppError) {
+	upload, err := u.attachmentRepository.FindTusUploadByID(ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named(name).Error(constant.ErrUploadNotFound, zap.String("upload_id", ID))
+			return nil, apperror.NotFoundError(constant.ErrUploadNotFound)
+		}
+		u.logger.Named(name).Error(constant.ErrAppendUploadFailed, zap.String("upload_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrAppendUploadFailed)
+	}
+
+	if upload.DocumentID != documentID {
+		u.logger.Named(name).Error(constant.ErrUploadNotFound, zap.String("upload_id", ID), zap.String("document_id", documentID))
+		return nil, apperror.NotFoundError(constant.ErrUploadNotFound)
+	}
+	if upload.CreatedBy != req.ID {
+		u.logger.Named(name).Error(constant.ErrNotUploadOwner, zap.String("upload_id", ID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotUploadOwner)
+	}
+	if time.Now().After(upload.ExpiresAt) {
+		return nil, apperror.GoneError(constant.ErrUploadExpired)
+	}
+	return upload, nil
+}
+
+// AppendTusUpload writes a chunk of a resumable upload at offset, which must be where the previous chunk ended.
+// Once the last byte arrives the file goes through the same checks as CreateAttachments and becomes an attachment.
+// After a failure the attachment is tried again when the last byte is sent once more, see tusOffset.
+func (u *attachmentUsecase) AppendTusUpload(req *dtos.UserDTO, documentID, ID string, offset int64, body io.Reader) (*dtos.TusUploadDTO, *apperror.AppError) {
+	// two chunks written at once would overwrite each other
+	if _, busy := u.tusLocks.LoadOrStore(ID, struct{}{}); busy {
+		return nil, apperror.ConflictError(constant.ErrUploadBusy)
+	}
+	defer u.tusLocks.Delete(ID)
+
+	upload, apperr := u.findTusUpload("AppendTusUpload", req, documentID, ID)
+	if apperr != nil {
+		return nil, apperr
+	}
+	if offset != tusOffset(upload) {
+		u.logger.Named("AppendTusUpload").Error(constant.ErrUploadOffsetMismatch, zap.String("upload_id", ID), zap.Int64("offset", offset), zap.Int64("received", upload.Offset))
+		return nil, apperror.ConflictError(constant.ErrUploadOffsetMismatch)
+	}
+
+	// the last byte of a fully received upload is written again
+	upload.Offset = offset
+	err := u.attachmentRepository.AppendTusUpload(upload, body, time.Now().Add(constant.TUS_UPLOAD_EXPIRY))
+	if errors.Is(err, tusstore.ErrChunkTooLarge) {
+		return nil, apperror.RequestEntityTooLargeError(constant.ErrChunkTooLarge)
+	}
+	if errors.Is(err, tusstore.ErrUploadNotFound) {
+		u.logger.Named("AppendTusUpload").Error(constant.ErrUploadNotFound, zap.String("upload_id", ID), zap.Error(err))
+		return nil, apperror.NotFoundError(constant.ErrUploadNotFound)
+	}
+	if err != nil {
+		u.logger.Named("AppendTusUpload").Error(constant.ErrAppendUploadFailed, zap.String("upload_id", ID), zap.Int64("offset", upload.Offset), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrAppendUploadFailed)
+	}
+
+	if upload.Offset == upload.Length {
+		if apperr := u.completeTusUpload(upload); apperr != nil {
+			return nil, apperr
+		}
+	}
+
+	return toTusUploadDTO(upload), nil
+}
+
+// completeTusUpload turns a fully received upload into an attachment.
+// A file the checks reject is removed, after other failures the upload stays so the client can try again.
+func (u *attachmentUsecase) completeTusUpload(upload *entities.TusUpload) *apperror.AppError {
+	if _, err := u.documentRepository.FindDocumentByID(upload.DocumentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("AppendTusUpload").Error(constant.ErrDocumentNotFound, zap.String("document_id", upload.DocumentID))
+			u.deleteTusUpload(upload.ID)
+			return apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named("AppendTusUpload").Error(constant.ErrFindDocumentByID, zap.String("document_id", upload.DocumentID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	file := &attachmentFile{
+		name: upload.DisplayName,
+		size: upload.Length,
+		open: func() (io.ReadCloser, error) { return u.attachmentRepository.OpenTusUpload(upload.ID) },
+	}
+
+	var attachments []entities.Attachment
+	uploads := make(map[string]*attachmentFile)
+	if err := u.validateAndProcessFile(file, upload.DocumentID, upload.Visibility, &attachments, uploads); err != nil {
+		u.logger.Named("AppendTusUpload").Error("Validate and process file: ", zap.String("upload_id", upload.ID), zap.Error(err))
+		if err.HttpCode == http.StatusBadRequest {
+			u.deleteTusUpload(upload.ID)
+		}
+		return err
+	}
+
+	if err := u.uploadAndSaveAttachments(uploads, attachments, upload.Visibility); err != nil {
+		u.logger.Named("AppendTusUpload").Error("Upload and save attachments: ", zap.String("upload_id", upload.ID), zap.Error(err))
+		return err
+	}
+	u.deleteTusUpload(upload.ID)
+
+	u.logger.Named("AppendTusUpload").Info("Success: ", zap.String("upload_id", upload.ID), zap.String("attachment_id", attachments[0].ID))
+	return nil
+}
+
+// deleteTusUpload only logs a failure, the upload is removed by CleanupExpiredUploads once it expires
+func (u *attachmentUsecase) deleteTusUpload(ID string) {
+	if err := u.attachmentRepository.DeleteTusUploadByID(ID); err != nil {
+		u.logger.Named("AppendTusUpload").Error(constant.ErrCleanupUploadFailed, zap.String("upload_id", ID), zap.Error(err))
+	}
+}
+
+// sniffAndHashObject streams an uploaded object once, checking that its content matches the extension and hashing it
+func (u *attachmentUsecase) sniffAndHashObject(upload *entities.AttachmentUpload, size int64) (string, string, *apperror.AppError) {
+	object, err := u.attachmentRepository.ReadAttachmentObject(u.cfg.GetAws().BucketName, upload.ObjectKey)
+	if err != nil {
+		u.logger.Named("CompleteAttachmentUpload").Error(constant.ErrReadAttachmentFailed, zap.String("upload_id", upload.ID), zap.Error(err))
+		return "", "", apperror.InternalServerError(constant.ErrReadAttachmentFailed)
+	}
+	defer object.Close()
+
+	hash := sha256.New()
+	content := io.TeeReader(object, hash)
+
+	contentType, err := utils.SniffFile(upload.DisplayName, size, content)
+	if err != nil {
+		u.logger.Named("CompleteAttachmentUpload").Error(constant.ErrUploadedFileMismatch, zap.String("upload_id", upload.ID), zap.Error(err))
+		return "", "", apperror.BadRequestError(fmt.Sprintf("%s: %s", upload.DisplayName, err.Error()))
+	}
+
+	// the rest of the file after what sniffing read
+	if _, err := io.Copy(io.Discard, content); err != nil {
+		u.logger.Named("CompleteAttachmentUpload").Error(constant.ErrReadAttachmentFailed, zap.String("upload_id", upload.ID), zap.Error(err))
+		return "", "", apperror.InternalServerError(constant.ErrReadAttachmentFailed)
+	}
+	return contentType, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetStorageSavings reports what deduplication saves and which objects are shared the most
+func (u *attachmentUsecase) GetStorageSavings() (*dtos.StorageSavingsDTO, *apperror.AppError) {
+	savings, err := u.attachmentRepository.FindStorageSavings()
+	if err != nil {
+		u.logger.Named("GetStorageSavings").Error(constant.ErrGetStorageSavingsFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetStorageSavingsFailed)
+	}
+	objects, err := u.attachmentRepository.FindMostSharedObjects(constant.MOST_SHARED_OBJECTS)
+	if err != nil {
+		u.logger.Named("GetStorageSavings").Error(constant.ErrGetStorageSavingsFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetStorageSavingsFailed)
+	}
+
+	mostShared := make([]dtos.SharedObjectDTO, len(*objects))
+	for i, o := range *objects {
+		mostShared[i] = dtos.SharedObjectDTO{
+			ObjectKey:  o.ObjectKey,
+			Hash:       o.Hash,
+			Visibility: strings.ToLower(o.Visibility),
+			Size:       o.Size,
+			References: o.RefCount,
+			SavedBytes: o.Size * (o.RefCount - 1),
+		}
+	}
+
+	return &dtos.StorageSavingsDTO{
+		Objects:         savings.Objects,
+		References:      savings.References,
+		StoredBytes:     savings.StoredBytes,
+		ReferencedBytes: savings.ReferencedBytes,
+		SavedBytes:      savings.ReferencedBytes - savings.StoredBytes,
+		MostShared:      mostShared,
+	}, nil
+}
+
+// GetStoredFile serves a file of the configured bucket, private files need a presigned query
+func (u *attachmentUsecase) GetStoredFile(bucketName, objectKey string, query url.Values) (io.ReadCloser, *s3client.ObjectInfo, *apperror.AppError) {
+	if bucketName != u.cfg.GetAws().BucketName {
+		return nil, nil, apperror.NotFoundError(constant.ErrStoredFileNotFound)
+	}
+
+	body, info, err := u.attachmentRepository.OpenStoredFile(bucketName, objectKey, query)
+	if err != nil {
+		return nil, nil, u.storedFileError("GetStoredFile", objectKey, err)
+	}
+	return body, info, nil
+}
+
+// PutStoredFile stores a file uploaded to a presigned URL of the configured bucket
+func (u *attachmentUsecase) PutStoredFile(bucketName, objectKey, contentType string, query url.Values, body io.Reader) *apperror.AppError {
+	if bucketName != u.cfg.GetAws().BucketName {
+		return apperror.NotFoundError(constant.ErrStoredFileNotFound)
+	}
+
+	if err := u.attachmentRepository.ReceiveStoredFile(bucketName, objectKey, contentType, query, body); err != nil {
+		return u.storedFileError("PutStoredFile", objectKey, err)
+	}
+	return nil
+}
+
+func (u *attachmentUsecase) storedFileError(name, objectKey string, err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, s3client.ErrObjectNotFound):
+		return apperror.NotFoundError(constant.ErrStoredFileNotFound)
+	case errors.Is(err, s3client.ErrAccessDenied):
+		return apperror.ForbiddenError(constant.ErrStoredFileForbidden)
+	case errors.Is(err, s3client.ErrInvalidObjectKey):
+		return apperror.BadRequestError(constant.ErrInvalidStoredFileKey)
+	default:
+		u.logger.Named(name).Error(constant.ErrStoreFileFailed, zap.String("object_key", objectKey), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrStoreFileFailed)
+	}
+}
+
+// job
+
+func (u *attachmentUsecase) PurgeExpiredAttachments() {
+	cutoff := time.Now().AddDate(0, 0, -u.cfg.GetJob().TrashRetentionDays)
+
+	attachments, err := u.attachmentRepository.FindDeletedAttachmentsBefore(cutoff)
+	if err != nil {
+		u.logger.Named("PurgeExpiredAttachments").Error(constant.ErrGetAttachmentFailed, zap.Error(err))
+		return
+	}
+
+	purged := 0
+	for _, a := range *attachments {
+		if err := u.purgeAttachment("PurgeExpiredAttachments", &a); err != nil {
+			continue
+		}
+		purged++
+	}
+
+	u.logger.Named("PurgeExpiredAttachments").Info("Success: ", zap.Int("purged", purged), zap.Time("deleted_before", cutoff))
+}
+
+// CleanupExpiredUploads removes uploads that were never completed together with whatever reached storage,
+// and resumable uploads that stopped receiving chunks
+func (u *attachmentUsecase) CleanupExpiredUploads() {
+	uploads, err := u.attachmentRepository.FindExpiredAttachmentUploads(time.Now())
+	if err != nil {
+		u.logger.Named("CleanupExpiredUploads").Error(constant.ErrCleanupUploadFailed, zap.Error(err))
+		return
+	}
+
+	cleaned := 0
+	for _, upload := range *uploads {
+		if err := u.attachmentRepository.DeleteAttachmentFromS3(u.cfg.GetAws().BucketName, upload.ObjectKey); err != nil {
+			u.logger.Named("CleanupExpiredUploads").Error(constant.ErrDeleteAttachmentFromS3, zap.String("upload_id", upload.ID), zap.Error(err))
+			continue
+		}
+		if err := u.attachmentRepository.DeleteAttachmentUploadByID(upload.ID); err != nil {
+			u.logger.Named("CleanupExpiredUploads").Error(constant.ErrCleanupUploadFailed, zap.String("upload_id", upload.ID), zap.Error(err))
+			continue
+		}
+		cleaned++
+	}
+
+	tusUploads, err := u.attachmentRepository.FindExpiredTusUploads(time.Now())
+	if err != nil {
+		u.logger.Named("CleanupExpiredUploads").Error(constant.ErrCleanupUploadFailed, zap.Error(err))
+		return
+	}
+	for _, upload := range *tusUploads {
+		// a slow chunk may still be arriving, it pushes the expiry back once it is written
+		if _, busy := u.tusLocks.Load(upload.ID); busy {
+			continue
+		}
+		if err := u.attachmentRepository.DeleteTusUploadByID(upload.ID); err != nil {
+			u.logger.Named("CleanupExpiredUploads").Error(constant.ErrCleanupUploadFailed, zap.String("upload_id", upload.ID), zap.Error(err))
+			continue
+		}
+		cleaned++
+	}
+
+	u.logger.Named("CleanupExpiredUploads").Info("Success: ", zap.Int("cleaned", cleaned))
+}
+
+// GenerateImageVariants resizes the images waiting for their variants.
+// An image that cannot be decoded is marked failed, one that hit a storage error is tried again on the next run.
+func (u *attachmentUsecase) GenerateImageVariants() {
+	attachments, err := u.attachmentRepository.FindPendingVariantAttachments(constant.VARIANT_BATCH_SIZE)
+	if err != nil {
+		u.logger.Named("GenerateImageVariants").Error(constant.ErrGenerateVariantsFailed, zap.Error(err))
+		return
+	}
+
+	generated := 0
+	for _, a := range *attachments {
+		variants, permanent, err := u.generateVariants(&a)
+		if err != nil {
+			u.logger.Named("GenerateImageVariants").Error(constant.ErrGenerateVariantsFailed, zap.String("attachment_id", a.ID), zap.Bool("permanent", permanent), zap.Error(err))
+			if permanent {
+				if err := u.attachmentRepository.UpdateAttachmentVariantStatus(a.ID, constant.VARIANT_FAILED); err != nil {
+					u.logger.Named("GenerateImageVariants").Error(constant.ErrGenerateVariantsFailed, zap.String("attachment_id", a.ID), zap.Error(err))
+				}
+			}
+			continue
+		}
+
+		if err := u.attachmentRepository.SaveAttachmentVariants(a.ID, &variants); err != nil {
+			u.logger.Named("GenerateImageVariants").Error(constant.ErrGenerateVariantsFailed, zap.String("attachment_id", a.ID), zap.Error(err))
+			u.deleteVariantObjects(variants)
+			continue
+		}
+		generated++
+	}
+
+	u.logger.Named("GenerateImageVariants").Info("Success: ", zap.Int("generated", generated), zap.Int("pending", len(*attachments)-generated))
+}
+
+// generateVariants stores a resized copy of the image in its own format and in WebP for every variant width smaller than the image,
+// permanent tells whether trying again could succeed
+func (u *attachmentUsecase) generateVariants(a *entities.Attachment) ([]entities.AttachmentVariant, bool, error) {
+	bucketName := u.cfg.GetAws().BucketName
+	data, err := u.attachmentRepository.ReadAttachmentHead(bucketName, a.ObjectKey, constant.MAX_FILE_SIZE)
+	if err != nil {
+		return nil, errors.Is(err, s3client.ErrObjectNotFound), err
+	}
+
+	img, err := utils.DecodeImage(data)
+	if err != nil {
+		return nil, true, err
+	}
+
+	var variants []entities.AttachmentVariant
+	for _, width := range constant.ImageVariantWidths {
+		if width >= img.Width() {
+			break
+		}
+
+		resized := img.Resize(width)
+		for _, format := range []string{img.Format, constant.VARIANT_WEBP} {
+			encoded, err := utils.EncodeImage(resized, format)
+			if err != nil {
+				u.deleteVariantObjects(variants)
+				return nil, true, err
+			}
+
+			objectKey := fmt.Sprintf("variants/%dw/%s.%s", width, a.ID, format)
+			contentType := "image/" + format
+			if err := u.attachmentRepository.UploadAttachmentToS3(bucketName, objectKey, contentType, bytes.NewReader(encoded), a.Visibility == constant.ATTACHMENT_PUBLIC); err != nil {
+				u.deleteVariantObjects(variants)
+				return nil, false, err
+			}
+
+			variants = append(variants, entities.AttachmentVariant{
+				ID:           objectKey,
+				AttachmentID: a.ID,
+				Width:        width,
+				Height:       resized.Bounds().Dy(),
+				Format:       format,
+				Size:         int64(len(encoded)),
+			})
+		}
+	}
+
+	return variants, false, nil
+}
+
+func (u *attachmentUsecase) deleteVariantObjects(variants []entities.AttachmentVariant) {
+	for _, v := range variants {
+		if err := u.attachmentRepository.DeleteAttachmentFromS3(u.cfg.GetAws().BucketName, v.ID); err != nil {
+			u.logger.Named("GenerateImageVariants").Error(constant.ErrDeleteAttachmentFromS3, zap.String("object_key", v.ID), zap.Error(err))
+		}
+	}
+}
+
+// maintenance
+
+// ReconcileStorage compares the bucket with the attachment rows. With remove it deletes the objects no row owns,
+// the attachments whose object is gone and the attachments left pending, and marks images whose variants are gone to be resized again.
+// Objects and pending rows younger than the grace period are left alone, they may belong to an upload in progress.
+func (u *attachmentUsecase) ReconcileStorage(remove bool) (*dtos.StorageReconcileDTO, *apperror.AppError) {
+	bucketName := u.cfg.GetAws().BucketName
+	cutoff := time.Now().Add(-constant.RECONCILE_GRACE_PERIOD)
+
+	// rows are read before the bucket is listed, so every committed row read has its object stored already.
+	// objects uploaded in between are younger than the grace period
+	attachments, err := u.attachmentRepository.FindAllAttachmentRecords()
+	if err != nil {
+		u.logger.Named("ReconcileStorage").Error(constant.ErrReconcileStorageFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrReconcileStorageFailed)
+	}
+	uploadKeys, err := u.attachmentRepository.FindAllAttachmentUploadKeys()
+	if err != nil {
+		u.logger.Named("ReconcileStorage").Error(constant.ErrReconcileStorageFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrReconcileStorageFailed)
+	}
+
+	owned := make(map[string]bool)
+	for _, a := range *attachments {
+		for _, objectKey := range attachmentObjectKeys(&a) {
+			owned[objectKey] = true
+		}
+	}
+	for _, objectKey := range uploadKeys {
+		owned[objectKey] = true
+	}
+
+	report := dtos.StorageReconcileDTO{
+		OrphanObjects:   []string{},
+		MissingObjects:  []string{},
+		MissingVariants: []string{},
+		StalePending:    []string{},
+		Removed:         remove,
+	}
+	stored := make(map[string]bool)
+	err = u.attachmentRepository.ListAttachmentObjects(bucketName, func(objectKey string, info *s3client.ObjectInfo) error {
+		stored[objectKey] = true
+		if !owned[objectKey] && info.LastModified.Before(cutoff) {
+			report.OrphanObjects = append(report.OrphanObjects, objectKey)
+		}
+		return nil
+	})
+	if err != nil {
+		u.logger.Named("ReconcileStorage").Error(constant.ErrReconcileStorageFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrReconcileStorageFailed)
+	}
+
+	var broken []entities.Attachment // attachments whose rows are removed
+	var resize []string              // attachments whose variants are made again
+	for _, a := range *attachments {
+		if a.State == constant.ATTACHMENT_PENDING {
+			if a.CreatedAt.Before(cutoff) {
+				report.StalePending = append(report.StalePending, a.ID)
+				broken = append(broken, a)
+			}
+			continue
+		}
+		if !stored[a.ObjectKey] {
+			report.MissingObjects = append(report.MissingObjects, a.ID)
+			broken = append(broken, a)
+			continue
+		}
+
+		missing := false
+		for _, v := range a.Variants {
+			if !stored[v.ID] {
+				report.MissingVariants = append(report.MissingVariants, v.ID)
+				missing = true
+			}
+		}
+		if missing {
+			resize = append(resize, a.ID)
+		}
+	}
+
+	if remove {
+		u.removeUnreconciled(bucketName, report.OrphanObjects, broken, resize)
+	}
+
+	u.logger.Named("ReconcileStorage").Info("Success: ",
+		zap.Bool("remove", remove),
+		zap.Int("orphan_objects", len(report.OrphanObjects)),
+		zap.Int("missing_objects", len(report.MissingObjects)),
+		zap.Int("missing_variants", len(report.MissingVariants)),
+		zap.Int("stale_pending", len(report.StalePending)),
+	)
+	return &report, nil
+}
+
+// removeUnreconciled fixes what ReconcileStorage found, a failure is logged and left for the next run
+func (u *attachmentUsecase) removeUnreconciled(bucketName string, orphans []string, broken []entities.Attachment, resize []string) {
+	for _, objectKey := range orphans {
+		if err := u.attachmentRepository.DeleteAttachmentFromS3(bucketName, objectKey); err != nil {
+			u.logger.Named("ReconcileStorage").Error(constant.ErrDeleteAttachmentFromS3, zap.String("object_key", objectKey), zap.Error(err))
+		}
+	}
+	for _, a := range broken {
+		u.purgeAttachment("ReconcileStorage", &a)
+	}
+	for _, ID := range resize {
+		if err := u.attachmentRepository.UpdateAttachmentVariantStatus(ID, constant.VARIANT_PENDING); err != nil {
+			u.logger.Named("ReconcileStorage").Error(constant.ErrReconcileStorageFailed, zap.String("attachment_id", ID), zap.Error(err))
+		}
+	}
+}
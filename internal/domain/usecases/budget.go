@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/apperror"
+)
+
+type BudgetUsecase interface {
+	// client side
+	GetBudgets(getBudgetsDTO *dtos.GetBudgetsDTO) (*dtos.PaginationResponse, *apperror.AppError)
+	GetBudget(documentID string) (*dtos.BudgetDTO, *apperror.AppError)
+	GetBudgetSummary(documentID string) (*dtos.BudgetSummaryDTO, *apperror.AppError)
+
+	// back office
+	UpdateBudget(req *dtos.UserDTO, documentID string, updateBudgetDTO *dtos.UpdateBudgetDTO) (*dtos.BudgetDTO, *apperror.AppError)
+	ImportBudget(req *dtos.UserDTO, documentID string, importBudgetDTO *dtos.ImportBudgetDTO) (*dtos.BudgetDTO, *apperror.AppError)
+	DeleteBudget(req *dtos.UserDTO, documentID string) *apperror.AppError
+}
@@ -0,0 +1,457 @@
+package usecases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/repositories"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/apperror"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+	"github.com/isd-sgcu/sucu-backend-2024/utils"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type budgetUsecase struct {
+	cfg                config.Config
+	logger             *zap.Logger
+	budgetRepository   repositories.BudgetRepository
+	documentRepository repositories.DocumentRepository
+}
+
+func NewBudgetUsecase(cfg config.Config, logger *zap.Logger, budgetRepository repositories.BudgetRepository, documentRepository repositories.DocumentRepository) BudgetUsecase {
+	return &budgetUsecase{
+		cfg:                cfg,
+		logger:             logger,
+		budgetRepository:   budgetRepository,
+		documentRepository: documentRepository,
+	}
+}
+
+// client side
+
+func (u *budgetUsecase) GetBudgets(getBudgetsDTO *dtos.GetBudgetsDTO) (*dtos.PaginationResponse, *apperror.AppError) {
+	args := &repositories.FindBudgetsArgs{
+		Offset:       (getBudgetsDTO.Page - 1) * getBudgetsDTO.PageSize,
+		Limit:        getBudgetsDTO.PageSize,
+		Organization: getBudgetsDTO.Organization,
+		FiscalYear:   getBudgetsDTO.FiscalYear,
+	}
+
+	budgets, total, err := u.budgetRepository.FindBudgets(args)
+	if err != nil {
+		u.logger.Named("GetBudgets").Error(constant.ErrGetBudgetFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetBudgetFailed)
+	}
+
+	data := make([]map[string]interface{}, 0)
+	for _, b := range *budgets {
+		data = append(data, map[string]interface{}{
+			"document_id":   b.DocumentID,
+			"title":         b.Document.Title,
+			"slug":          b.Document.Slug,
+			"fiscal_year":   b.FiscalYear,
+			"organization":  strings.ToLower(b.Organization),
+			"currency":      b.Currency,
+			"total_planned": budgetAmount(b.TotalPlanned),
+			"total_actual":  budgetAmount(b.TotalActual),
+			"variance":      budgetAmount(b.TotalActual - b.TotalPlanned),
+			"updated_at":    b.UpdatedAt,
+		})
+	}
+
+	paginationResponse := dtos.PaginationResponse{
+		Data:      data,
+		Page:      fmt.Sprintf("%d", getBudgetsDTO.Page),
+		Limit:     fmt.Sprintf("%d", getBudgetsDTO.PageSize),
+		TotalPage: fmt.Sprintf("%d", int(math.Ceil(float64(total)/float64(getBudgetsDTO.PageSize)))),
+	}
+
+	return &paginationResponse, nil
+}
+
+func (u *budgetUsecase) GetBudget(documentID string) (*dtos.BudgetDTO, *apperror.AppError) {
+	budget, apperr := u.findPublicBudget("GetBudget", documentID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	res := toBudgetDTO(budget)
+	return &res, nil
+}
+
+// GetBudgetSummary totals the items per category and compares actual spending with the plan
+func (u *budgetUsecase) GetBudgetSummary(documentID string) (*dtos.BudgetSummaryDTO, *apperror.AppError) {
+	budget, apperr := u.findPublicBudget("GetBudgetSummary", documentID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	type categoryTotal struct {
+		items           int
+		planned, actual int64
+	}
+	order := make([]string, 0)
+	totals := make(map[string]*categoryTotal)
+	for _, item := range budget.Items {
+		total, ok := totals[item.Category]
+		if !ok {
+			total = &categoryTotal{}
+			totals[item.Category] = total
+			order = append(order, item.Category)
+		}
+		total.items++
+		total.planned += item.Planned
+		total.actual += item.Actual
+	}
+
+	categories := make([]dtos.BudgetCategoryDTO, len(order))
+	for i, category := range order {
+		total := totals[category]
+		categories[i] = dtos.BudgetCategoryDTO{
+			Category:        category,
+			Items:           total.items,
+			Planned:         budgetAmount(total.planned),
+			Actual:          budgetAmount(total.actual),
+			Variance:        budgetAmount(total.actual - total.planned),
+			VariancePercent: variancePercent(total.planned, total.actual),
+		}
+	}
+
+	return &dtos.BudgetSummaryDTO{
+		DocumentID:      budget.DocumentID,
+		FiscalYear:      budget.FiscalYear,
+		Organization:    strings.ToLower(budget.Organization),
+		Currency:        budget.Currency,
+		TotalPlanned:    budgetAmount(budget.TotalPlanned),
+		TotalActual:     budgetAmount(budget.TotalActual),
+		Variance:        budgetAmount(budget.TotalActual - budget.TotalPlanned),
+		VariancePercent: variancePercent(budget.TotalPlanned, budget.TotalActual),
+		Categories:      categories,
+	}, nil
+}
+
+// back office
+
+func (u *budgetUsecase) UpdateBudget(req *dtos.UserDTO, documentID string, updateBudgetDTO *dtos.UpdateBudgetDTO) (*dtos.BudgetDTO, *apperror.AppError) {
+	document, apperr := u.findBudgetDocument("UpdateBudget", req, documentID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	if len(updateBudgetDTO.Items) > constant.MAX_BUDGET_ITEMS {
+		return nil, apperror.BadRequestError(constant.ErrTooManyBudgetItems)
+	}
+
+	items := make([]entities.BudgetItem, len(updateBudgetDTO.Items))
+	for i, item := range updateBudgetDTO.Items {
+		budgetItem, apperr := toBudgetItem(fmt.Sprintf("item %d", i+1), item.Category, item.Description, item.Planned.String(), item.Actual.String())
+		if apperr != nil {
+			return nil, apperr
+		}
+		items[i] = *budgetItem
+	}
+
+	budget := &entities.Budget{
+		DocumentID: document.ID,
+		FiscalYear: updateBudgetDTO.FiscalYear,
+		Currency:   updateBudgetDTO.Currency,
+		Items:      items,
+	}
+	return u.saveBudget("UpdateBudget", req, document, budget, updateBudgetDTO.TotalPlanned.String(), updateBudgetDTO.TotalActual.String())
+}
+
+// ImportBudget replaces the items of a budget with the rows of a CSV or XLSX file.
+// The first non-empty row names the columns, category and planned are required.
+func (u *budgetUsecase) ImportBudget(req *dtos.UserDTO, documentID string, importBudgetDTO *dtos.ImportBudgetDTO) (*dtos.BudgetDTO, *apperror.AppError) {
+	document, apperr := u.findBudgetDocument("ImportBudget", req, documentID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	file := importBudgetDTO.File
+	rows, err := utils.ReadSheetFile(file, constant.MAX_BUDGET_IMPORT_SIZE)
+	switch {
+	case errors.Is(err, utils.ErrSheetTooLarge):
+		return nil, apperror.BadRequestError(constant.ErrBudgetImportTooLarge)
+	case errors.Is(err, utils.ErrUnsupportedSheet):
+		return nil, apperror.BadRequestError(constant.ErrUnsupportedBudgetImport)
+	case err != nil:
+		u.logger.Named("ImportBudget").Error(constant.ErrInvalidBudgetImport, zap.String("document_id", documentID), zap.String("filename", file.Filename), zap.Error(err))
+		return nil, apperror.BadRequestError(fmt.Sprintf("%s: %s", constant.ErrInvalidBudgetImport, err.Error()))
+	}
+
+	items, apperr := toBudgetItemsFromRows(rows)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	budget := &entities.Budget{
+		DocumentID: document.ID,
+		FiscalYear: importBudgetDTO.FiscalYear,
+		Currency:   importBudgetDTO.Currency,
+		Items:      items,
+	}
+	return u.saveBudget("ImportBudget", req, document, budget, importBudgetDTO.TotalPlanned, importBudgetDTO.TotalActual)
+}
+
+func (u *budgetUsecase) DeleteBudget(req *dtos.UserDTO, documentID string) *apperror.AppError {
+	if _, apperr := u.findBudgetDocument("DeleteBudget", req, documentID); apperr != nil {
+		return apperr
+	}
+
+	if err := u.budgetRepository.DeleteBudget(documentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFoundError(constant.ErrBudgetNotFound)
+		}
+		u.logger.Named("DeleteBudget").Error(constant.ErrDeleteBudgetFailed, zap.String("document_id", documentID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrDeleteBudgetFailed)
+	}
+
+	u.logger.Named("DeleteBudget").Info("Success: Budget deleted", zap.String("document_id", documentID), zap.String("user_id", req.ID))
+	return nil
+}
+
+// saveBudget checks the budget and the totals the client declared, then stores it with the computed totals
+func (u *budgetUsecase) saveBudget(name string, req *dtos.UserDTO, document *entities.Document, budget *entities.Budget, totalPlanned, totalActual string) (*dtos.BudgetDTO, *apperror.AppError) {
+	if budget.FiscalYear < constant.MIN_FISCAL_YEAR || budget.FiscalYear > constant.MAX_FISCAL_YEAR {
+		return nil, apperror.BadRequestError(constant.ErrInvalidFiscalYear)
+	}
+
+	if !utils.ValidateBudgetCurrency(budget.Currency) {
+		return nil, apperror.BadRequestError(constant.ErrInvalidCurrency)
+	}
+	budget.Currency = strings.ToUpper(budget.Currency)
+	if budget.Currency == "" {
+		budget.Currency = constant.DEFAULT_BUDGET_CURRENCY
+	}
+
+	for i := range budget.Items {
+		budget.Items[i].DocumentID = document.ID
+		budget.Items[i].Position = i + 1
+		budget.TotalPlanned += budget.Items[i].Planned
+		budget.TotalActual += budget.Items[i].Actual
+	}
+
+	for _, declared := range []struct {
+		value string
+		total int64
+	}{
+		{totalPlanned, budget.TotalPlanned},
+		{totalActual, budget.TotalActual},
+	} {
+		if declared.value == "" {
+			continue
+		}
+		amount, err := utils.ParseAmount(declared.value, constant.BUDGET_CURRENCY_DECIMALS)
+		if err != nil {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s: %s", constant.ErrInvalidBudgetAmount, err.Error()))
+		}
+		if amount != declared.total {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s: %s declared, %s computed", constant.ErrBudgetTotalMismatch,
+				utils.FormatAmount(amount, constant.BUDGET_CURRENCY_DECIMALS), utils.FormatAmount(declared.total, constant.BUDGET_CURRENCY_DECIMALS)))
+		}
+	}
+
+	budget.Organization = utils.GetOrg(document.Author.RoleID)
+	budget.UpdatedBy = req.ID
+
+	if err := u.budgetRepository.SaveBudget(budget); err != nil {
+		u.logger.Named(name).Error(constant.ErrUpdateBudgetFailed, zap.String("document_id", document.ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrUpdateBudgetFailed)
+	}
+
+	saved, err := u.budgetRepository.FindBudgetByDocumentID(document.ID)
+	if err != nil {
+		u.logger.Named(name).Error(constant.ErrGetBudgetFailed, zap.String("document_id", document.ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetBudgetFailed)
+	}
+
+	u.logger.Named(name).Info("Success: Budget saved", zap.String("document_id", document.ID), zap.Int("items", len(saved.Items)), zap.String("user_id", req.ID))
+
+	res := toBudgetDTO(saved)
+	return &res, nil
+}
+
+// findPublicBudget finds the budget of a document anyone may open, see isPublic
+func (u *budgetUsecase) findPublicBudget(name, documentID string) (*entities.Budget, *apperror.AppError) {
+	document, err := u.documentRepository.FindDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named(name).Error(constant.ErrFindDocumentByID, zap.String("document_id", documentID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+	if !isPublic(document) {
+		return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+	}
+
+	budget, err := u.budgetRepository.FindBudgetByDocumentID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFoundError(constant.ErrBudgetNotFound)
+		}
+		u.logger.Named(name).Error(constant.ErrGetBudgetFailed, zap.String("document_id", documentID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetBudgetFailed)
+	}
+
+	return budget, nil
+}
+
+// findBudgetDocument finds a BUDGET document that req may edit, that is its author or a superadmin of the author's organization
+func (u *budgetUsecase) findBudgetDocument(name string, req *dtos.UserDTO, documentID string) (*entities.Document, *apperror.AppError) {
+	document, err := u.documentRepository.FindDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named(name).Error(constant.ErrDocumentNotFound, zap.String("document_id", documentID))
+			return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named(name).Error(constant.ErrFindDocumentByID, zap.String("document_id", documentID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named(name).Error(constant.ErrNotDocumentEditor, zap.String("document_id", documentID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	if document.TypeID != constant.BUDGET {
+		return nil, apperror.BadRequestError(constant.ErrNotBudgetDocument)
+	}
+
+	return document, nil
+}
+
+// toBudgetItemsFromRows reads the line items of an imported sheet, empty rows are skipped
+func toBudgetItemsFromRows(rows [][]string) ([]entities.BudgetItem, *apperror.AppError) {
+	header := 0
+	for header < len(rows) && utils.IsBlankRow(rows[header]) {
+		header++
+	}
+	if header == len(rows) {
+		return nil, apperror.BadRequestError(fmt.Sprintf("%s: %s", constant.ErrMissingBudgetImportColumn, "category"))
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[header] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	for _, required := range []string{"category", "planned"} {
+		if _, ok := columns[required]; !ok {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s: %s", constant.ErrMissingBudgetImportColumn, required))
+		}
+	}
+
+	cell := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	items := make([]entities.BudgetItem, 0)
+	for i := header + 1; i < len(rows); i++ {
+		row := rows[i]
+		if utils.IsBlankRow(row) {
+			continue
+		}
+		if len(items) == constant.MAX_BUDGET_ITEMS {
+			return nil, apperror.BadRequestError(constant.ErrTooManyBudgetItems)
+		}
+
+		item, apperr := toBudgetItem(fmt.Sprintf("row %d", i+1), cell(row, "category"), cell(row, "description"), cell(row, "planned"), cell(row, "actual"))
+		if apperr != nil {
+			return nil, apperr
+		}
+		items = append(items, *item)
+	}
+
+	return items, nil
+}
+
+// toBudgetItem checks one line item, label names it in error messages
+func toBudgetItem(label, category, description, planned, actual string) (*entities.BudgetItem, *apperror.AppError) {
+	category = strings.TrimSpace(category)
+	description = strings.TrimSpace(description)
+	if category == "" || utf8.RuneCountInString(category) > 100 || utf8.RuneCountInString(description) > 255 {
+		return nil, apperror.BadRequestError(fmt.Sprintf("invalid category or description of %s", label))
+	}
+
+	item := &entities.BudgetItem{
+		Category:    category,
+		Description: description,
+	}
+
+	for _, amount := range []struct {
+		value string
+		dest  *int64
+	}{
+		{planned, &item.Planned},
+		{actual, &item.Actual},
+	} {
+		if amount.value == "" {
+			continue
+		}
+		minor, err := utils.ParseAmount(amount.value, constant.BUDGET_CURRENCY_DECIMALS)
+		if err != nil {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s of %s: %s", constant.ErrInvalidBudgetAmount, label, err.Error()))
+		}
+		if minor < 0 || minor > constant.MAX_BUDGET_AMOUNT {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s of %s: out of range", constant.ErrInvalidBudgetAmount, label))
+		}
+		*amount.dest = minor
+	}
+
+	return item, nil
+}
+
+func toBudgetDTO(budget *entities.Budget) dtos.BudgetDTO {
+	items := make([]dtos.BudgetItemDTO, len(budget.Items))
+	for i, item := range budget.Items {
+		items[i] = dtos.BudgetItemDTO{
+			Category:    item.Category,
+			Description: item.Description,
+			Planned:     budgetAmount(item.Planned),
+			Actual:      budgetAmount(item.Actual),
+		}
+	}
+
+	return dtos.BudgetDTO{
+		DocumentID:   budget.DocumentID,
+		FiscalYear:   budget.FiscalYear,
+		Organization: strings.ToLower(budget.Organization),
+		Currency:     budget.Currency,
+		TotalPlanned: budgetAmount(budget.TotalPlanned),
+		TotalActual:  budgetAmount(budget.TotalActual),
+		Variance:     budgetAmount(budget.TotalActual - budget.TotalPlanned),
+		Items:        items,
+		UpdatedBy:    budget.UpdatedBy,
+		CreatedAt:    budget.CreatedAt,
+		UpdatedAt:    budget.UpdatedAt,
+	}
+}
+
+// budgetAmount writes minor units as an exact JSON number
+func budgetAmount(minor int64) json.Number {
+	return json.Number(utils.FormatAmount(minor, constant.BUDGET_CURRENCY_DECIMALS))
+}
+
+// variancePercent is the variance relative to planned in percent with two decimals, nil when nothing was planned
+func variancePercent(planned, actual int64) *float64 {
+	if planned == 0 {
+		return nil
+	}
+	percent := math.Round(float64(actual-planned)/float64(planned)*100*100) / 100
+	return &percent
+}
@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/apperror"
+)
+
+type DatasetUsecase interface {
+	// client side
+	GetDataset(documentID string) (*dtos.DatasetDTO, *apperror.AppError)
+	GetDatasetChart(documentID string, query *dtos.DatasetQueryDTO) (*dtos.DatasetChartDTO, *apperror.AppError)
+	AggregateDataset(documentID string, query *dtos.DatasetQueryDTO) (*dtos.DatasetAggregateDTO, *apperror.AppError)
+
+	// back office
+	UpdateDataset(req *dtos.UserDTO, documentID string, updateDatasetDTO *dtos.UpdateDatasetDTO) (*dtos.DatasetDTO, *apperror.AppError)
+	ImportDataset(req *dtos.UserDTO, documentID string, importDatasetDTO *dtos.ImportDatasetDTO) (*dtos.DatasetDTO, *apperror.AppError)
+	DeleteDataset(req *dtos.UserDTO, documentID string) *apperror.AppError
+}
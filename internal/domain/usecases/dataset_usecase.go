@@ -0,0 +1,620 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/repositories"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/apperror"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+	"github.com/isd-sgcu/sucu-backend-2024/utils"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type datasetUsecase struct {
+	cfg                config.Config
+	logger             *zap.Logger
+	datasetRepository  repositories.DatasetRepository
+	documentRepository repositories.DocumentRepository
+}
+
+func NewDatasetUsecase(cfg config.Config, logger *zap.Logger, datasetRepository repositories.DatasetRepository, documentRepository repositories.DocumentRepository) DatasetUsecase {
+	return &datasetUsecase{
+		cfg:                cfg,
+		logger:             logger,
+		datasetRepository:  datasetRepository,
+		documentRepository: documentRepository,
+	}
+}
+
+// dimension names are used as JSON keys and query parameters
+var datasetDimensionName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// client side
+
+func (u *datasetUsecase) GetDataset(documentID string) (*dtos.DatasetDTO, *apperror.AppError) {
+	dataset, apperr := u.findPublicDataset("GetDataset", documentID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	res := toDatasetDTO(dataset)
+	return &res, nil
+}
+
+// GetDatasetChart aggregates every series by one field and aligns the values on a shared list of labels
+func (u *datasetUsecase) GetDatasetChart(documentID string, query *dtos.DatasetQueryDTO) (*dtos.DatasetChartDTO, *apperror.AppError) {
+	dataset, apperr := u.findPublicDataset("GetDatasetChart", documentID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	if len(query.GroupBy) == 0 {
+		query.GroupBy = []string{constant.DATASET_LABEL}
+	}
+	if len(query.GroupBy) > 1 {
+		return nil, apperror.BadRequestError(fmt.Sprintf("%s: a chart is grouped by one field", constant.ErrInvalidDatasetDimension))
+	}
+
+	grouped, apperr := aggregateDataset(dataset, query)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	labels := make([]string, 0)
+	index := make(map[string]int)
+	for _, series := range grouped {
+		for _, group := range series.groups {
+			if _, ok := index[group.key[0]]; !ok {
+				index[group.key[0]] = len(labels)
+				labels = append(labels, group.key[0])
+			}
+		}
+	}
+
+	res := &dtos.DatasetChartDTO{
+		GroupBy:   query.GroupBy[0],
+		Aggregate: query.Aggregate,
+		Labels:    labels,
+		Series:    make([]dtos.DatasetChartSeriesDTO, len(grouped)),
+	}
+	for i, series := range grouped {
+		data := make([]*float64, len(labels))
+		for _, group := range series.groups {
+			value := group.value(query.Aggregate)
+			data[index[group.key[0]]] = &value
+		}
+		res.Series[i] = dtos.DatasetChartSeriesDTO{
+			Name: series.name,
+			Unit: series.unit,
+			Data: data,
+		}
+	}
+
+	return res, nil
+}
+
+// AggregateDataset groups the points of every series by any number of fields, no fields gives one total per series
+func (u *datasetUsecase) AggregateDataset(documentID string, query *dtos.DatasetQueryDTO) (*dtos.DatasetAggregateDTO, *apperror.AppError) {
+	dataset, apperr := u.findPublicDataset("AggregateDataset", documentID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	grouped, apperr := aggregateDataset(dataset, query)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	res := &dtos.DatasetAggregateDTO{
+		GroupBy:   query.GroupBy,
+		Aggregate: query.Aggregate,
+		Series:    make([]dtos.DatasetGroupedSeriesDTO, len(grouped)),
+	}
+	for i, series := range grouped {
+		groups := make([]dtos.DatasetGroupDTO, len(series.groups))
+		for j, group := range series.groups {
+			key := make(map[string]string, len(query.GroupBy))
+			for k, name := range query.GroupBy {
+				key[name] = group.key[k]
+			}
+			groups[j] = dtos.DatasetGroupDTO{
+				Key:    key,
+				Value:  group.value(query.Aggregate),
+				Points: group.points,
+			}
+		}
+		res.Series[i] = dtos.DatasetGroupedSeriesDTO{
+			Name:   series.name,
+			Unit:   series.unit,
+			Groups: groups,
+		}
+	}
+
+	return res, nil
+}
+
+// back office
+
+func (u *datasetUsecase) UpdateDataset(req *dtos.UserDTO, documentID string, updateDatasetDTO *dtos.UpdateDatasetDTO) (*dtos.DatasetDTO, *apperror.AppError) {
+	document, apperr := u.findStatisticDocument("UpdateDataset", req, documentID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	return u.saveDataset("UpdateDataset", req, document, updateDatasetDTO)
+}
+
+// ImportDataset replaces a dataset with the rows of a CSV or XLSX file in long form. The first
+// non-empty row names the columns: value is required, series, label and unit are optional and
+// every other column is a dimension.
+func (u *datasetUsecase) ImportDataset(req *dtos.UserDTO, documentID string, importDatasetDTO *dtos.ImportDatasetDTO) (*dtos.DatasetDTO, *apperror.AppError) {
+	document, apperr := u.findStatisticDocument("ImportDataset", req, documentID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	file := importDatasetDTO.File
+	rows, err := utils.ReadSheetFile(file, constant.MAX_DATASET_IMPORT_SIZE)
+	switch {
+	case errors.Is(err, utils.ErrSheetTooLarge):
+		return nil, apperror.BadRequestError(constant.ErrDatasetImportTooLarge)
+	case errors.Is(err, utils.ErrUnsupportedSheet):
+		return nil, apperror.BadRequestError(constant.ErrUnsupportedDatasetImport)
+	case err != nil:
+		u.logger.Named("ImportDataset").Error(constant.ErrInvalidDatasetImport, zap.String("document_id", documentID), zap.String("filename", file.Filename), zap.Error(err))
+		return nil, apperror.BadRequestError(fmt.Sprintf("%s: %s", constant.ErrInvalidDatasetImport, err.Error()))
+	}
+
+	updateDatasetDTO, apperr := toDatasetFromRows(rows, importDatasetDTO.Series, importDatasetDTO.Unit)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	return u.saveDataset("ImportDataset", req, document, updateDatasetDTO)
+}
+
+func (u *datasetUsecase) DeleteDataset(req *dtos.UserDTO, documentID string) *apperror.AppError {
+	if _, apperr := u.findStatisticDocument("DeleteDataset", req, documentID); apperr != nil {
+		return apperr
+	}
+
+	if err := u.datasetRepository.DeleteDataset(documentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFoundError(constant.ErrDatasetNotFound)
+		}
+		u.logger.Named("DeleteDataset").Error(constant.ErrDeleteDatasetFailed, zap.String("document_id", documentID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrDeleteDatasetFailed)
+	}
+
+	u.logger.Named("DeleteDataset").Info("Success: Dataset deleted", zap.String("document_id", documentID), zap.String("user_id", req.ID))
+	return nil
+}
+
+// saveDataset checks every dimension, series and point and stores the dataset in place of the old one
+func (u *datasetUsecase) saveDataset(name string, req *dtos.UserDTO, document *entities.Document, updateDatasetDTO *dtos.UpdateDatasetDTO) (*dtos.DatasetDTO, *apperror.AppError) {
+	if len(updateDatasetDTO.Dimensions) > constant.MAX_DATASET_DIMENSIONS {
+		return nil, apperror.BadRequestError(constant.ErrTooManyDatasetDimensions)
+	}
+	if len(updateDatasetDTO.Series) > constant.MAX_DATASET_SERIES {
+		return nil, apperror.BadRequestError(constant.ErrTooManyDatasetSeries)
+	}
+
+	dataset := &entities.Dataset{
+		DocumentID: document.ID,
+		Dimensions: make([]string, 0, len(updateDatasetDTO.Dimensions)),
+		UpdatedBy:  req.ID,
+		Series:     make([]entities.DatasetSeries, 0, len(updateDatasetDTO.Series)),
+		Points:     make([]entities.DatasetPoint, 0),
+	}
+
+	declared := make(map[string]bool)
+	for _, dimension := range updateDatasetDTO.Dimensions {
+		dimension = strings.ToLower(strings.TrimSpace(dimension))
+		if !datasetDimensionName.MatchString(dimension) || isReservedDatasetColumn(dimension) || declared[dimension] {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s: %q", constant.ErrInvalidDatasetDimension, dimension))
+		}
+		declared[dimension] = true
+		dataset.Dimensions = append(dataset.Dimensions, dimension)
+	}
+
+	names := make(map[string]bool)
+	for i, series := range updateDatasetDTO.Series {
+		seriesName := strings.TrimSpace(series.Name)
+		unit := strings.TrimSpace(series.Unit)
+		if seriesName == "" || names[seriesName] || utf8.RuneCountInString(seriesName) > 100 || utf8.RuneCountInString(unit) > 50 {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s: %q", constant.ErrInvalidDatasetSeries, seriesName))
+		}
+		names[seriesName] = true
+
+		if len(dataset.Points)+len(series.Points) > constant.MAX_DATASET_POINTS {
+			return nil, apperror.BadRequestError(constant.ErrTooManyDatasetPoints)
+		}
+
+		dataset.Series = append(dataset.Series, entities.DatasetSeries{
+			DocumentID: document.ID,
+			Position:   i + 1,
+			Name:       seriesName,
+			Unit:       unit,
+		})
+
+		for j, point := range series.Points {
+			invalid := apperror.BadRequestError(fmt.Sprintf("%s: point %d of series %q", constant.ErrInvalidDatasetPoint, j+1, seriesName))
+
+			label := strings.TrimSpace(point.Label)
+			if point.Value == nil || math.IsNaN(*point.Value) || math.IsInf(*point.Value, 0) || utf8.RuneCountInString(label) > 255 {
+				return nil, invalid
+			}
+
+			dimensions := make(map[string]string, len(point.Dimensions))
+			for dimension, value := range point.Dimensions {
+				dimension = strings.ToLower(strings.TrimSpace(dimension))
+				value = strings.TrimSpace(value)
+				if !declared[dimension] || utf8.RuneCountInString(value) > 255 {
+					return nil, invalid
+				}
+				if value != "" {
+					dimensions[dimension] = value
+				}
+			}
+
+			dataset.Points = append(dataset.Points, entities.DatasetPoint{
+				DocumentID: document.ID,
+				Series:     i + 1,
+				Position:   j + 1,
+				Label:      label,
+				Value:      *point.Value,
+				Dimensions: dimensions,
+			})
+		}
+	}
+
+	if err := u.datasetRepository.SaveDataset(dataset); err != nil {
+		u.logger.Named(name).Error(constant.ErrUpdateDatasetFailed, zap.String("document_id", document.ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrUpdateDatasetFailed)
+	}
+
+	saved, err := u.datasetRepository.FindDatasetByDocumentID(document.ID)
+	if err != nil {
+		u.logger.Named(name).Error(constant.ErrGetDatasetFailed, zap.String("document_id", document.ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDatasetFailed)
+	}
+
+	u.logger.Named(name).Info("Success: Dataset saved", zap.String("document_id", document.ID), zap.Int("series", len(saved.Series)), zap.Int("points", len(saved.Points)), zap.String("user_id", req.ID))
+
+	res := toDatasetDTO(saved)
+	return &res, nil
+}
+
+// findPublicDataset finds the dataset of a document anyone may open, see isPublic
+func (u *datasetUsecase) findPublicDataset(name, documentID string) (*entities.Dataset, *apperror.AppError) {
+	document, err := u.documentRepository.FindDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named(name).Error(constant.ErrFindDocumentByID, zap.String("document_id", documentID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+	if !isPublic(document) {
+		return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+	}
+
+	dataset, err := u.datasetRepository.FindDatasetByDocumentID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFoundError(constant.ErrDatasetNotFound)
+		}
+		u.logger.Named(name).Error(constant.ErrGetDatasetFailed, zap.String("document_id", documentID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDatasetFailed)
+	}
+
+	return dataset, nil
+}
+
+// findStatisticDocument finds a STATISTIC document that req may edit, see isDocumentEditor
+func (u *datasetUsecase) findStatisticDocument(name string, req *dtos.UserDTO, documentID string) (*entities.Document, *apperror.AppError) {
+	document, err := u.documentRepository.FindDocumentByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named(name).Error(constant.ErrDocumentNotFound, zap.String("document_id", documentID))
+			return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named(name).Error(constant.ErrFindDocumentByID, zap.String("document_id", documentID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named(name).Error(constant.ErrNotDocumentEditor, zap.String("document_id", documentID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	if document.TypeID != constant.STATISTIC {
+		return nil, apperror.BadRequestError(constant.ErrNotStatisticDocument)
+	}
+
+	return document, nil
+}
+
+// toDatasetFromRows turns an imported sheet into the series it describes, series keep the order they first appear in
+func toDatasetFromRows(rows [][]string, defaultSeries, defaultUnit string) (*dtos.UpdateDatasetDTO, *apperror.AppError) {
+	header := 0
+	for header < len(rows) && utils.IsBlankRow(rows[header]) {
+		header++
+	}
+	if header == len(rows) {
+		return nil, apperror.BadRequestError(constant.ErrMissingDatasetImportColumn)
+	}
+
+	columns := make(map[string]int)
+	dimensions := make([]string, 0)
+	for i, name := range rows[header] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; ok || name == "" {
+			continue
+		}
+		columns[name] = i
+		if !isReservedDatasetColumn(name) {
+			dimensions = append(dimensions, name)
+		}
+	}
+	if _, ok := columns[constant.DATASET_VALUE]; !ok {
+		return nil, apperror.BadRequestError(constant.ErrMissingDatasetImportColumn)
+	}
+
+	cell := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	if defaultSeries = strings.TrimSpace(defaultSeries); defaultSeries == "" {
+		defaultSeries = constant.DEFAULT_DATASET_SERIES
+	}
+
+	res := &dtos.UpdateDatasetDTO{
+		Dimensions: dimensions,
+		Series:     make([]dtos.DatasetSeriesDTO, 0),
+	}
+	seriesIndex := make(map[string]int)
+	points := 0
+
+	for i := header + 1; i < len(rows); i++ {
+		row := rows[i]
+		if utils.IsBlankRow(row) {
+			continue
+		}
+		if points == constant.MAX_DATASET_POINTS {
+			return nil, apperror.BadRequestError(constant.ErrTooManyDatasetPoints)
+		}
+		points++
+
+		value, err := strconv.ParseFloat(strings.ReplaceAll(cell(row, constant.DATASET_VALUE), ",", ""), 64)
+		if err != nil {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s: row %d", constant.ErrInvalidDatasetPoint, i+1))
+		}
+
+		seriesName := cell(row, constant.DATASET_SERIES)
+		if seriesName == "" {
+			seriesName = defaultSeries
+		}
+		unit := cell(row, constant.DATASET_UNIT)
+
+		s, ok := seriesIndex[seriesName]
+		if !ok {
+			if len(res.Series) == constant.MAX_DATASET_SERIES {
+				return nil, apperror.BadRequestError(constant.ErrTooManyDatasetSeries)
+			}
+			s = len(res.Series)
+			seriesIndex[seriesName] = s
+			res.Series = append(res.Series, dtos.DatasetSeriesDTO{
+				Name:   seriesName,
+				Unit:   strings.TrimSpace(defaultUnit),
+				Points: make([]dtos.DatasetPointDTO, 0),
+			})
+		}
+
+		series := &res.Series[s]
+		if unit != "" {
+			if len(series.Points) > 0 && series.Unit != unit {
+				return nil, apperror.BadRequestError(fmt.Sprintf("%s: row %d changes the unit of series %q", constant.ErrInvalidDatasetSeries, i+1, seriesName))
+			}
+			series.Unit = unit
+		}
+
+		point := dtos.DatasetPointDTO{
+			Label:      cell(row, constant.DATASET_LABEL),
+			Value:      &value,
+			Dimensions: make(map[string]string, len(dimensions)),
+		}
+		for _, dimension := range dimensions {
+			point.Dimensions[dimension] = cell(row, dimension)
+		}
+		series.Points = append(series.Points, point)
+	}
+
+	return res, nil
+}
+
+func isReservedDatasetColumn(name string) bool {
+	for _, reserved := range constant.DatasetReservedColumns {
+		if name == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+// datasetGroup accumulates the points of one series that share the values of the grouped fields
+type datasetGroup struct {
+	key      []string
+	sum      float64
+	min, max float64
+	points   int
+}
+
+func (g *datasetGroup) add(value float64) {
+	if g.points == 0 || value < g.min {
+		g.min = value
+	}
+	if g.points == 0 || value > g.max {
+		g.max = value
+	}
+	g.sum += value
+	g.points++
+}
+
+func (g *datasetGroup) value(aggregate string) float64 {
+	switch aggregate {
+	case constant.AGGREGATE_AVG:
+		return g.sum / float64(g.points)
+	case constant.AGGREGATE_MIN:
+		return g.min
+	case constant.AGGREGATE_MAX:
+		return g.max
+	case constant.AGGREGATE_COUNT:
+		return float64(g.points)
+	}
+	return g.sum
+}
+
+type datasetGroupedSeries struct {
+	name, unit string
+	groups     []*datasetGroup // in order of first appearance
+}
+
+// aggregateDataset checks the fields of query against the dataset and groups the points it selects
+func aggregateDataset(dataset *entities.Dataset, query *dtos.DatasetQueryDTO) ([]datasetGroupedSeries, *apperror.AppError) {
+	known := map[string]bool{constant.DATASET_LABEL: true}
+	for _, dimension := range dataset.Dimensions {
+		known[dimension] = true
+	}
+	for _, name := range query.GroupBy {
+		if !known[name] {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s: %q", constant.ErrInvalidDatasetDimension, name))
+		}
+	}
+	for name := range query.Filters {
+		if !known[name] {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s: %q", constant.ErrInvalidDatasetFilter, name))
+		}
+	}
+
+	selected := make(map[int]bool)
+	for _, name := range query.Series {
+		found := false
+		for _, series := range dataset.Series {
+			if series.Name == name {
+				selected[series.Position] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s: %q", constant.ErrInvalidDatasetSeries, name))
+		}
+	}
+
+	res := make([]datasetGroupedSeries, 0, len(dataset.Series))
+	position := make(map[int]int)
+	for _, series := range dataset.Series {
+		if len(selected) > 0 && !selected[series.Position] {
+			continue
+		}
+		position[series.Position] = len(res)
+		res = append(res, datasetGroupedSeries{
+			name:   series.Name,
+			unit:   series.Unit,
+			groups: make([]*datasetGroup, 0),
+		})
+	}
+
+	groups := make(map[int]map[string]*datasetGroup)
+	for _, point := range dataset.Points {
+		i, ok := position[point.Series]
+		if !ok || !matchDatasetFilters(&point, query.Filters) {
+			continue
+		}
+
+		key := make([]string, len(query.GroupBy))
+		for k, name := range query.GroupBy {
+			key[k] = datasetField(&point, name)
+		}
+		// the unit separator cannot be typed into a cell, so joined keys do not collide
+		joined := strings.Join(key, "\x1f")
+
+		if groups[i] == nil {
+			groups[i] = make(map[string]*datasetGroup)
+		}
+		group, ok := groups[i][joined]
+		if !ok {
+			group = &datasetGroup{key: key}
+			groups[i][joined] = group
+			res[i].groups = append(res[i].groups, group)
+		}
+		group.add(point.Value)
+	}
+
+	return res, nil
+}
+
+func matchDatasetFilters(point *entities.DatasetPoint, filters map[string]string) bool {
+	for name, value := range filters {
+		if datasetField(point, name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// datasetField is the label or the value of a dimension of a point, empty when the point has none
+func datasetField(point *entities.DatasetPoint, name string) string {
+	if name == constant.DATASET_LABEL {
+		return point.Label
+	}
+	return point.Dimensions[name]
+}
+
+func toDatasetDTO(dataset *entities.Dataset) dtos.DatasetDTO {
+	series := make([]dtos.DatasetSeriesDTO, len(dataset.Series))
+	index := make(map[int]int)
+	for i, s := range dataset.Series {
+		index[s.Position] = i
+		series[i] = dtos.DatasetSeriesDTO{
+			Name:   s.Name,
+			Unit:   s.Unit,
+			Points: make([]dtos.DatasetPointDTO, 0),
+		}
+	}
+
+	for _, point := range dataset.Points {
+		i, ok := index[point.Series]
+		if !ok {
+			continue
+		}
+		value := point.Value
+		series[i].Points = append(series[i].Points, dtos.DatasetPointDTO{
+			Label:      point.Label,
+			Value:      &value,
+			Dimensions: point.Dimensions,
+		})
+	}
+
+	return dtos.DatasetDTO{
+		DocumentID: dataset.DocumentID,
+		Dimensions: dataset.Dimensions,
+		Series:     series,
+		UpdatedBy:  dataset.UpdatedBy,
+		CreatedAt:  dataset.CreatedAt,
+		UpdatedAt:  dataset.UpdatedAt,
+	}
+}
@@ -8,11 +8,54 @@ import (
 type DocumentUsecase interface {
 	// client side
 	GetAllDocuments(req *dtos.GetAllDocumentsDTO) (*dtos.PaginationResponse, *apperror.AppError)
-	GetDocumentByID(ID string) (*dtos.DocumentDTO, *apperror.AppError)
+	GetDocumentByID(ID, lang string) (*dtos.DocumentDTO, *apperror.AppError)
+	GetDocumentBySlug(slug, lang string) (*dtos.DocumentDTO, *apperror.AppError)
 
 	// back office
 	GetDocumentsByRole(req *dtos.GetAllDocumentsByRoleDTO) (*dtos.PaginationResponse, *apperror.AppError)
 	CreateDocument(document *dtos.CreateDocumentDTO) *apperror.AppError
-	UpdateDocumentByID(ID string, updateMap interface{}) *apperror.AppError
+	UpdateDocumentByID(req *dtos.UserDTO, ID string, updateDocumentDTO *dtos.UpdateDocumentDTO) *apperror.AppError
 	DeleteDocumentByID(ID string) *apperror.AppError
+	UpdateDocumentTags(req *dtos.UserDTO, ID string, updateTagsDTO *dtos.UpdateDocumentTagsDTO) *apperror.AppError
+	UpdateDocumentSlug(req *dtos.UserDTO, ID string, updateSlugDTO *dtos.UpdateDocumentSlugDTO) *apperror.AppError
+	UpdateDocumentExpiry(req *dtos.UserDTO, ID string, updateExpiryDTO *dtos.UpdateDocumentExpiryDTO) *apperror.AppError
+	BulkUpdateDocuments(req *dtos.UserDTO, bulkDTO *dtos.BulkDocumentsDTO) (*dtos.BulkDocumentsResultDTO, *apperror.AppError)
+
+	// translation
+	GetDocumentTranslations(req *dtos.UserDTO, ID string) (*[]dtos.DocumentTranslationDTO, *apperror.AppError)
+	UpdateDocumentTranslation(req *dtos.UserDTO, ID, locale string, translationDTO *dtos.UpdateDocumentTranslationDTO) *apperror.AppError
+	DeleteDocumentTranslation(req *dtos.UserDTO, ID, locale string) *apperror.AppError
+
+	// featured
+	GetFeaturedDocuments(organization, lang string) (*[]dtos.FeaturedDocumentDTO, *apperror.AppError)
+	GetFeaturedSlots(req *dtos.UserDTO, scope string) (*[]dtos.FeaturedDocumentDTO, *apperror.AppError)
+	UpdateFeaturedDocuments(req *dtos.UserDTO, scope string, updateFeaturedDTO *dtos.UpdateFeaturedDocumentsDTO) *apperror.AppError
+	DeleteFeaturedDocument(req *dtos.UserDTO, scope, documentID string) *apperror.AppError
+
+	// link
+	GetDocumentLinks(req *dtos.UserDTO, ID string) (*dtos.DocumentLinksDTO, *apperror.AppError)
+	UpdateDocumentLinks(req *dtos.UserDTO, ID string, updateLinksDTO *dtos.UpdateDocumentLinksDTO) *apperror.AppError
+
+	// review
+	GetPendingDocuments(req *dtos.UserDTO, pagination *dtos.GetPendingDocumentsDTO) (*dtos.PaginationResponse, *apperror.AppError)
+	GetDocumentReviews(req *dtos.UserDTO, ID string) (*[]dtos.DocumentReviewDTO, *apperror.AppError)
+	SubmitDocument(req *dtos.UserDTO, ID string) *apperror.AppError
+	ApproveDocument(req *dtos.UserDTO, ID string) *apperror.AppError
+	RejectDocument(req *dtos.UserDTO, ID string, reviewDTO *dtos.ReviewDocumentDTO) *apperror.AppError
+	RequestDocumentChanges(req *dtos.UserDTO, ID string, reviewDTO *dtos.ReviewDocumentDTO) *apperror.AppError
+
+	// revision
+	GetDocumentRevisions(req *dtos.UserDTO, ID string) (*[]dtos.DocumentRevisionDTO, *apperror.AppError)
+	GetDocumentRevision(req *dtos.UserDTO, ID string, revision int) (*dtos.DocumentRevisionDTO, *apperror.AppError)
+	DiffDocumentRevisions(req *dtos.UserDTO, ID string, from, to int) (*dtos.DocumentRevisionDiffDTO, *apperror.AppError)
+	RestoreDocumentRevision(req *dtos.UserDTO, ID string, revision int) *apperror.AppError
+
+	// trash
+	GetTrashedDocuments(req *dtos.UserDTO, pagination *dtos.GetTrashedDocumentsDTO) (*dtos.PaginationResponse, *apperror.AppError)
+	RestoreDocumentByID(req *dtos.UserDTO, ID string) *apperror.AppError
+	PurgeDocumentByID(req *dtos.UserDTO, ID string) *apperror.AppError
+
+	// job
+	PurgeExpiredTrash()
+	ArchiveExpiredDocuments()
 }
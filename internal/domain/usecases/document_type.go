@@ -0,0 +1,16 @@
+package usecases
+
+import (
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/apperror"
+)
+
+type DocumentTypeUsecase interface {
+	GetAllDocumentTypes() (*[]dtos.DocumentTypeDTO, *apperror.AppError)
+	GetDocumentTypeByID(ID string) (*dtos.DocumentTypeDTO, *apperror.AppError)
+
+	// super-admin method
+	CreateDocumentType(createDocumentTypeDTO *dtos.CreateDocumentTypeDTO) (*dtos.DocumentTypeDTO, *apperror.AppError)
+	UpdateDocumentTypeByID(ID string, updateDocumentTypeDTO *dtos.UpdateDocumentTypeDTO) *apperror.AppError
+	DeleteDocumentTypeByID(ID string) *apperror.AppError
+}
@@ -0,0 +1,193 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/repositories"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/apperror"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+	"github.com/isd-sgcu/sucu-backend-2024/utils"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type documentTypeUsecase struct {
+	cfg                    config.Config
+	logger                 *zap.Logger
+	documentTypeRepository repositories.DocumentTypeRepository
+}
+
+func NewDocumentTypeUsecase(cfg config.Config, logger *zap.Logger, documentTypeRepository repositories.DocumentTypeRepository) DocumentTypeUsecase {
+	return &documentTypeUsecase{
+		cfg:                    cfg,
+		logger:                 logger,
+		documentTypeRepository: documentTypeRepository,
+	}
+}
+
+func (u *documentTypeUsecase) GetAllDocumentTypes() (*[]dtos.DocumentTypeDTO, *apperror.AppError) {
+	documentTypes, err := u.documentTypeRepository.FindAllDocumentTypes()
+	if err != nil {
+		u.logger.Named("GetAllDocumentTypes").Error(constant.ErrGetDocumentTypeFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentTypeFailed)
+	}
+
+	res := make([]dtos.DocumentTypeDTO, len(*documentTypes))
+	for i, t := range *documentTypes {
+		res[i] = toDocumentTypeDTO(&t)
+	}
+
+	return &res, nil
+}
+
+func (u *documentTypeUsecase) GetDocumentTypeByID(ID string) (*dtos.DocumentTypeDTO, *apperror.AppError) {
+	documentType, apperr := u.findDocumentType("GetDocumentTypeByID", utils.NormalizeDocTypeID(ID))
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	res := toDocumentTypeDTO(documentType)
+	return &res, nil
+}
+
+func (u *documentTypeUsecase) CreateDocumentType(createDocumentTypeDTO *dtos.CreateDocumentTypeDTO) (*dtos.DocumentTypeDTO, *apperror.AppError) {
+	ID := utils.NormalizeDocTypeID(createDocumentTypeDTO.ID)
+	if !utils.ValidDocTypeID(ID) {
+		return nil, apperror.BadRequestError(constant.ErrInvalidDocTypeID)
+	}
+
+	existingType, err := u.documentTypeRepository.FindDocumentTypeByID(ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		u.logger.Named("CreateDocumentType").Error(constant.ErrGetDocumentTypeFailed, zap.String("type_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentTypeFailed)
+	}
+
+	if existingType != nil {
+		u.logger.Named("CreateDocumentType").Error(constant.ErrDocumentTypeAlreadyExists, zap.String("type_id", ID))
+		return nil, apperror.BadRequestError(constant.ErrDocumentTypeAlreadyExists)
+	}
+
+	documentType := &entities.DocumentType{
+		ID:             ID,
+		NameTH:         strings.TrimSpace(createDocumentTypeDTO.NameTH),
+		NameEN:         strings.TrimSpace(createDocumentTypeDTO.NameEN),
+		Description:    createDocumentTypeDTO.Description,
+		SortOrder:      createDocumentTypeDTO.SortOrder,
+		RequiresReview: createDocumentTypeDTO.RequiresReview,
+	}
+
+	if err := u.documentTypeRepository.InsertDocumentType(documentType); err != nil {
+		u.logger.Named("CreateDocumentType").Error(constant.ErrInsertDocumentTypeFailed, zap.String("type_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrInsertDocumentTypeFailed)
+	}
+
+	u.logger.Named("CreateDocumentType").Info("Success: ", zap.String("type_id", ID))
+
+	res := toDocumentTypeDTO(documentType)
+	return &res, nil
+}
+
+func (u *documentTypeUsecase) UpdateDocumentTypeByID(ID string, updateDocumentTypeDTO *dtos.UpdateDocumentTypeDTO) *apperror.AppError {
+	ID = utils.NormalizeDocTypeID(ID)
+
+	updateMap := make(map[string]interface{})
+	if updateDocumentTypeDTO.NameTH != nil {
+		updateMap["name_th"] = strings.TrimSpace(*updateDocumentTypeDTO.NameTH)
+	}
+	if updateDocumentTypeDTO.NameEN != nil {
+		updateMap["name_en"] = strings.TrimSpace(*updateDocumentTypeDTO.NameEN)
+	}
+	if updateDocumentTypeDTO.Description != nil {
+		updateMap["description"] = *updateDocumentTypeDTO.Description
+	}
+	if updateDocumentTypeDTO.SortOrder != nil {
+		updateMap["sort_order"] = *updateDocumentTypeDTO.SortOrder
+	}
+	if updateDocumentTypeDTO.RequiresReview != nil {
+		updateMap["requires_review"] = *updateDocumentTypeDTO.RequiresReview
+	}
+
+	if len(updateMap) == 0 {
+		_, apperr := u.findDocumentType("UpdateDocumentTypeByID", ID)
+		return apperr
+	}
+
+	if err := u.documentTypeRepository.UpdateDocumentTypeByID(ID, updateMap); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("UpdateDocumentTypeByID").Error(constant.ErrDocumentTypeNotFound, zap.String("type_id", ID))
+			return apperror.NotFoundError(constant.ErrDocumentTypeNotFound)
+		}
+		u.logger.Named("UpdateDocumentTypeByID").Error(constant.ErrUpdateDocumentTypeFailed, zap.String("type_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrUpdateDocumentTypeFailed)
+	}
+
+	u.logger.Named("UpdateDocumentTypeByID").Info("Success: Document type updated", zap.String("type_id", ID))
+	return nil
+}
+
+func (u *documentTypeUsecase) DeleteDocumentTypeByID(ID string) *apperror.AppError {
+	ID = utils.NormalizeDocTypeID(ID)
+
+	if utils.IsCoreDocType(ID) {
+		return apperror.BadRequestError(constant.ErrCoreDocumentType)
+	}
+
+	if _, apperr := u.findDocumentType("DeleteDocumentTypeByID", ID); apperr != nil {
+		return apperr
+	}
+
+	// trashed documents still point at their type and may be restored
+	count, err := u.documentTypeRepository.CountDocumentsByType(ID)
+	if err != nil {
+		u.logger.Named("DeleteDocumentTypeByID").Error(constant.ErrGetDocumentTypeFailed, zap.String("type_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrGetDocumentTypeFailed)
+	}
+	if count > 0 {
+		u.logger.Named("DeleteDocumentTypeByID").Error(constant.ErrDocumentTypeInUse, zap.String("type_id", ID), zap.Int64("documents", count))
+		return apperror.BadRequestError(constant.ErrDocumentTypeInUse)
+	}
+
+	if err := u.documentTypeRepository.DeleteDocumentTypeByID(ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("DeleteDocumentTypeByID").Error(constant.ErrDocumentTypeNotFound, zap.String("type_id", ID))
+			return apperror.NotFoundError(constant.ErrDocumentTypeNotFound)
+		}
+		u.logger.Named("DeleteDocumentTypeByID").Error(constant.ErrDeleteDocumentTypeFailed, zap.String("type_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrDeleteDocumentTypeFailed)
+	}
+
+	u.logger.Named("DeleteDocumentTypeByID").Info("Success: Document type deleted", zap.String("type_id", ID))
+	return nil
+}
+
+func (u *documentTypeUsecase) findDocumentType(name, ID string) (*entities.DocumentType, *apperror.AppError) {
+	documentType, err := u.documentTypeRepository.FindDocumentTypeByID(ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named(name).Error(constant.ErrDocumentTypeNotFound, zap.String("type_id", ID))
+			return nil, apperror.NotFoundError(constant.ErrDocumentTypeNotFound)
+		}
+		u.logger.Named(name).Error(constant.ErrGetDocumentTypeFailed, zap.String("type_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentTypeFailed)
+	}
+
+	return documentType, nil
+}
+
+func toDocumentTypeDTO(t *entities.DocumentType) dtos.DocumentTypeDTO {
+	return dtos.DocumentTypeDTO{
+		ID:             strings.ToLower(t.ID),
+		NameTH:         t.NameTH,
+		NameEN:         t.NameEN,
+		Description:    t.Description,
+		SortOrder:      t.SortOrder,
+		RequiresReview: t.RequiresReview,
+		Core:           utils.IsCoreDocType(t.ID),
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
 	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
@@ -18,29 +21,45 @@ import (
 )
 
 type documentUsecase struct {
-	cfg                config.Config
-	logger             *zap.Logger
-	documentRepository repositories.DocumentRepository
-	userRepository     repositories.UserRepository
+	cfg                    config.Config
+	logger                 *zap.Logger
+	documentRepository     repositories.DocumentRepository
+	userRepository         repositories.UserRepository
+	attachmentRepository   repositories.AttachmentRepository
+	tagRepository          repositories.TagRepository
+	documentTypeRepository repositories.DocumentTypeRepository
 }
 
-func NewDocumentUsecase(cfg config.Config, logger *zap.Logger, documentRepository repositories.DocumentRepository, userRepository repositories.UserRepository) DocumentUsecase {
+func NewDocumentUsecase(cfg config.Config, logger *zap.Logger, documentRepository repositories.DocumentRepository, userRepository repositories.UserRepository, attachmentRepository repositories.AttachmentRepository, tagRepository repositories.TagRepository, documentTypeRepository repositories.DocumentTypeRepository) DocumentUsecase {
 	return &documentUsecase{
-		cfg:                cfg,
-		logger:             logger,
-		documentRepository: documentRepository,
-		userRepository:     userRepository,
+		cfg:                    cfg,
+		logger:                 logger,
+		documentRepository:     documentRepository,
+		userRepository:         userRepository,
+		attachmentRepository:   attachmentRepository,
+		tagRepository:          tagRepository,
+		documentTypeRepository: documentTypeRepository,
 	}
 }
 
 func (u *documentUsecase) GetAllDocuments(req *dtos.GetAllDocumentsDTO) (*dtos.PaginationResponse, *apperror.AppError) {
+	if req.DocumentType != "" {
+		if _, apperr := u.validateDocType("GetAllDocuments", req.DocumentType); apperr != nil {
+			return nil, apperr
+		}
+	}
+
 	// retreive documents from repository
 	args := &repositories.FindAllDocumentsArgs{
 		Offset:       (req.Page - 1) * req.PageSize,
 		Limit:        req.PageSize,
 		DocumentType: req.DocumentType,
 		Organization: req.Organization,
-		Title:        req.Title,
+		Query:        req.Query,
+		Tags:         req.Tags,
+		TagMode:      req.TagMode,
+		Locale:       req.Locale,
+		Archived:     req.Archived,
 		StartTime:    req.StartTime,
 		EndTime:      req.EndTime,
 	}
@@ -51,19 +70,48 @@ func (u *documentUsecase) GetAllDocuments(req *dtos.GetAllDocumentsDTO) (*dtos.P
 		return nil, apperror.InternalServerError(constant.ErrGetDocumentFailed)
 	}
 
+	pinned, apperr := u.findPinned("GetAllDocuments", documents)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	superseded, apperr := u.findSuperseded("GetAllDocuments", documents)
+	if apperr != nil {
+		return nil, apperr
+	}
+
 	// create pagination response dtos
 	data := make([]map[string]interface{}, 0)
 	for _, d := range *documents {
-		data = append(data, map[string]interface{}{
-			"id":           d.ID,
-			"title":        d.Title,
-			"banner":       d.Banner,
-			"cover":        d.Cover,
-			"type":         strings.ToLower(d.TypeID),
-			"created_at":   d.CreatedAt,
-			"updated_at":   d.UpdatedAt,
-			"organization": strings.ToLower(strings.Split(d.Author.RoleID, "_")[0]),
-		})
+		localized := localizeDocument(&d, req.Lang)
+		item := map[string]interface{}{
+			"id":                d.ID,
+			"title":             localized.Title,
+			"excerpt":           localized.Excerpt,
+			"locale":            localized.Locale,
+			"available_locales": availableLocales(&d),
+			"slug":              d.Slug,
+			"banner":            d.Banner,
+			"cover":             d.Cover,
+			"type":              strings.ToLower(d.TypeID),
+			"created_at":        d.CreatedAt,
+			"updated_at":        d.UpdatedAt,
+			"organization":      strings.ToLower(strings.Split(d.Author.RoleID, "_")[0]),
+			"tags":              tagNames(d.Tags),
+			"pinned":            pinned[d.ID],
+			"expires_at":        d.ExpiresAt,
+			"reading_time":      localized.ReadingTime,
+		}
+		if ID, ok := superseded[d.ID]; ok {
+			item["superseded_by"] = ID
+		}
+		if req.Archived {
+			item["archived_at"] = d.ArchivedAt
+		}
+		if req.Query != "" {
+			item["highlight"] = highlightDocument(localized, req.Query)
+		}
+		data = append(data, item)
 	}
 
 	paginationResponse := dtos.PaginationResponse{
@@ -76,18 +124,72 @@ func (u *documentUsecase) GetAllDocuments(req *dtos.GetAllDocumentsDTO) (*dtos.P
 	return &paginationResponse, nil
 }
 
-func (u *documentUsecase) GetDocumentByID(ID string) (*dtos.DocumentDTO, *apperror.AppError) {
-	return nil, nil
+func (u *documentUsecase) GetDocumentByID(ID, lang string) (*dtos.DocumentDTO, *apperror.AppError) {
+	document, err := u.documentRepository.FindDocumentDetailByID(ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("GetDocumentByID").Error(constant.ErrDocumentNotFound, zap.String("document_id", ID))
+			return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named("GetDocumentByID").Error(constant.ErrFindDocumentByID, zap.String("document_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	// unpublished documents only exist for the back office, archived ones stay reachable by link
+	if !isPublic(document) {
+		return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+	}
+
+	res := toDocumentDTO(document, lang)
+	return &res, nil
+}
+
+// GetDocumentBySlug also resolves former slugs, the returned slug is then the current one
+func (u *documentUsecase) GetDocumentBySlug(slug, lang string) (*dtos.DocumentDTO, *apperror.AppError) {
+	document, err := u.documentRepository.FindDocumentBySlug(slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		oldSlug, oldErr := u.documentRepository.FindOldDocumentSlug(slug)
+		if oldErr != nil {
+			if errors.Is(oldErr, gorm.ErrRecordNotFound) {
+				u.logger.Named("GetDocumentBySlug").Error(constant.ErrDocumentNotFound, zap.String("slug", slug))
+				return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+			}
+			u.logger.Named("GetDocumentBySlug").Error(constant.ErrFindDocumentByID, zap.String("slug", slug), zap.Error(oldErr))
+			return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+		}
+
+		return u.GetDocumentByID(oldSlug.DocumentID, lang)
+	}
+	if err != nil {
+		u.logger.Named("GetDocumentBySlug").Error(constant.ErrFindDocumentByID, zap.String("slug", slug), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	if !isPublic(document) {
+		return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+	}
+
+	res := toDocumentDTO(document, lang)
+	return &res, nil
 }
 
 func (u *documentUsecase) GetDocumentsByRole(req *dtos.GetAllDocumentsByRoleDTO) (*dtos.PaginationResponse, *apperror.AppError) {
+	if req.DocumentType != "" {
+		if _, apperr := u.validateDocType("GetAllDocumentsByRole", req.DocumentType); apperr != nil {
+			return nil, apperr
+		}
+	}
+
 	// retreive documents from repository
 	args := &repositories.FindAllDocumentsByRoleArgs{
 		Offset:       (req.Page - 1) * req.PageSize,
 		Limit:        req.PageSize,
 		DocumentType: req.DocumentType,
 		Organization: req.Organization,
-		Title:        req.Title,
+		Query:        req.Query,
+		Tags:         req.Tags,
+		TagMode:      req.TagMode,
+		Locale:       req.Locale,
 		StartTime:    req.StartTime,
 		EndTime:      req.EndTime,
 		Role:         req.Role,
@@ -99,20 +201,47 @@ func (u *documentUsecase) GetDocumentsByRole(req *dtos.GetAllDocumentsByRoleDTO)
 		return nil, apperror.InternalServerError(constant.ErrGetDocumentFailed)
 	}
 
+	pinned, apperr := u.findPinned("GetAllDocumentsByRole", documents)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	superseded, apperr := u.findSuperseded("GetAllDocumentsByRole", documents)
+	if apperr != nil {
+		return nil, apperr
+	}
+
 	// create pagination response dtos
 	data := make([]map[string]interface{}, 0)
 	for _, d := range *documents {
-		data = append(data, map[string]interface{}{
-			"id":           d.ID,
-			"title":        d.Title,
-			"banner":       d.Banner,
-			"cover":        d.Cover,
-			"type":         strings.ToLower(d.TypeID),
-			"created_at":   d.CreatedAt,
-			"updated_at":   d.UpdatedAt,
-			"organization": strings.ToLower(strings.Split(d.Author.RoleID, "_")[0]),
-			"author_role":  strings.ToLower(d.Author.RoleID),
-		})
+		localized := localizeDocument(&d, req.Lang)
+		item := map[string]interface{}{
+			"id":                d.ID,
+			"title":             localized.Title,
+			"excerpt":           localized.Excerpt,
+			"locale":            localized.Locale,
+			"available_locales": availableLocales(&d),
+			"slug":              d.Slug,
+			"banner":            d.Banner,
+			"cover":             d.Cover,
+			"type":              strings.ToLower(d.TypeID),
+			"created_at":        d.CreatedAt,
+			"updated_at":        d.UpdatedAt,
+			"organization":      strings.ToLower(strings.Split(d.Author.RoleID, "_")[0]),
+			"author_role":       strings.ToLower(d.Author.RoleID),
+			"status":            strings.ToLower(d.Status),
+			"tags":              tagNames(d.Tags),
+			"pinned":            pinned[d.ID],
+			"expires_at":        d.ExpiresAt,
+			"reading_time":      localized.ReadingTime,
+		}
+		if ID, ok := superseded[d.ID]; ok {
+			item["superseded_by"] = ID
+		}
+		if req.Query != "" {
+			item["highlight"] = highlightDocument(localized, req.Query)
+		}
+		data = append(data, item)
 	}
 
 	paginationResponse := dtos.PaginationResponse{
@@ -127,26 +256,69 @@ func (u *documentUsecase) GetDocumentsByRole(req *dtos.GetAllDocumentsByRoleDTO)
 
 func (u *documentUsecase) CreateDocument(document *dtos.CreateDocumentDTO) *apperror.AppError {
 	// validate user
-	_, err := u.userRepository.FindUserByID(document.UserID)
+	author, err := u.userRepository.FindUserByID(document.UserID)
 	if err != nil {
 		u.logger.Named("CreateDocument").Error(constant.ErrUserNotFound, zap.String("user_id", document.UserID), zap.Error(err))
 		return apperror.NotFoundError(constant.ErrUserNotFound)
 	}
 
-	docType, err := utils.GetDocType(document.TypeID)
-	if err != nil {
-		u.logger.Named("CreateDocument").Error(constant.ErrInvalidDocType, zap.String("type_id", document.TypeID), zap.Error(err))
-		return apperror.BadRequestError(constant.ErrInvalidDocType)
+	docType, apperr := u.validateDocType("CreateDocument", document.TypeID)
+	if apperr != nil {
+		return apperr
+	}
+
+	if document.ExpiresAt != nil && !document.ExpiresAt.After(time.Now()) {
+		return apperror.BadRequestError(constant.ErrDocumentExpiryPassed)
+	}
+
+	format, content, apperr := renderContent(document.Format, document.Content)
+	if apperr != nil {
+		return apperr
+	}
+
+	// documents from ordinary admins wait for their superadmin's approval before going public
+	status := constant.PUBLISHED
+	if utils.RequiresReview(docType.RequiresReview, author.RoleID) {
+		status = constant.DRAFT
+	}
+
+	locale := strings.ToLower(document.Locale)
+	if locale == "" {
+		locale = constant.DEFAULT_LOCALE
+	} else if !utils.ValidateLocale(locale) {
+		return apperror.BadRequestError(constant.ErrInvalidLocale)
+	}
+
+	ID, apperr := u.newDocumentID("CreateDocument")
+	if apperr != nil {
+		return apperr
+	}
+
+	base := utils.Slugify(document.Slug)
+	if base == "" {
+		base = utils.Slugify(document.Title)
+	}
+	slug, apperr := u.uniqueSlug("CreateDocument", base, ID)
+	if apperr != nil {
+		return apperr
 	}
 
 	newDocument := &entities.Document{
-		ID:      fmt.Sprintf("DOC-%v", utils.GenerateRandomString("0123456789", 8)),
+		ID:      ID,
 		Title:   document.Title,
-		Content: document.Content,
+		Slug:    slug,
+		Content: content,
+		Source:  document.Content,
+		Format:  format,
+		Excerpt: plainExcerpt(document.Excerpt),
+		Locale:  locale,
 		Banner:  document.Banner,
 		Cover:   document.Cover,
 		UserID:  document.UserID,
-		TypeID:  docType,
+		TypeID:  docType.ID,
+		Status:  status,
+
+		ExpiresAt: document.ExpiresAt,
 	}
 
 	if err := u.documentRepository.InsertDocument(newDocument); err != nil {
@@ -158,8 +330,56 @@ func (u *documentUsecase) CreateDocument(document *dtos.CreateDocumentDTO) *appe
 	return nil
 }
 
-func (u *documentUsecase) UpdateDocumentByID(ID string, updateMap interface{}) *apperror.AppError {
-	if err := u.documentRepository.UpdateDocumentByID(ID, updateMap); err != nil {
+func (u *documentUsecase) UpdateDocumentByID(req *dtos.UserDTO, ID string, updateDocumentDTO *dtos.UpdateDocumentDTO) *apperror.AppError {
+	document, apperr := u.findDocument("UpdateDocumentByID", ID)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("UpdateDocumentByID").Error(constant.ErrNotDocumentEditor, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+	// the author waits for the review, the reviewers may still fix the document
+	if document.Status == constant.PENDING && !u.isOrgSuperAdmin(req, document) {
+		u.logger.Named("UpdateDocumentByID").Error(constant.ErrDocumentUnderReview, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.BadRequestError(constant.ErrDocumentUnderReview)
+	}
+
+	updateMap := make(map[string]interface{})
+	if updateDocumentDTO.Title != "" {
+		updateMap["title"] = updateDocumentDTO.Title
+	}
+	if updateDocumentDTO.Excerpt != nil {
+		updateMap["excerpt"] = plainExcerpt(updateDocumentDTO.Excerpt)
+	}
+	if updateDocumentDTO.Banner != nil {
+		updateMap["banner"] = updateDocumentDTO.Banner
+	}
+	if updateDocumentDTO.Cover != nil {
+		updateMap["cover"] = updateDocumentDTO.Cover
+	}
+
+	// a new format alone renders the current source again
+	if updateDocumentDTO.Content != "" || updateDocumentDTO.Format != "" {
+		source, format := updateDocumentDTO.Content, updateDocumentDTO.Format
+		if source == "" {
+			source = document.Source
+		}
+		if format == "" {
+			format = document.Format
+		}
+
+		format, content, apperr := renderContent(format, source)
+		if apperr != nil {
+			return apperr
+		}
+		updateMap["content"] = content
+		updateMap["source"] = source
+		updateMap["format"] = format
+	}
+
+	if err := u.documentRepository.UpdateDocumentByID(ID, updateMap, req.ID); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			u.logger.Named("UpdateDocumentByID").Error(constant.ErrDocumentNotFound, zap.String("documentID", ID))
 			return apperror.InternalServerError(constant.ErrDocumentNotFound)
@@ -185,3 +405,1470 @@ func (u *documentUsecase) DeleteDocumentByID(ID string) *apperror.AppError {
 	u.logger.Named("DeleteDocumentByID").Info("Success: Document deleted", zap.String("documentID", ID))
 	return nil
 }
+
+func (u *documentUsecase) UpdateDocumentTags(req *dtos.UserDTO, ID string, updateTagsDTO *dtos.UpdateDocumentTagsDTO) *apperror.AppError {
+	document, apperr := u.findDocument("UpdateDocumentTags", ID)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("UpdateDocumentTags").Error(constant.ErrNotDocumentEditor, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	tagIDs := make([]string, 0, len(updateTagsDTO.TagIDs))
+	seen := make(map[string]bool)
+	for _, tagID := range updateTagsDTO.TagIDs {
+		if !seen[tagID] {
+			seen[tagID] = true
+			tagIDs = append(tagIDs, tagID)
+		}
+	}
+
+	tags, err := u.tagRepository.FindTagsByIDs(tagIDs)
+	if err != nil {
+		u.logger.Named("UpdateDocumentTags").Error(constant.ErrGetTagFailed, zap.Strings("tag_ids", tagIDs), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrGetTagFailed)
+	}
+	if len(*tags) != len(tagIDs) {
+		u.logger.Named("UpdateDocumentTags").Error(constant.ErrTagNotFound, zap.Strings("tag_ids", tagIDs))
+		return apperror.BadRequestError(constant.ErrTagNotFound)
+	}
+
+	if err := u.documentRepository.UpdateDocumentTags(ID, tags); err != nil {
+		u.logger.Named("UpdateDocumentTags").Error(constant.ErrUpdateDocumentTagsFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrUpdateDocumentTagsFailed)
+	}
+
+	u.logger.Named("UpdateDocumentTags").Info("Success: Document tags updated", zap.String("document_id", ID))
+	return nil
+}
+
+func (u *documentUsecase) UpdateDocumentSlug(req *dtos.UserDTO, ID string, updateSlugDTO *dtos.UpdateDocumentSlugDTO) *apperror.AppError {
+	document, apperr := u.findDocument("UpdateDocumentSlug", ID)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("UpdateDocumentSlug").Error(constant.ErrNotDocumentEditor, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	slug := utils.Slugify(updateSlugDTO.Slug)
+	if slug == "" {
+		return apperror.BadRequestError(constant.ErrInvalidSlug)
+	}
+
+	exists, err := u.documentRepository.SlugExists(slug, ID)
+	if err != nil {
+		u.logger.Named("UpdateDocumentSlug").Error(constant.ErrUpdateDocumentSlugFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrUpdateDocumentSlugFailed)
+	}
+	if exists {
+		u.logger.Named("UpdateDocumentSlug").Error(constant.ErrSlugAlreadyExists, zap.String("document_id", ID), zap.String("slug", slug))
+		return apperror.BadRequestError(constant.ErrSlugAlreadyExists)
+	}
+
+	if err := u.documentRepository.UpdateDocumentSlug(ID, slug); err != nil {
+		u.logger.Named("UpdateDocumentSlug").Error(constant.ErrUpdateDocumentSlugFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrUpdateDocumentSlugFailed)
+	}
+
+	u.logger.Named("UpdateDocumentSlug").Info("Success: Document slug updated", zap.String("document_id", ID), zap.String("slug", slug))
+	return nil
+}
+
+func (u *documentUsecase) UpdateDocumentExpiry(req *dtos.UserDTO, ID string, updateExpiryDTO *dtos.UpdateDocumentExpiryDTO) *apperror.AppError {
+	document, apperr := u.findDocument("UpdateDocumentExpiry", ID)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("UpdateDocumentExpiry").Error(constant.ErrNotDocumentEditor, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	// drafts get their expiry when created, changing it later is for documents already out
+	if document.Status != constant.PUBLISHED && document.Status != constant.ARCHIVED {
+		return apperror.BadRequestError(constant.ErrNotArchivableDocument)
+	}
+
+	expiresAt := updateExpiryDTO.ExpiresAt
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return apperror.BadRequestError(constant.ErrDocumentExpiryPassed)
+	}
+
+	if err := u.documentRepository.UpdateDocumentExpiry(ID, expiresAt); err != nil {
+		u.logger.Named("UpdateDocumentExpiry").Error(constant.ErrUpdateDocumentExpiryFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrUpdateDocumentExpiryFailed)
+	}
+
+	u.logger.Named("UpdateDocumentExpiry").Info("Success: Document expiry updated", zap.String("document_id", ID), zap.Timep("expires_at", expiresAt))
+	return nil
+}
+
+// BulkUpdateDocuments applies one operation to many documents, each authorized on its own.
+// In atomic mode the first failure rolls back every document, in best-effort mode the others are still applied.
+func (u *documentUsecase) BulkUpdateDocuments(req *dtos.UserDTO, bulkDTO *dtos.BulkDocumentsDTO) (*dtos.BulkDocumentsResultDTO, *apperror.AppError) {
+	operation, mode := strings.ToLower(bulkDTO.Operation), strings.ToLower(bulkDTO.Mode)
+	if mode == "" {
+		mode = constant.DEFAULT_BULK_MODE
+	}
+
+	if !utils.ValidateBulkOperation(operation) {
+		return nil, apperror.BadRequestError(constant.ErrInvalidBulkOperation)
+	}
+	if !utils.ValidateBulkMode(mode) {
+		return nil, apperror.BadRequestError(constant.ErrInvalidBulkMode)
+	}
+	if len(bulkDTO.DocumentIDs) > constant.MAX_BULK_DOCUMENTS {
+		return nil, apperror.BadRequestError(constant.ErrTooManyBulkDocuments)
+	}
+
+	seen := make(map[string]bool)
+	for _, ID := range bulkDTO.DocumentIDs {
+		if seen[ID] {
+			return nil, apperror.BadRequestError(fmt.Sprintf("%s: %s", constant.ErrDuplicateBulkDocument, ID))
+		}
+		seen[ID] = true
+	}
+
+	// what every document shares is looked up once
+	var documentType *entities.DocumentType
+	var tags *[]entities.Tag
+	switch operation {
+	case constant.BULK_CHANGE_TYPE:
+		if bulkDTO.TypeID == "" {
+			return nil, apperror.BadRequestError(constant.ErrBulkTypeRequired)
+		}
+		var apperr *apperror.AppError
+		if documentType, apperr = u.validateDocType("BulkUpdateDocuments", bulkDTO.TypeID); apperr != nil {
+			return nil, apperr
+		}
+	case constant.BULK_ADD_TAG:
+		tagIDs := make([]string, 0, len(bulkDTO.TagIDs))
+		seenTags := make(map[string]bool)
+		for _, tagID := range bulkDTO.TagIDs {
+			if !seenTags[tagID] {
+				seenTags[tagID] = true
+				tagIDs = append(tagIDs, tagID)
+			}
+		}
+		if len(tagIDs) == 0 {
+			return nil, apperror.BadRequestError(constant.ErrBulkTagsRequired)
+		}
+
+		var err error
+		if tags, err = u.tagRepository.FindTagsByIDs(tagIDs); err != nil {
+			u.logger.Named("BulkUpdateDocuments").Error(constant.ErrGetTagFailed, zap.Strings("tag_ids", tagIDs), zap.Error(err))
+			return nil, apperror.InternalServerError(constant.ErrGetTagFailed)
+		}
+		if len(*tags) != len(tagIDs) {
+			return nil, apperror.BadRequestError(constant.ErrTagNotFound)
+		}
+	}
+
+	res := dtos.BulkDocumentsResultDTO{
+		Operation: operation,
+		Mode:      mode,
+		Results:   make([]dtos.BulkDocumentResultDTO, len(bulkDTO.DocumentIDs)),
+	}
+	for i, ID := range bulkDTO.DocumentIDs {
+		res.Results[i] = dtos.BulkDocumentResultDTO{DocumentID: ID, Success: true, Code: http.StatusOK}
+	}
+
+	if mode == constant.BULK_BEST_EFFORT {
+		for i, ID := range bulkDTO.DocumentIDs {
+			if apperr := u.bulkUpdateDocument(u.documentRepository, req, ID, operation, documentType, tags); apperr != nil {
+				setBulkFailure(&res.Results[i], apperr.HttpCode, apperr.Error())
+			}
+		}
+	} else {
+		failed := -1
+		err := u.documentRepository.Transaction(func(repo repositories.DocumentRepository) error {
+			for i, ID := range bulkDTO.DocumentIDs {
+				if apperr := u.bulkUpdateDocument(repo, req, ID, operation, documentType, tags); apperr != nil {
+					failed = i
+					setBulkFailure(&res.Results[i], apperr.HttpCode, apperr.Error())
+					return apperr
+				}
+			}
+			return nil
+		})
+
+		if failed < 0 && err != nil {
+			u.logger.Named("BulkUpdateDocuments").Error(constant.ErrBulkUpdateDocumentsFailed, zap.String("operation", operation), zap.Error(err))
+			return nil, apperror.InternalServerError(constant.ErrBulkUpdateDocumentsFailed)
+		}
+		if failed >= 0 {
+			for i := range res.Results {
+				if i != failed {
+					setBulkFailure(&res.Results[i], http.StatusConflict, constant.ErrBulkItemRolledBack)
+				}
+			}
+		}
+	}
+
+	for _, result := range res.Results {
+		if result.Success {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+	}
+
+	u.logger.Named("BulkUpdateDocuments").Info("Success: Bulk operation done", zap.String("operation", operation), zap.String("mode", mode), zap.Int("succeeded", res.Succeeded), zap.Int("failed", res.Failed))
+	return &res, nil
+}
+
+// bulkUpdateDocument applies a bulk operation to one document through repo, which may be bound to a transaction
+func (u *documentUsecase) bulkUpdateDocument(repo repositories.DocumentRepository, req *dtos.UserDTO, ID, operation string, documentType *entities.DocumentType, tags *[]entities.Tag) *apperror.AppError {
+	var document *entities.Document
+	var err error
+	if operation == constant.BULK_RESTORE {
+		document, err = repo.FindDeletedDocumentByID(ID)
+	} else {
+		document, err = repo.FindDocumentByID(ID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named("BulkUpdateDocuments").Error(constant.ErrFindDocumentByID, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	// content changes are up to the editors, the rest is what a reviewer would do one document at a time
+	if operation == constant.BULK_CHANGE_TYPE || operation == constant.BULK_ADD_TAG {
+		if !isDocumentEditor(req, document) {
+			u.logger.Named("BulkUpdateDocuments").Error(constant.ErrNotDocumentEditor, zap.String("document_id", ID), zap.String("user_id", req.ID))
+			return apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+		}
+	} else if !u.isOrgSuperAdmin(req, document) {
+		u.logger.Named("BulkUpdateDocuments").Error(constant.ErrNotDocumentReviewer, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentReviewer)
+	}
+
+	switch operation {
+	case constant.BULK_DELETE:
+		err = repo.DeleteDocumentByID(ID)
+	case constant.BULK_RESTORE:
+		err = repo.RestoreDocumentByID(ID)
+	case constant.BULK_PUBLISH:
+		review, reviewErr := newDocumentReview(req, document, constant.APPROVE, nil)
+		if reviewErr != nil {
+			return apperror.BadRequestError(reviewErr.Error())
+		}
+		err = repo.UpdateDocumentStatus(review)
+	case constant.BULK_ARCHIVE:
+		if document.Status != constant.PUBLISHED {
+			return apperror.BadRequestError(constant.ErrArchiveNotPublished)
+		}
+		err = repo.ArchiveDocumentByID(ID, time.Now())
+	case constant.BULK_CHANGE_TYPE:
+		if document.TypeID == documentType.ID {
+			return nil
+		}
+		err = repo.UpdateDocumentByID(ID, map[string]interface{}{"type_id": documentType.ID}, req.ID)
+	case constant.BULK_ADD_TAG:
+		err = repo.AddDocumentTags(ID, tags)
+	}
+
+	if err != nil {
+		// the document was changed between the lookup and the update
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.BadRequestError(constant.ErrDocumentStatusChanged)
+		}
+		u.logger.Named("BulkUpdateDocuments").Error(constant.ErrBulkUpdateDocumentsFailed, zap.String("document_id", ID), zap.String("operation", operation), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrBulkUpdateDocumentsFailed)
+	}
+
+	return nil
+}
+
+func setBulkFailure(result *dtos.BulkDocumentResultDTO, code int, message string) {
+	result.Success = false
+	result.Code = code
+	result.Error = &message
+}
+
+// translation
+
+func (u *documentUsecase) GetDocumentTranslations(req *dtos.UserDTO, ID string) (*[]dtos.DocumentTranslationDTO, *apperror.AppError) {
+	document, apperr := u.findDocument("GetDocumentTranslations", ID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("GetDocumentTranslations").Error(constant.ErrNotDocumentEditor, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	translations, err := u.documentRepository.FindDocumentTranslations(ID)
+	if err != nil {
+		u.logger.Named("GetDocumentTranslations").Error(constant.ErrGetTranslationFailed, zap.String("document_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetTranslationFailed)
+	}
+
+	res := make([]dtos.DocumentTranslationDTO, len(*translations))
+	for i, t := range *translations {
+		res[i] = dtos.DocumentTranslationDTO{
+			Locale:      t.Locale,
+			Title:       t.Title,
+			Content:     t.Content,
+			Source:      t.Source,
+			Format:      t.Format,
+			Excerpt:     t.Excerpt,
+			ReadingTime: t.ReadingTime,
+			CreatedAt:   t.CreatedAt,
+			UpdatedAt:   t.UpdatedAt,
+		}
+	}
+
+	return &res, nil
+}
+
+func (u *documentUsecase) UpdateDocumentTranslation(req *dtos.UserDTO, ID, locale string, translationDTO *dtos.UpdateDocumentTranslationDTO) *apperror.AppError {
+	document, apperr := u.findTranslatableDocument("UpdateDocumentTranslation", req, ID, locale)
+	if apperr != nil {
+		return apperr
+	}
+
+	format, content, apperr := renderContent(translationDTO.Format, translationDTO.Content)
+	if apperr != nil {
+		return apperr
+	}
+
+	translation := &entities.DocumentTranslation{
+		DocumentID: document.ID,
+		Locale:     strings.ToLower(locale),
+		Title:      translationDTO.Title,
+		Content:    content,
+		Source:     translationDTO.Content,
+		Format:     format,
+		Excerpt:    plainExcerpt(translationDTO.Excerpt),
+	}
+
+	if err := u.documentRepository.UpsertDocumentTranslation(translation); err != nil {
+		u.logger.Named("UpdateDocumentTranslation").Error(constant.ErrUpdateTranslationFailed, zap.String("document_id", ID), zap.String("locale", locale), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrUpdateTranslationFailed)
+	}
+
+	u.logger.Named("UpdateDocumentTranslation").Info("Success: Document translation updated", zap.String("document_id", ID), zap.String("locale", translation.Locale))
+	return nil
+}
+
+func (u *documentUsecase) DeleteDocumentTranslation(req *dtos.UserDTO, ID, locale string) *apperror.AppError {
+	if _, apperr := u.findTranslatableDocument("DeleteDocumentTranslation", req, ID, locale); apperr != nil {
+		return apperr
+	}
+
+	if err := u.documentRepository.DeleteDocumentTranslation(ID, strings.ToLower(locale)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("DeleteDocumentTranslation").Error(constant.ErrTranslationNotFound, zap.String("document_id", ID), zap.String("locale", locale))
+			return apperror.NotFoundError(constant.ErrTranslationNotFound)
+		}
+		u.logger.Named("DeleteDocumentTranslation").Error(constant.ErrDeleteTranslationFailed, zap.String("document_id", ID), zap.String("locale", locale), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrDeleteTranslationFailed)
+	}
+
+	u.logger.Named("DeleteDocumentTranslation").Info("Success: Document translation deleted", zap.String("document_id", ID), zap.String("locale", locale))
+	return nil
+}
+
+// findTranslatableDocument checks that req may edit the document and that locale is one of its translations
+func (u *documentUsecase) findTranslatableDocument(name string, req *dtos.UserDTO, ID, locale string) (*entities.Document, *apperror.AppError) {
+	locale = strings.ToLower(locale)
+	if locale == "" || !utils.ValidateLocale(locale) {
+		return nil, apperror.BadRequestError(constant.ErrInvalidLocale)
+	}
+
+	document, apperr := u.findDocument(name, ID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named(name).Error(constant.ErrNotDocumentEditor, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	if locale == document.Locale {
+		return nil, apperror.BadRequestError(constant.ErrTranslationIsBaseLocale)
+	}
+
+	return document, nil
+}
+
+// newDocumentID draws random document IDs until one is free
+func (u *documentUsecase) newDocumentID(name string) (string, *apperror.AppError) {
+	for i := 0; i < constant.MAX_ID_ATTEMPTS; i++ {
+		ID := fmt.Sprintf("DOC-%v", utils.GenerateRandomString("0123456789", 8))
+
+		exists, err := u.documentRepository.DocumentIDExists(ID)
+		if err != nil {
+			u.logger.Named(name).Error(constant.ErrGenerateDocumentID, zap.Error(err))
+			return "", apperror.InternalServerError(constant.ErrGenerateDocumentID)
+		}
+		if !exists {
+			return ID, nil
+		}
+	}
+
+	u.logger.Named(name).Error(constant.ErrGenerateDocumentID, zap.Int("attempts", constant.MAX_ID_ATTEMPTS))
+	return "", apperror.InternalServerError(constant.ErrGenerateDocumentID)
+}
+
+// uniqueSlug returns base, or base with the first free numbered suffix
+func (u *documentUsecase) uniqueSlug(name, base, documentID string) (string, *apperror.AppError) {
+	if base == "" {
+		base = constant.DEFAULT_SLUG
+	}
+
+	for n := 1; n <= constant.MAX_SLUG_ATTEMPTS+1; n++ {
+		slug := utils.SlugCandidate(base, n)
+		// a popular title ran out of numbers, a random suffix is practically always free
+		if n > constant.MAX_SLUG_ATTEMPTS {
+			slug = fmt.Sprintf("%s-%s", base, utils.GenerateRandomString("0123456789abcdefghijklmnopqrstuvwxyz", 6))
+		}
+
+		exists, err := u.documentRepository.SlugExists(slug, documentID)
+		if err != nil {
+			u.logger.Named(name).Error(constant.ErrGenerateSlugFailed, zap.String("slug", slug), zap.Error(err))
+			return "", apperror.InternalServerError(constant.ErrGenerateSlugFailed)
+		}
+		if !exists {
+			return slug, nil
+		}
+	}
+
+	u.logger.Named(name).Error(constant.ErrGenerateSlugFailed, zap.String("slug", base))
+	return "", apperror.InternalServerError(constant.ErrGenerateSlugFailed)
+}
+
+// featured
+
+func (u *documentUsecase) GetFeaturedDocuments(organization, lang string) (*[]dtos.FeaturedDocumentDTO, *apperror.AppError) {
+	scopes := []string{constant.FEATURED_GLOBAL}
+	if organization != "" {
+		scopes = append(scopes, strings.ToUpper(organization))
+	}
+
+	slots, err := u.documentRepository.FindFeaturedDocuments(scopes, true)
+	if err != nil {
+		u.logger.Named("GetFeaturedDocuments").Error(constant.ErrGetFeaturedDocumentFailed, zap.Strings("scopes", scopes), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetFeaturedDocumentFailed)
+	}
+
+	// global slots come first and a document pinned in both scopes is only shown once
+	sort.SliceStable(*slots, func(i, j int) bool {
+		return (*slots)[i].Scope == constant.FEATURED_GLOBAL && (*slots)[j].Scope != constant.FEATURED_GLOBAL
+	})
+
+	seen := make(map[string]bool)
+	res := make([]dtos.FeaturedDocumentDTO, 0, len(*slots))
+	for _, slot := range *slots {
+		if seen[slot.DocumentID] {
+			continue
+		}
+		seen[slot.DocumentID] = true
+		res = append(res, toFeaturedDocumentDTO(&slot, lang))
+	}
+
+	return &res, nil
+}
+
+func (u *documentUsecase) GetFeaturedSlots(req *dtos.UserDTO, scope string) (*[]dtos.FeaturedDocumentDTO, *apperror.AppError) {
+	scope, apperr := u.checkFeaturedScope("GetFeaturedSlots", req, scope)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	// expired slots are listed too so they can be renewed or removed
+	slots, err := u.documentRepository.FindFeaturedDocuments([]string{scope}, false)
+	if err != nil {
+		u.logger.Named("GetFeaturedSlots").Error(constant.ErrGetFeaturedDocumentFailed, zap.String("scope", scope), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetFeaturedDocumentFailed)
+	}
+
+	res := make([]dtos.FeaturedDocumentDTO, len(*slots))
+	for i, slot := range *slots {
+		res[i] = toFeaturedDocumentDTO(&slot, "")
+	}
+
+	return &res, nil
+}
+
+func (u *documentUsecase) UpdateFeaturedDocuments(req *dtos.UserDTO, scope string, updateFeaturedDTO *dtos.UpdateFeaturedDocumentsDTO) *apperror.AppError {
+	scope, apperr := u.checkFeaturedScope("UpdateFeaturedDocuments", req, scope)
+	if apperr != nil {
+		return apperr
+	}
+
+	if len(updateFeaturedDTO.Slots) > constant.MAX_FEATURED_SLOTS {
+		return apperror.BadRequestError(constant.ErrTooManyFeaturedSlots)
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	slots := make([]entities.FeaturedDocument, len(updateFeaturedDTO.Slots))
+	for i, slot := range updateFeaturedDTO.Slots {
+		if seen[slot.DocumentID] {
+			return apperror.BadRequestError(constant.ErrDuplicateFeaturedDocument)
+		}
+		seen[slot.DocumentID] = true
+
+		if slot.ExpiresAt != nil && !slot.ExpiresAt.After(now) {
+			return apperror.BadRequestError(constant.ErrFeaturedExpiryPassed)
+		}
+
+		document, apperr := u.findDocument("UpdateFeaturedDocuments", slot.DocumentID)
+		if apperr != nil {
+			return apperr
+		}
+		if document.Status != constant.PUBLISHED {
+			return apperror.BadRequestError(constant.ErrFeaturedDocumentNotPublished)
+		}
+		if scope != constant.FEATURED_GLOBAL && utils.GetOrg(document.Author.RoleID) != scope {
+			return apperror.BadRequestError(constant.ErrFeaturedDocumentOtherOrg)
+		}
+
+		slots[i] = entities.FeaturedDocument{
+			ID:         fmt.Sprintf("FTR-%v", utils.GenerateRandomString("0123456789", 8)),
+			Scope:      scope,
+			DocumentID: document.ID,
+			Position:   i + 1,
+			ExpiresAt:  slot.ExpiresAt,
+			CreatedBy:  req.ID,
+		}
+	}
+
+	if err := u.documentRepository.ReplaceFeaturedDocuments(scope, &slots); err != nil {
+		u.logger.Named("UpdateFeaturedDocuments").Error(constant.ErrUpdateFeaturedDocumentFailed, zap.String("scope", scope), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrUpdateFeaturedDocumentFailed)
+	}
+
+	u.logger.Named("UpdateFeaturedDocuments").Info("Success: Featured documents updated", zap.String("scope", scope), zap.Int("slots", len(slots)))
+	return nil
+}
+
+func (u *documentUsecase) DeleteFeaturedDocument(req *dtos.UserDTO, scope, documentID string) *apperror.AppError {
+	scope, apperr := u.checkFeaturedScope("DeleteFeaturedDocument", req, scope)
+	if apperr != nil {
+		return apperr
+	}
+
+	if err := u.documentRepository.DeleteFeaturedDocument(scope, documentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("DeleteFeaturedDocument").Error(constant.ErrFeaturedDocumentNotFound, zap.String("scope", scope), zap.String("document_id", documentID))
+			return apperror.NotFoundError(constant.ErrFeaturedDocumentNotFound)
+		}
+		u.logger.Named("DeleteFeaturedDocument").Error(constant.ErrDeleteFeaturedDocumentFailed, zap.String("scope", scope), zap.String("document_id", documentID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrDeleteFeaturedDocumentFailed)
+	}
+
+	u.logger.Named("DeleteFeaturedDocument").Info("Success: Featured document deleted", zap.String("scope", scope), zap.String("document_id", documentID))
+	return nil
+}
+
+// checkFeaturedScope normalizes scope and checks that req may curate it.
+// Global slots belong to every superadmin, organization slots to that organization's superadmins.
+func (u *documentUsecase) checkFeaturedScope(name string, req *dtos.UserDTO, scope string) (string, *apperror.AppError) {
+	scope = strings.ToUpper(scope)
+	if scope != constant.FEATURED_GLOBAL && (scope == "" || !utils.ValidateOrg(scope)) {
+		return "", apperror.BadRequestError(constant.ErrInvalidFeaturedScope)
+	}
+
+	if !utils.IsSuperAdmin(req.Role) || (scope != constant.FEATURED_GLOBAL && utils.GetOrg(req.Role) != scope) {
+		u.logger.Named(name).Error(constant.ErrNotFeaturedScopeAdmin, zap.String("scope", scope), zap.String("user_id", req.ID))
+		return "", apperror.ForbiddenError(constant.ErrNotFeaturedScopeAdmin)
+	}
+
+	return scope, nil
+}
+
+// findPinned reports which of the listed documents hold an unexpired featured slot
+func (u *documentUsecase) findPinned(name string, documents *[]entities.Document) (map[string]bool, *apperror.AppError) {
+	IDs := make([]string, len(*documents))
+	for i, d := range *documents {
+		IDs[i] = d.ID
+	}
+
+	pinnedIDs, err := u.documentRepository.FindPinnedDocumentIDs(IDs)
+	if err != nil {
+		u.logger.Named(name).Error(constant.ErrGetFeaturedDocumentFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetFeaturedDocumentFailed)
+	}
+
+	pinned := make(map[string]bool, len(pinnedIDs))
+	for _, ID := range pinnedIDs {
+		pinned[ID] = true
+	}
+	return pinned, nil
+}
+
+func toFeaturedDocumentDTO(slot *entities.FeaturedDocument, lang string) dtos.FeaturedDocumentDTO {
+	d := &slot.Document
+	localized := localizeDocument(d, lang)
+
+	return dtos.FeaturedDocumentDTO{
+		Scope:     strings.ToLower(slot.Scope),
+		Position:  slot.Position,
+		ExpiresAt: slot.ExpiresAt,
+		Document: map[string]interface{}{
+			"id":                d.ID,
+			"title":             localized.Title,
+			"excerpt":           localized.Excerpt,
+			"locale":            localized.Locale,
+			"available_locales": availableLocales(d),
+			"slug":              d.Slug,
+			"banner":            d.Banner,
+			"cover":             d.Cover,
+			"type":              strings.ToLower(d.TypeID),
+			"status":            strings.ToLower(d.Status),
+			"created_at":        d.CreatedAt,
+			"updated_at":        d.UpdatedAt,
+			"organization":      strings.ToLower(utils.GetOrg(d.Author.RoleID)),
+			"reading_time":      localized.ReadingTime,
+			"pinned":            true,
+		},
+	}
+}
+
+// link
+
+// GetDocumentLinks lists every link of a document for its editors, including links to documents that are not published yet
+func (u *documentUsecase) GetDocumentLinks(req *dtos.UserDTO, ID string) (*dtos.DocumentLinksDTO, *apperror.AppError) {
+	document, apperr := u.findDocument("GetDocumentLinks", ID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("GetDocumentLinks").Error(constant.ErrNotDocumentEditor, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	links, backlinks, err := u.documentRepository.FindDocumentLinks(ID)
+	if err != nil {
+		u.logger.Named("GetDocumentLinks").Error(constant.ErrGetDocumentLinksFailed, zap.String("document_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentLinksFailed)
+	}
+
+	res := dtos.DocumentLinksDTO{
+		Links:     toDocumentLinkDTOs(*links, false, false),
+		Backlinks: toDocumentLinkDTOs(*backlinks, true, false),
+	}
+	return &res, nil
+}
+
+// UpdateDocumentLinks replaces the links going out of a document, the linked documents get them as backlinks.
+// Two documents are linked one way only, so a document cannot link back to one that links to it.
+func (u *documentUsecase) UpdateDocumentLinks(req *dtos.UserDTO, ID string, updateLinksDTO *dtos.UpdateDocumentLinksDTO) *apperror.AppError {
+	document, apperr := u.findDocument("UpdateDocumentLinks", ID)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("UpdateDocumentLinks").Error(constant.ErrNotDocumentEditor, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentEditor)
+	}
+
+	if len(updateLinksDTO.Links) > constant.MAX_DOCUMENT_LINKS {
+		return apperror.BadRequestError(constant.ErrTooManyDocumentLinks)
+	}
+
+	seen := make(map[string]bool)
+	targetIDs := make([]string, 0, len(updateLinksDTO.Links))
+	links := make([]entities.DocumentLink, len(updateLinksDTO.Links))
+	for i, link := range updateLinksDTO.Links {
+		linkType := strings.ToUpper(link.Type)
+		if !utils.ValidateDocumentLinkType(linkType) {
+			return apperror.BadRequestError(constant.ErrInvalidDocumentLinkType)
+		}
+		if link.DocumentID == ID {
+			return apperror.BadRequestError(constant.ErrSelfDocumentLink)
+		}
+		if seen[link.DocumentID] {
+			return apperror.BadRequestError(constant.ErrDuplicateDocumentLink)
+		}
+		seen[link.DocumentID] = true
+		targetIDs = append(targetIDs, link.DocumentID)
+
+		links[i] = entities.DocumentLink{
+			SourceID:  ID,
+			TargetID:  link.DocumentID,
+			Type:      linkType,
+			CreatedBy: req.ID,
+		}
+	}
+
+	targets, err := u.documentRepository.FindDocumentsByIDs(targetIDs)
+	if err != nil {
+		u.logger.Named("UpdateDocumentLinks").Error(constant.ErrUpdateDocumentLinksFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrUpdateDocumentLinksFailed)
+	}
+	found := make(map[string]bool, len(*targets))
+	for _, target := range *targets {
+		found[target.ID] = true
+	}
+	for _, targetID := range targetIDs {
+		if !found[targetID] {
+			return apperror.NotFoundError(fmt.Sprintf("%s: %s", constant.ErrLinkedDocumentNotFound, targetID))
+		}
+	}
+
+	_, backlinks, err := u.documentRepository.FindDocumentLinks(ID)
+	if err != nil {
+		u.logger.Named("UpdateDocumentLinks").Error(constant.ErrGetDocumentLinksFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrGetDocumentLinksFailed)
+	}
+	for _, backlink := range *backlinks {
+		if seen[backlink.SourceID] {
+			return apperror.BadRequestError(fmt.Sprintf("%s: %s", constant.ErrDocumentAlreadyLinked, backlink.SourceID))
+		}
+	}
+
+	if err := u.documentRepository.ReplaceDocumentLinks(ID, &links); err != nil {
+		u.logger.Named("UpdateDocumentLinks").Error(constant.ErrUpdateDocumentLinksFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrUpdateDocumentLinksFailed)
+	}
+
+	u.logger.Named("UpdateDocumentLinks").Info("Success: Document links updated", zap.String("document_id", ID), zap.Int("links", len(links)))
+	return nil
+}
+
+// findSuperseded maps the listed documents that a published document supersedes to the newest such document
+func (u *documentUsecase) findSuperseded(name string, documents *[]entities.Document) (map[string]string, *apperror.AppError) {
+	IDs := make([]string, len(*documents))
+	for i, d := range *documents {
+		IDs[i] = d.ID
+	}
+
+	superseded, err := u.documentRepository.FindSupersedingDocumentIDs(IDs)
+	if err != nil {
+		u.logger.Named(name).Error(constant.ErrGetDocumentLinksFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentLinksFailed)
+	}
+	return superseded, nil
+}
+
+// toDocumentLinkDTOs converts links with their other document loaded, backlinks are named from the target's side.
+// publicOnly leaves out documents the public cannot open.
+func toDocumentLinkDTOs(links []entities.DocumentLink, backlinks, publicOnly bool) []dtos.DocumentLinkDTO {
+	res := make([]dtos.DocumentLinkDTO, 0, len(links))
+	for _, link := range links {
+		linkType, d := link.Type, &link.Target
+		if backlinks {
+			linkType, d = constant.DocumentLinkInverse[link.Type], &link.Source
+		}
+		// a trashed document is not loaded
+		if d.ID == "" || publicOnly && !isPublic(d) {
+			continue
+		}
+
+		res = append(res, dtos.DocumentLinkDTO{
+			Type: strings.ToLower(linkType),
+			Document: dtos.LinkedDocumentDTO{
+				ID:        d.ID,
+				Title:     d.Title,
+				Slug:      d.Slug,
+				TypeID:    strings.ToLower(d.TypeID),
+				Status:    strings.ToLower(d.Status),
+				CreatedAt: d.CreatedAt,
+			},
+		})
+	}
+	return res
+}
+
+// supersededBy is the newest public document among the backlinks that supersedes d
+func supersededBy(d *entities.Document) *string {
+	var newest *entities.Document
+	for i, link := range d.Backlinks {
+		source := &d.Backlinks[i].Source
+		if link.Type != constant.LINK_SUPERSEDES || source.ID == "" || !isPublic(source) {
+			continue
+		}
+		if newest == nil || source.CreatedAt.After(newest.CreatedAt) {
+			newest = source
+		}
+	}
+	if newest == nil {
+		return nil
+	}
+	return &newest.ID
+}
+
+// review
+
+func (u *documentUsecase) GetPendingDocuments(req *dtos.UserDTO, pagination *dtos.GetPendingDocumentsDTO) (*dtos.PaginationResponse, *apperror.AppError) {
+	args := &repositories.FindPendingDocumentsArgs{
+		Offset:       (pagination.Page - 1) * pagination.PageSize,
+		Limit:        pagination.PageSize,
+		Organization: utils.GetOrg(req.Role),
+	}
+
+	documents, total, err := u.documentRepository.FindPendingDocuments(args)
+	if err != nil {
+		u.logger.Named("GetPendingDocuments").Error(constant.ErrGetDocumentFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentFailed)
+	}
+
+	data := make([]map[string]interface{}, 0)
+	for _, d := range *documents {
+		data = append(data, map[string]interface{}{
+			"id":           d.ID,
+			"title":        d.Title,
+			"slug":         d.Slug,
+			"banner":       d.Banner,
+			"cover":        d.Cover,
+			"type":         strings.ToLower(d.TypeID),
+			"status":       strings.ToLower(d.Status),
+			"created_at":   d.CreatedAt,
+			"updated_at":   d.UpdatedAt,
+			"organization": strings.ToLower(utils.GetOrg(d.Author.RoleID)),
+			"author": dtos.UserDTO{
+				ID:        d.Author.ID,
+				FirstName: d.Author.FirstName,
+				LastName:  d.Author.LastName,
+				Role:      d.Author.RoleID,
+			},
+		})
+	}
+
+	paginationResponse := dtos.PaginationResponse{
+		Data:      data,
+		Page:      fmt.Sprintf("%d", pagination.Page),
+		Limit:     fmt.Sprintf("%d", pagination.PageSize),
+		TotalPage: fmt.Sprintf("%d", int(math.Ceil(float64(total)/float64(pagination.PageSize)))),
+	}
+
+	return &paginationResponse, nil
+}
+
+func (u *documentUsecase) GetDocumentReviews(req *dtos.UserDTO, ID string) (*[]dtos.DocumentReviewDTO, *apperror.AppError) {
+	document, apperr := u.findDocument("GetDocumentReviews", ID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	// authors need to see why their document was rejected, reviewers need the whole trail
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("GetDocumentReviews").Error(constant.ErrNotDocumentReviewer, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentReviewer)
+	}
+
+	reviews, err := u.documentRepository.FindDocumentReviews(ID)
+	if err != nil {
+		u.logger.Named("GetDocumentReviews").Error(constant.ErrGetDocumentReviewFailed, zap.String("document_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentReviewFailed)
+	}
+
+	res := make([]dtos.DocumentReviewDTO, len(*reviews))
+	for i, r := range *reviews {
+		res[i] = dtos.DocumentReviewDTO{
+			ID:         r.ID,
+			DocumentID: r.DocumentID,
+			Action:     strings.ToLower(r.Action),
+			FromStatus: strings.ToLower(r.FromStatus),
+			ToStatus:   strings.ToLower(r.ToStatus),
+			Comment:    r.Comment,
+			CreatedAt:  r.CreatedAt,
+			Actor: dtos.UserDTO{
+				ID:        r.Actor.ID,
+				FirstName: r.Actor.FirstName,
+				LastName:  r.Actor.LastName,
+				Role:      r.Actor.RoleID,
+			},
+		}
+	}
+
+	return &res, nil
+}
+
+func (u *documentUsecase) SubmitDocument(req *dtos.UserDTO, ID string) *apperror.AppError {
+	return u.transitionDocument("SubmitDocument", req, ID, constant.SUBMIT, nil)
+}
+
+func (u *documentUsecase) ApproveDocument(req *dtos.UserDTO, ID string) *apperror.AppError {
+	return u.transitionDocument("ApproveDocument", req, ID, constant.APPROVE, nil)
+}
+
+func (u *documentUsecase) RejectDocument(req *dtos.UserDTO, ID string, reviewDTO *dtos.ReviewDocumentDTO) *apperror.AppError {
+	comment := strings.TrimSpace(reviewDTO.Comment)
+	if comment == "" {
+		return apperror.BadRequestError(constant.ErrReviewCommentRequired)
+	}
+
+	return u.transitionDocument("RejectDocument", req, ID, constant.REJECT, &comment)
+}
+
+func (u *documentUsecase) RequestDocumentChanges(req *dtos.UserDTO, ID string, reviewDTO *dtos.ReviewDocumentDTO) *apperror.AppError {
+	comment := strings.TrimSpace(reviewDTO.Comment)
+	if comment == "" {
+		return apperror.BadRequestError(constant.ErrReviewCommentRequired)
+	}
+
+	return u.transitionDocument("RequestDocumentChanges", req, ID, constant.REQUEST_CHANGES, &comment)
+}
+
+func (u *documentUsecase) transitionDocument(name string, req *dtos.UserDTO, ID, action string, comment *string) *apperror.AppError {
+	document, apperr := u.findDocument(name, ID)
+	if apperr != nil {
+		return apperr
+	}
+
+	if action == constant.SUBMIT {
+		if document.UserID != req.ID {
+			u.logger.Named(name).Error(constant.ErrNotDocumentAuthor, zap.String("document_id", ID), zap.String("user_id", req.ID))
+			return apperror.ForbiddenError(constant.ErrNotDocumentAuthor)
+		}
+	} else if !u.isOrgSuperAdmin(req, document) {
+		u.logger.Named(name).Error(constant.ErrNotDocumentReviewer, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentReviewer)
+	}
+
+	review, err := newDocumentReview(req, document, action, comment)
+	if err != nil {
+		u.logger.Named(name).Error(err.Error(), zap.String("document_id", ID), zap.String("status", document.Status), zap.String("action", action))
+		return apperror.BadRequestError(err.Error())
+	}
+
+	if err := u.documentRepository.UpdateDocumentStatus(review); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named(name).Error(constant.ErrDocumentStatusChanged, zap.String("document_id", ID))
+			return apperror.BadRequestError(constant.ErrDocumentStatusChanged)
+		}
+		u.logger.Named(name).Error(constant.ErrReviewDocumentFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrReviewDocumentFailed)
+	}
+
+	u.logger.Named(name).Info("Success: ", zap.String("document_id", ID), zap.String("from", review.FromStatus), zap.String("to", review.ToStatus))
+	return nil
+}
+
+// newDocumentReview records req taking action on the document, the action has to be allowed from its current status
+func newDocumentReview(req *dtos.UserDTO, document *entities.Document, action string, comment *string) (*entities.DocumentReview, error) {
+	nextStatus, err := utils.GetNextDocStatus(document.Status, action)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.DocumentReview{
+		ID:         fmt.Sprintf("RVW-%v", utils.GenerateRandomString("0123456789", 8)),
+		DocumentID: document.ID,
+		ActorID:    req.ID,
+		Action:     action,
+		FromStatus: document.Status,
+		ToStatus:   nextStatus,
+		Comment:    comment,
+	}, nil
+}
+
+// validateDocType looks up a document type from a request, unknown types are a bad request
+func (u *documentUsecase) validateDocType(name, docType string) (*entities.DocumentType, *apperror.AppError) {
+	documentType, err := u.documentTypeRepository.FindDocumentTypeByID(utils.NormalizeDocTypeID(docType))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named(name).Error(constant.ErrInvalidDocType, zap.String("type_id", docType))
+			return nil, apperror.BadRequestError(constant.ErrInvalidDocType)
+		}
+		u.logger.Named(name).Error(constant.ErrGetDocumentTypeFailed, zap.String("type_id", docType), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentTypeFailed)
+	}
+
+	return documentType, nil
+}
+
+func (u *documentUsecase) findDocument(name, ID string) (*entities.Document, *apperror.AppError) {
+	document, err := u.documentRepository.FindDocumentByID(ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named(name).Error(constant.ErrDocumentNotFound, zap.String("document_id", ID))
+			return nil, apperror.NotFoundError(constant.ErrDocumentNotFound)
+		}
+		u.logger.Named(name).Error(constant.ErrFindDocumentByID, zap.String("document_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	return document, nil
+}
+
+// revision
+
+func (u *documentUsecase) GetDocumentRevisions(req *dtos.UserDTO, ID string) (*[]dtos.DocumentRevisionDTO, *apperror.AppError) {
+	document, apperr := u.findDocument("GetDocumentRevisions", ID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("GetDocumentRevisions").Error(constant.ErrNotDocumentReviewer, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentReviewer)
+	}
+
+	revisions, err := u.documentRepository.FindDocumentRevisions(ID)
+	if err != nil {
+		u.logger.Named("GetDocumentRevisions").Error(constant.ErrGetDocumentRevisionFailed, zap.String("document_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentRevisionFailed)
+	}
+
+	res := make([]dtos.DocumentRevisionDTO, len(*revisions))
+	for i, r := range *revisions {
+		res[i] = toDocumentRevisionDTO(&r)
+		res[i].Content = ""
+		res[i].Source = ""
+	}
+
+	return &res, nil
+}
+
+func (u *documentUsecase) GetDocumentRevision(req *dtos.UserDTO, ID string, revision int) (*dtos.DocumentRevisionDTO, *apperror.AppError) {
+	document, apperr := u.findDocument("GetDocumentRevision", ID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("GetDocumentRevision").Error(constant.ErrNotDocumentReviewer, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentReviewer)
+	}
+
+	r, apperr := u.findRevision("GetDocumentRevision", ID, revision)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	res := toDocumentRevisionDTO(r)
+	return &res, nil
+}
+
+func (u *documentUsecase) DiffDocumentRevisions(req *dtos.UserDTO, ID string, from, to int) (*dtos.DocumentRevisionDiffDTO, *apperror.AppError) {
+	document, apperr := u.findDocument("DiffDocumentRevisions", ID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	if !isDocumentEditor(req, document) {
+		u.logger.Named("DiffDocumentRevisions").Error(constant.ErrNotDocumentReviewer, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return nil, apperror.ForbiddenError(constant.ErrNotDocumentReviewer)
+	}
+
+	fromRevision, apperr := u.findRevision("DiffDocumentRevisions", ID, from)
+	if apperr != nil {
+		return nil, apperr
+	}
+	toRevision, apperr := u.findRevision("DiffDocumentRevisions", ID, to)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	fields := make([]dtos.DocumentFieldDiffDTO, 0)
+	if fromRevision.Title != toRevision.Title {
+		fields = append(fields, dtos.DocumentFieldDiffDTO{Field: "title", From: &fromRevision.Title, To: &toRevision.Title})
+	}
+	if !utils.EqualStringPtr(fromRevision.Banner, toRevision.Banner) {
+		fields = append(fields, dtos.DocumentFieldDiffDTO{Field: "banner", From: fromRevision.Banner, To: toRevision.Banner})
+	}
+	if !utils.EqualStringPtr(fromRevision.Cover, toRevision.Cover) {
+		fields = append(fields, dtos.DocumentFieldDiffDTO{Field: "cover", From: fromRevision.Cover, To: toRevision.Cover})
+	}
+
+	lines := utils.DiffLines(revisionText(fromRevision), revisionText(toRevision))
+	content := make([]dtos.DocumentLineDiffDTO, len(lines))
+	for i, l := range lines {
+		content[i] = dtos.DocumentLineDiffDTO{Op: l.Op, Text: l.Text}
+	}
+
+	return &dtos.DocumentRevisionDiffDTO{
+		DocumentID: ID,
+		From:       from,
+		To:         to,
+		Fields:     fields,
+		Content:    content,
+	}, nil
+}
+
+func (u *documentUsecase) RestoreDocumentRevision(req *dtos.UserDTO, ID string, revision int) *apperror.AppError {
+	document, apperr := u.findDocument("RestoreDocumentRevision", ID)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !u.isOrgSuperAdmin(req, document) {
+		u.logger.Named("RestoreDocumentRevision").Error(constant.ErrNotDocumentReviewer, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentReviewer)
+	}
+
+	if _, apperr := u.findRevision("RestoreDocumentRevision", ID, revision); apperr != nil {
+		return apperr
+	}
+
+	if err := u.documentRepository.RestoreDocumentRevision(ID, revision, req.ID); err != nil {
+		u.logger.Named("RestoreDocumentRevision").Error(constant.ErrRestoreRevisionFailed, zap.String("document_id", ID), zap.Int("revision", revision), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrRestoreRevisionFailed)
+	}
+
+	u.logger.Named("RestoreDocumentRevision").Info("Success: ", zap.String("document_id", ID), zap.Int("revision", revision))
+	return nil
+}
+
+// trash
+
+func (u *documentUsecase) GetTrashedDocuments(req *dtos.UserDTO, pagination *dtos.GetTrashedDocumentsDTO) (*dtos.PaginationResponse, *apperror.AppError) {
+	args := &repositories.FindDeletedDocumentsArgs{
+		Offset:       (pagination.Page - 1) * pagination.PageSize,
+		Limit:        pagination.PageSize,
+		Organization: utils.GetOrg(req.Role),
+	}
+
+	documents, total, err := u.documentRepository.FindDeletedDocuments(args)
+	if err != nil {
+		u.logger.Named("GetTrashedDocuments").Error(constant.ErrGetDocumentFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentFailed)
+	}
+
+	retention := time.Duration(u.cfg.GetJob().TrashRetentionDays) * 24 * time.Hour
+
+	data := make([]map[string]interface{}, 0)
+	for _, d := range *documents {
+		data = append(data, map[string]interface{}{
+			"id":           d.ID,
+			"title":        d.Title,
+			"type":         strings.ToLower(d.TypeID),
+			"status":       strings.ToLower(d.Status),
+			"organization": strings.ToLower(utils.GetOrg(d.Author.RoleID)),
+			"created_at":   d.CreatedAt,
+			"deleted_at":   d.DeletedAt.Time,
+			"purge_at":     d.DeletedAt.Time.Add(retention),
+		})
+	}
+
+	paginationResponse := dtos.PaginationResponse{
+		Data:      data,
+		Page:      fmt.Sprintf("%d", pagination.Page),
+		Limit:     fmt.Sprintf("%d", pagination.PageSize),
+		TotalPage: fmt.Sprintf("%d", int(math.Ceil(float64(total)/float64(pagination.PageSize)))),
+	}
+
+	return &paginationResponse, nil
+}
+
+func (u *documentUsecase) RestoreDocumentByID(req *dtos.UserDTO, ID string) *apperror.AppError {
+	document, apperr := u.findDeletedDocument("RestoreDocumentByID", ID)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !u.isOrgSuperAdmin(req, document) {
+		u.logger.Named("RestoreDocumentByID").Error(constant.ErrNotDocumentReviewer, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentReviewer)
+	}
+
+	if err := u.documentRepository.RestoreDocumentByID(ID); err != nil {
+		u.logger.Named("RestoreDocumentByID").Error(constant.ErrRestoreDocumentFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrRestoreDocumentFailed)
+	}
+
+	u.logger.Named("RestoreDocumentByID").Info("Success: Document restored", zap.String("document_id", ID))
+	return nil
+}
+
+func (u *documentUsecase) PurgeDocumentByID(req *dtos.UserDTO, ID string) *apperror.AppError {
+	document, apperr := u.findDeletedDocument("PurgeDocumentByID", ID)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !u.isOrgSuperAdmin(req, document) {
+		u.logger.Named("PurgeDocumentByID").Error(constant.ErrNotDocumentReviewer, zap.String("document_id", ID), zap.String("user_id", req.ID))
+		return apperror.ForbiddenError(constant.ErrNotDocumentReviewer)
+	}
+
+	return u.purgeDocument("PurgeDocumentByID", ID)
+}
+
+// job
+
+func (u *documentUsecase) PurgeExpiredTrash() {
+	cutoff := time.Now().AddDate(0, 0, -u.cfg.GetJob().TrashRetentionDays)
+
+	IDs, err := u.documentRepository.FindDeletedDocumentIDsBefore(cutoff)
+	if err != nil {
+		u.logger.Named("PurgeExpiredTrash").Error(constant.ErrGetDocumentFailed, zap.Error(err))
+		return
+	}
+
+	purged := 0
+	for _, ID := range IDs {
+		if err := u.purgeDocument("PurgeExpiredTrash", ID); err != nil {
+			continue
+		}
+		purged++
+	}
+
+	u.logger.Named("PurgeExpiredTrash").Info("Success: ", zap.Int("purged", purged), zap.Time("deleted_before", cutoff))
+}
+
+func (u *documentUsecase) ArchiveExpiredDocuments() {
+	now := time.Now()
+
+	archived, err := u.documentRepository.ArchiveExpiredDocuments(now)
+	if err != nil {
+		u.logger.Named("ArchiveExpiredDocuments").Error(constant.ErrArchiveDocumentFailed, zap.Error(err))
+		return
+	}
+
+	u.logger.Named("ArchiveExpiredDocuments").Info("Success: ", zap.Int64("archived", archived), zap.Time("expired_before", now))
+}
+
+// purgeDocument removes the variants of every attachment before deleting the rows, so a failure part way
+// leaves the document in trash to be purged again. Originals are deleted afterwards, once no other attachment shares them.
+func (u *documentUsecase) purgeDocument(name, ID string) *apperror.AppError {
+	attachments, err := u.attachmentRepository.FindAllAttachmentsByDocumentID(ID)
+	if err != nil {
+		u.logger.Named(name).Error(constant.ErrGetAttachmentFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrPurgeDocumentFailed)
+	}
+
+	for _, a := range *attachments {
+		for _, objectKey := range variantObjectKeys(&a) {
+			if err := u.attachmentRepository.DeleteAttachmentFromS3(u.cfg.GetAws().BucketName, objectKey); err != nil {
+				u.logger.Named(name).Error(constant.ErrDeleteAttachmentFromS3, zap.String("document_id", ID), zap.String("attachment_id", a.ID), zap.Error(err))
+				return apperror.InternalServerError(constant.ErrPurgeDocumentFailed)
+			}
+		}
+	}
+
+	released, err := u.documentRepository.PurgeDocumentByID(ID)
+	if err != nil {
+		u.logger.Named(name).Error(constant.ErrPurgeDocumentFailed, zap.String("document_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrPurgeDocumentFailed)
+	}
+	deleteReleasedObjects(u.cfg, u.logger.Named(name), u.attachmentRepository, released)
+
+	u.logger.Named(name).Info("Success: Document purged", zap.String("document_id", ID), zap.Int("attachments", len(*attachments)))
+	return nil
+}
+
+func (u *documentUsecase) findDeletedDocument(name, ID string) (*entities.Document, *apperror.AppError) {
+	document, err := u.documentRepository.FindDeletedDocumentByID(ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named(name).Error(constant.ErrDocumentNotInTrash, zap.String("document_id", ID))
+			return nil, apperror.NotFoundError(constant.ErrDocumentNotInTrash)
+		}
+		u.logger.Named(name).Error(constant.ErrFindDocumentByID, zap.String("document_id", ID), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrFindDocumentByID)
+	}
+
+	return document, nil
+}
+
+func (u *documentUsecase) findRevision(name, ID string, revision int) (*entities.DocumentRevision, *apperror.AppError) {
+	r, err := u.documentRepository.FindDocumentRevision(ID, revision)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named(name).Error(constant.ErrRevisionNotFound, zap.String("document_id", ID), zap.Int("revision", revision))
+			return nil, apperror.NotFoundError(constant.ErrRevisionNotFound)
+		}
+		u.logger.Named(name).Error(constant.ErrGetDocumentRevisionFailed, zap.String("document_id", ID), zap.Int("revision", revision), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetDocumentRevisionFailed)
+	}
+
+	return r, nil
+}
+
+// revisionText is what the author wrote in a revision, which diffs better than the rendered HTML
+func revisionText(r *entities.DocumentRevision) string {
+	if r.Source != "" {
+		return r.Source
+	}
+	return r.Content
+}
+
+func toDocumentRevisionDTO(r *entities.DocumentRevision) dtos.DocumentRevisionDTO {
+	return dtos.DocumentRevisionDTO{
+		ID:           r.ID,
+		DocumentID:   r.DocumentID,
+		Revision:     r.Revision,
+		Title:        r.Title,
+		Content:      r.Content,
+		Source:       r.Source,
+		Format:       r.Format,
+		Banner:       r.Banner,
+		Cover:        r.Cover,
+		RestoredFrom: r.RestoredFrom,
+		CreatedAt:    r.CreatedAt,
+		Editor: dtos.UserDTO{
+			ID:        r.Editor.ID,
+			FirstName: r.Editor.FirstName,
+			LastName:  r.Editor.LastName,
+			Role:      r.Editor.RoleID,
+		},
+	}
+}
+
+// isOrgSuperAdmin reports whether req is a superadmin of the same organization as the document's author
+func (u *documentUsecase) isOrgSuperAdmin(req *dtos.UserDTO, document *entities.Document) bool {
+	return utils.IsSuperAdmin(req.Role) && utils.GetOrg(req.Role) == utils.GetOrg(document.Author.RoleID)
+}
+
+// isPublic reports whether anyone may open the document by its link
+func isPublic(document *entities.Document) bool {
+	return document.Status == constant.PUBLISHED || document.Status == constant.ARCHIVED
+}
+
+// isDocumentEditor reports whether req is the document's author or a superadmin of the author's organization
+func isDocumentEditor(req *dtos.UserDTO, document *entities.Document) bool {
+	return document.UserID == req.ID || utils.IsSuperAdmin(req.Role) && utils.GetOrg(req.Role) == utils.GetOrg(document.Author.RoleID)
+}
+
+// highlightDocument marks the search terms in the title and in a snippet of the content
+func highlightDocument(document *localizedDocument, query string) map[string]string {
+	return map[string]string{
+		"title":   utils.HighlightSnippet(document.Title, query, len([]rune(document.Title))),
+		"content": utils.HighlightSnippet(utils.PlainText(document.Content), query, constant.SNIPPET_SIZE),
+	}
+}
+
+// localizedDocument is the text of a document in the locale it is served in
+type localizedDocument struct {
+	Locale      string
+	Title       string
+	Content     string
+	Excerpt     *string
+	ReadingTime int
+}
+
+// localizeDocument picks the translation closest to lang, see utils.PickLocale.
+// Translations have to be loaded on the document.
+func localizeDocument(d *entities.Document, lang string) *localizedDocument {
+	locale := utils.PickLocale(lang, availableLocales(d))
+	for _, t := range d.Translations {
+		if t.Locale == locale && locale != d.Locale {
+			return &localizedDocument{Locale: t.Locale, Title: t.Title, Content: t.Content, Excerpt: excerptOrAuto(t.Excerpt, t.AutoExcerpt), ReadingTime: t.ReadingTime}
+		}
+	}
+	return &localizedDocument{Locale: d.Locale, Title: d.Title, Content: d.Content, Excerpt: excerptOrAuto(d.Excerpt, d.AutoExcerpt), ReadingTime: d.ReadingTime}
+}
+
+func excerptOrAuto(excerpt *string, auto string) *string {
+	if excerpt != nil || auto == "" {
+		return excerpt
+	}
+	return &auto
+}
+
+// renderContent validates the format of content sent by a client and renders it to safe HTML
+func renderContent(format, source string) (string, string, *apperror.AppError) {
+	format = strings.ToLower(format)
+	if format == "" {
+		format = constant.DEFAULT_CONTENT_FORMAT
+	} else if !utils.ValidateContentFormat(format) {
+		return "", "", apperror.BadRequestError(constant.ErrInvalidContentFormat)
+	}
+
+	content := utils.RenderContent(format, source)
+	if strings.TrimSpace(content) == "" {
+		return "", "", apperror.BadRequestError(constant.ErrEmptyContent)
+	}
+
+	return format, content, nil
+}
+
+// plainExcerpt strips markup from an excerpt written by the author, an empty one means none
+func plainExcerpt(excerpt *string) *string {
+	if excerpt == nil {
+		return nil
+	}
+	text := utils.PlainText(*excerpt)
+	if text == "" {
+		return nil
+	}
+	return &text
+}
+
+// availableLocales lists the base locale of a document first, then its translations
+func availableLocales(d *entities.Document) []string {
+	locales := []string{d.Locale}
+	for _, t := range d.Translations {
+		if t.Locale != d.Locale {
+			locales = append(locales, t.Locale)
+		}
+	}
+	return locales
+}
+
+func tagNames(tags []entities.Tag) []string {
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+func toDocumentDTO(d *entities.Document, lang string) dtos.DocumentDTO {
+	localized := localizeDocument(d, lang)
+	res := dtos.DocumentDTO{
+		ID:        d.ID,
+		Title:     localized.Title,
+		Slug:      d.Slug,
+		Content:   localized.Content,
+		Excerpt:   localized.Excerpt,
+		Locale:    localized.Locale,
+		Banner:    d.Banner,
+		Cover:     d.Cover,
+		UserID:    d.UserID,
+		TypeID:    strings.ToLower(d.TypeID),
+		Status:    strings.ToLower(d.Status),
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+
+		ReadingTime:      localized.ReadingTime,
+		ExpiresAt:        d.ExpiresAt,
+		ArchivedAt:       d.ArchivedAt,
+		AvailableLocales: availableLocales(d),
+
+		Author: dtos.UserDTO{
+			ID:        d.Author.ID,
+			FirstName: d.Author.FirstName,
+			LastName:  d.Author.LastName,
+			Role:      d.Author.RoleID,
+		},
+		Tags:   make([]dtos.TagDTO, len(d.Tags)),
+		Images: make([]dtos.AttachmentDTO, 0),
+		Docs:   make([]dtos.AttachmentDTO, 0),
+
+		// the detail is public, so are the documents it links to
+		Links:        toDocumentLinkDTOs(d.Links, false, true),
+		Backlinks:    toDocumentLinkDTOs(d.Backlinks, true, true),
+		SupersededBy: supersededBy(d),
+	}
+
+	for i, t := range d.Tags {
+		res.Tags[i] = toTagDTO(&t)
+	}
+
+	for _, a := range d.Attachments {
+		// the detail is public, private attachments are only reachable through a signed URL
+		if a.Visibility == constant.ATTACHMENT_PRIVATE {
+			continue
+		}
+
+		attachment := toAttachmentDTO(&a, d.Author.RoleID)
+		if a.TypeID == constant.IMAGE {
+			res.Images = append(res.Images, attachment)
+		} else {
+			res.Docs = append(res.Docs, attachment)
+		}
+	}
+
+	return res
+}
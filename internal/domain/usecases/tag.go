@@ -0,0 +1,16 @@
+package usecases
+
+import (
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/apperror"
+)
+
+type TagUsecase interface {
+	GetAllTags() (*[]dtos.TagDTO, *apperror.AppError)
+	GetTagUsage(organization string) (*[]dtos.TagUsageDTO, *apperror.AppError)
+
+	// super-admin method
+	CreateTag(createTagDTO *dtos.CreateTagDTO) (*dtos.TagDTO, *apperror.AppError)
+	UpdateTagByID(ID string, updateTagDTO *dtos.UpdateTagDTO) *apperror.AppError
+	DeleteTagByID(ID string) *apperror.AppError
+}
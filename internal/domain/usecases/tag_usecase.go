@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/repositories"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/apperror"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+	"github.com/isd-sgcu/sucu-backend-2024/utils"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type tagUsecase struct {
+	cfg           config.Config
+	logger        *zap.Logger
+	tagRepository repositories.TagRepository
+}
+
+func NewTagUsecase(cfg config.Config, logger *zap.Logger, tagRepository repositories.TagRepository) TagUsecase {
+	return &tagUsecase{
+		cfg:           cfg,
+		logger:        logger,
+		tagRepository: tagRepository,
+	}
+}
+
+func (u *tagUsecase) GetAllTags() (*[]dtos.TagDTO, *apperror.AppError) {
+	tags, err := u.tagRepository.FindAllTags()
+	if err != nil {
+		u.logger.Named("GetAllTags").Error(constant.ErrGetTagFailed, zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetTagFailed)
+	}
+
+	res := make([]dtos.TagDTO, len(*tags))
+	for i, t := range *tags {
+		res[i] = toTagDTO(&t)
+	}
+
+	return &res, nil
+}
+
+func (u *tagUsecase) GetTagUsage(organization string) (*[]dtos.TagUsageDTO, *apperror.AppError) {
+	usage, err := u.tagRepository.FindTagUsage(organization)
+	if err != nil {
+		u.logger.Named("GetTagUsage").Error(constant.ErrGetTagUsageFailed, zap.String("organization", organization), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrGetTagUsageFailed)
+	}
+
+	// rows come ordered by tag name, one per organization
+	res := make([]dtos.TagUsageDTO, 0)
+	for _, row := range *usage {
+		if len(res) == 0 || res[len(res)-1].ID != row.TagID {
+			res = append(res, dtos.TagUsageDTO{
+				ID:            row.TagID,
+				Name:          row.Name,
+				Organizations: make(map[string]int64),
+			})
+		}
+
+		tag := &res[len(res)-1]
+		tag.Total += row.Count
+		tag.Organizations[strings.ToLower(row.Organization)] = row.Count
+	}
+
+	return &res, nil
+}
+
+func (u *tagUsecase) CreateTag(createTagDTO *dtos.CreateTagDTO) (*dtos.TagDTO, *apperror.AppError) {
+	name := utils.NormalizeTagName(createTagDTO.Name)
+	if name == "" {
+		return nil, apperror.BadRequestError(constant.ErrInvalidTagName)
+	}
+
+	if apperr := u.checkTagName("CreateTag", name); apperr != nil {
+		return nil, apperr
+	}
+
+	tag := &entities.Tag{
+		ID:   fmt.Sprintf("TAG-%v", utils.GenerateRandomString("0123456789", 8)),
+		Name: name,
+	}
+
+	if err := u.tagRepository.InsertTag(tag); err != nil {
+		u.logger.Named("CreateTag").Error(constant.ErrInsertTagFailed, zap.String("name", name), zap.Error(err))
+		return nil, apperror.InternalServerError(constant.ErrInsertTagFailed)
+	}
+
+	u.logger.Named("CreateTag").Info("Success: ", zap.String("tag_id", tag.ID))
+
+	res := toTagDTO(tag)
+	return &res, nil
+}
+
+func (u *tagUsecase) UpdateTagByID(ID string, updateTagDTO *dtos.UpdateTagDTO) *apperror.AppError {
+	name := utils.NormalizeTagName(updateTagDTO.Name)
+	if name == "" {
+		return apperror.BadRequestError(constant.ErrInvalidTagName)
+	}
+
+	if apperr := u.checkTagName("UpdateTagByID", name); apperr != nil {
+		return apperr
+	}
+
+	if err := u.tagRepository.UpdateTagByID(ID, map[string]interface{}{"name": name}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("UpdateTagByID").Error(constant.ErrTagNotFound, zap.String("tag_id", ID))
+			return apperror.NotFoundError(constant.ErrTagNotFound)
+		}
+		u.logger.Named("UpdateTagByID").Error(constant.ErrUpdateTagFailed, zap.String("tag_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrUpdateTagFailed)
+	}
+
+	u.logger.Named("UpdateTagByID").Info("Success: Tag updated", zap.String("tag_id", ID))
+	return nil
+}
+
+func (u *tagUsecase) DeleteTagByID(ID string) *apperror.AppError {
+	if err := u.tagRepository.DeleteTagByID(ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.logger.Named("DeleteTagByID").Error(constant.ErrTagNotFound, zap.String("tag_id", ID))
+			return apperror.NotFoundError(constant.ErrTagNotFound)
+		}
+		u.logger.Named("DeleteTagByID").Error(constant.ErrDeleteTagFailed, zap.String("tag_id", ID), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrDeleteTagFailed)
+	}
+
+	u.logger.Named("DeleteTagByID").Info("Success: Tag deleted", zap.String("tag_id", ID))
+	return nil
+}
+
+// checkTagName rejects a name that another tag already uses
+func (u *tagUsecase) checkTagName(name, tagName string) *apperror.AppError {
+	existingTag, err := u.tagRepository.FindTagByName(tagName)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		u.logger.Named(name).Error(constant.ErrGetTagFailed, zap.String("name", tagName), zap.Error(err))
+		return apperror.InternalServerError(constant.ErrGetTagFailed)
+	}
+
+	if existingTag != nil {
+		u.logger.Named(name).Error(constant.ErrTagAlreadyExists, zap.String("name", tagName))
+		return apperror.BadRequestError(constant.ErrTagAlreadyExists)
+	}
+
+	return nil
+}
+
+func toTagDTO(t *entities.Tag) dtos.TagDTO {
+	return dtos.TagDTO{
+		ID:        t.ID,
+		Name:      t.Name,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
@@ -6,4 +6,8 @@ type Usecase interface {
 	User() UserUsecase
 	Attachment() AttachmentUsecase
 	Document() DocumentUsecase
+	Tag() TagUsecase
+	DocumentType() DocumentTypeUsecase
+	Budget() BudgetUsecase
+	Dataset() DatasetUsecase
 }
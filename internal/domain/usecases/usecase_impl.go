@@ -8,20 +8,28 @@ import (
 )
 
 type usecase struct {
-	MiddlewareUsecase MiddlewareUsecase
-	AuthUsecase       AuthUsecase
-	UserUsecase       UserUsecase
-	AttachmentUsecase AttachmentUsecase
-	DocumentUsecase   DocumentUsecase
+	MiddlewareUsecase   MiddlewareUsecase
+	AuthUsecase         AuthUsecase
+	UserUsecase         UserUsecase
+	AttachmentUsecase   AttachmentUsecase
+	DocumentUsecase     DocumentUsecase
+	TagUsecase          TagUsecase
+	DocumentTypeUsecase DocumentTypeUsecase
+	BudgetUsecase       BudgetUsecase
+	DatasetUsecase      DatasetUsecase
 }
 
 func NewUsecase(repo repositories.Repository, cfg config.Config, logger *zap.Logger) Usecase {
 	return &usecase{
-		MiddlewareUsecase: NewMiddlewareUsecase(cfg, logger.Named("MiddlewareSvc"), repo.User()),
-		AuthUsecase:       NewAuthUsecase(cfg, logger.Named("AuthSvc"), repo.User()),
-		UserUsecase:       NewUserUsecase(cfg, logger.Named("UserSvc"), repo.User()),
-		AttachmentUsecase: NewAttachmentUsecase(cfg, logger.Named("AttachmentSvc"), repo.Attachment()),
-		DocumentUsecase:   NewDocumentUsecase(cfg, logger.Named("DocumentSvc"), repo.Document(), repo.User()),
+		MiddlewareUsecase:   NewMiddlewareUsecase(cfg, logger.Named("MiddlewareSvc"), repo.User()),
+		AuthUsecase:         NewAuthUsecase(cfg, logger.Named("AuthSvc"), repo.User()),
+		UserUsecase:         NewUserUsecase(cfg, logger.Named("UserSvc"), repo.User()),
+		AttachmentUsecase:   NewAttachmentUsecase(cfg, logger.Named("AttachmentSvc"), repo.Attachment(), repo.Document()),
+		DocumentUsecase:     NewDocumentUsecase(cfg, logger.Named("DocumentSvc"), repo.Document(), repo.User(), repo.Attachment(), repo.Tag(), repo.DocumentType()),
+		TagUsecase:          NewTagUsecase(cfg, logger.Named("TagSvc"), repo.Tag()),
+		DocumentTypeUsecase: NewDocumentTypeUsecase(cfg, logger.Named("DocumentTypeSvc"), repo.DocumentType()),
+		BudgetUsecase:       NewBudgetUsecase(cfg, logger.Named("BudgetSvc"), repo.Budget(), repo.Document()),
+		DatasetUsecase:      NewDatasetUsecase(cfg, logger.Named("DatasetSvc"), repo.Dataset(), repo.Document()),
 	}
 }
 
@@ -44,3 +52,19 @@ func (u *usecase) Attachment() AttachmentUsecase {
 func (u *usecase) Document() DocumentUsecase {
 	return u.DocumentUsecase
 }
+
+func (u *usecase) Tag() TagUsecase {
+	return u.TagUsecase
+}
+
+func (u *usecase) DocumentType() DocumentTypeUsecase {
+	return u.DocumentTypeUsecase
+}
+
+func (u *usecase) Budget() BudgetUsecase {
+	return u.BudgetUsecase
+}
+
+func (u *usecase) Dataset() DatasetUsecase {
+	return u.DatasetUsecase
+}
@@ -3,11 +3,114 @@ package dtos
 import "time"
 
 type AttachmentDTO struct {
-	ID          string    `json:"id"`
-	DisplayName string    `json:"name"`
-	DocumentID  string    `json:"document_id"`
-	TypeID      string    `json:"type_id"`
-	RoleID      string    `json:"role_id"` // role_id จะเอาไว้ให้ client ดูว่าไฟล์นี้มาจาก org อะไร sgcu or sucu
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            string                 `json:"id"`
+	DisplayName   string                 `json:"name"`
+	DocumentID    string                 `json:"document_id"`
+	TypeID        string                 `json:"type_id"`
+	Visibility    string                 `json:"visibility"` // visibility: public, private
+	ContentType   string                 `json:"content_type"`
+	Size          int64                  `json:"size"`           // bytes, 0 for files uploaded before sizes were kept
+	Hash          string                 `json:"hash"`           // hex SHA-256, empty for files uploaded before hashing
+	VariantStatus string                 `json:"variant_status"` // variant_status: none, pending, ready, failed
+	Variants      []AttachmentVariantDTO `json:"variants"`
+	RoleID        string                 `json:"role_id"` // role_id จะเอาไว้ให้ client ดูว่าไฟล์นี้มาจาก org อะไร sgcu or sucu
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// AttachmentVariantDTO is a resized copy of an image, its ID is the object key next to the original
+type AttachmentVariantDTO struct {
+	ID     string `json:"id"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"` // format: jpeg, png, webp
+}
+
+type GetAllAttachmentsDTO struct {
+	Page         int
+	PageSize     int
+	TypeID       string // type: image, docs
+	DocumentID   string
+	Organization string // organization: sccu, sgcu
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+type GetAllAttachmentsByRoleDTO struct {
+	Page         int
+	PageSize     int
+	TypeID       string // type: image, docs
+	DocumentID   string
+	Organization string // organization: sccu, sgcu
+	Role         string
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+type AttachmentURLDTO struct {
+	URL        string     `json:"url"`
+	Visibility string     `json:"visibility"` // visibility: public, private
+	ExpiresAt  *time.Time `json:"expires_at"` // empty for the stable URL of a public attachment
+}
+
+type CreateAttachmentUploadsDTO struct {
+	Visibility string                    `json:"visibility"` // visibility: public, private
+	Files      []AttachmentUploadFileDTO `json:"files" validate:"required,min=1,dive"`
+}
+
+type AttachmentUploadFileDTO struct {
+	Name        string `json:"name" validate:"required"`
+	Size        int64  `json:"size" validate:"required"` // bytes
+	ContentType string `json:"content_type"`             // guessed from the extension when empty
+}
+
+type AttachmentUploadDTO struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"` // the client must send these with the upload
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type CreateTusUploadDTO struct {
+	Name       string // from the filename entry of Upload-Metadata
+	Length     int64  // Upload-Length, bytes
+	Visibility string // visibility: public, private
+}
+
+type TusUploadDTO struct {
+	ID        string
+	Offset    int64 // bytes received so far
+	Length    int64
+	ExpiresAt time.Time
+}
+
+// StorageReconcileDTO lists where the bucket and the attachment rows disagree
+type StorageReconcileDTO struct {
+	OrphanObjects   []string `json:"orphan_objects"`   // objects no attachment, variant or upload owns
+	MissingObjects  []string `json:"missing_objects"`  // attachments whose object is gone
+	MissingVariants []string `json:"missing_variants"` // variants whose object is gone
+	StalePending    []string `json:"stale_pending"`    // attachments left pending by an upload that never finished
+	Removed         bool     `json:"removed"`
+}
+
+// StorageSavingsDTO is how much storage sharing one object between attachments of the same content saves.
+// Attachments uploaded before deduplication are not counted.
+type StorageSavingsDTO struct {
+	Objects         int64             `json:"objects"`          // stored objects
+	References      int64             `json:"references"`       // attachments referring to them
+	StoredBytes     int64             `json:"stored_bytes"`     // bytes in storage
+	ReferencedBytes int64             `json:"referenced_bytes"` // bytes the attachments would take without sharing
+	SavedBytes      int64             `json:"saved_bytes"`
+	MostShared      []SharedObjectDTO `json:"most_shared"` // objects saving the most bytes
+}
+
+type SharedObjectDTO struct {
+	ObjectKey  string `json:"object_key"`
+	Hash       string `json:"hash"`
+	Visibility string `json:"visibility"` // visibility: public, private
+	Size       int64  `json:"size"`
+	References int64  `json:"references"`
+	SavedBytes int64  `json:"saved_bytes"`
 }
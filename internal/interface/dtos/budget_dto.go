@@ -0,0 +1,74 @@
+package dtos
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"time"
+)
+
+// amounts are exact decimals in the unit of the currency, e.g. 12500.50, sent as JSON numbers or strings
+
+type BudgetDTO struct {
+	DocumentID   string          `json:"document_id"`
+	FiscalYear   int             `json:"fiscal_year"`  // Buddhist era, e.g. 2567
+	Organization string          `json:"organization"` // organization: sgcu, sccu
+	Currency     string          `json:"currency"`     // currency: THB, USD, EUR
+	TotalPlanned json.Number     `json:"total_planned"`
+	TotalActual  json.Number     `json:"total_actual"`
+	Variance     json.Number     `json:"variance"` // total actual minus total planned
+	Items        []BudgetItemDTO `json:"items"`
+	UpdatedBy    string          `json:"updated_by"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+type BudgetItemDTO struct {
+	Category    string      `json:"category" validate:"required,max=100"` // e.g. activities, equipment
+	Description string      `json:"description" validate:"max=255"`
+	Planned     json.Number `json:"planned" validate:"required"`
+	Actual      json.Number `json:"actual"` // 0 when empty
+}
+
+type UpdateBudgetDTO struct {
+	FiscalYear   int             `json:"fiscal_year" validate:"required"`
+	Currency     string          `json:"currency"`              // THB when empty
+	TotalPlanned json.Number     `json:"total_planned"`         // must equal the sum of the items when given
+	TotalActual  json.Number     `json:"total_actual"`          // must equal the sum of the items when given
+	Items        []BudgetItemDTO `json:"items" validate:"dive"` // in display order, replaces every item
+}
+
+type ImportBudgetDTO struct {
+	FiscalYear   int
+	Currency     string
+	TotalPlanned string
+	TotalActual  string
+	File         *multipart.FileHeader // csv or xlsx with a header row: category, description, planned, actual
+}
+
+type BudgetSummaryDTO struct {
+	DocumentID      string              `json:"document_id"`
+	FiscalYear      int                 `json:"fiscal_year"`
+	Organization    string              `json:"organization"`
+	Currency        string              `json:"currency"`
+	TotalPlanned    json.Number         `json:"total_planned"`
+	TotalActual     json.Number         `json:"total_actual"`
+	Variance        json.Number         `json:"variance"`
+	VariancePercent *float64            `json:"variance_percent"` // variance relative to planned, null when nothing was planned
+	Categories      []BudgetCategoryDTO `json:"categories"`       // in order of first appearance
+}
+
+type BudgetCategoryDTO struct {
+	Category        string      `json:"category"`
+	Items           int         `json:"items"`
+	Planned         json.Number `json:"planned"`
+	Actual          json.Number `json:"actual"`
+	Variance        json.Number `json:"variance"`
+	VariancePercent *float64    `json:"variance_percent"`
+}
+
+type GetBudgetsDTO struct {
+	Page         int
+	PageSize     int
+	Organization string // organization: sccu, sgcu
+	FiscalYear   int
+}
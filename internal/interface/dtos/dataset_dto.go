@@ -0,0 +1,77 @@
+package dtos
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+type DatasetDTO struct {
+	DocumentID string             `json:"document_id"`
+	Dimensions []string           `json:"dimensions"`
+	Series     []DatasetSeriesDTO `json:"series"`
+	UpdatedBy  string             `json:"updated_by"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+type DatasetSeriesDTO struct {
+	Name   string            `json:"name" validate:"required,max=100"` // e.g. new students
+	Unit   string            `json:"unit" validate:"max=50"`           // e.g. students, THB, %
+	Points []DatasetPointDTO `json:"points" validate:"dive"`
+}
+
+type DatasetPointDTO struct {
+	Label      string            `json:"label" validate:"max=255"` // e.g. 2567, Faculty of Engineering
+	Value      *float64          `json:"value" validate:"required"`
+	Dimensions map[string]string `json:"dimensions"` // dimension name -> value, declared by the dataset
+}
+
+type UpdateDatasetDTO struct {
+	Dimensions []string           `json:"dimensions"`             // e.g. faculty, gender
+	Series     []DatasetSeriesDTO `json:"series" validate:"dive"` // in display order, replaces every series
+}
+
+type ImportDatasetDTO struct {
+	Series string                // name of the series when the file has no series column
+	Unit   string                // unit of the series when the file has no unit column
+	File   *multipart.FileHeader // csv or xlsx in long form: one point per row with a value column
+}
+
+type DatasetQueryDTO struct {
+	Series    []string          // only these series, every series when empty
+	GroupBy   []string          // label or dimension names
+	Aggregate string            // aggregate: sum, avg, min, max, count
+	Filters   map[string]string // label or dimension name -> value a point must have
+}
+
+type DatasetAggregateDTO struct {
+	GroupBy   []string                  `json:"group_by"`
+	Aggregate string                    `json:"aggregate"`
+	Series    []DatasetGroupedSeriesDTO `json:"series"`
+}
+
+type DatasetGroupedSeriesDTO struct {
+	Name   string            `json:"name"`
+	Unit   string            `json:"unit"`
+	Groups []DatasetGroupDTO `json:"groups"` // in order of first appearance
+}
+
+type DatasetGroupDTO struct {
+	Key    map[string]string `json:"key"` // grouped name -> value
+	Value  float64           `json:"value"`
+	Points int               `json:"points"` // points in the group
+}
+
+// DatasetChartDTO has one value per label and series, the shape most chart libraries take
+type DatasetChartDTO struct {
+	GroupBy   string                  `json:"group_by"`
+	Aggregate string                  `json:"aggregate"`
+	Labels    []string                `json:"labels"`
+	Series    []DatasetChartSeriesDTO `json:"series"`
+}
+
+type DatasetChartSeriesDTO struct {
+	Name string     `json:"name"`
+	Unit string     `json:"unit"`
+	Data []*float64 `json:"data"` // aligned with labels, null where the series has no point
+}
@@ -5,42 +5,77 @@ import "time"
 type DocumentDTO struct {
 	ID        string    `json:"id"`
 	Title     string    `json:"title"`
-	Content   string    `json:"content"`
+	Slug      string    `json:"slug"`
+	Content   string    `json:"content"` // sanitized HTML
+	Excerpt   *string   `json:"excerpt"` // plain text, generated from the content unless the author wrote one
+	Locale    string    `json:"locale"`  // locale the title, content and excerpt are in
 	Banner    *string   `json:"banner"`
 	Cover     *string   `json:"cover"`
 	UserID    string    `json:"user_id"`
 	TypeID    string    `json:"type_id"`
+	Status    string    `json:"status"` // status: draft, pending, published, rejected, changes_requested, archived
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
+	ReadingTime      int        `json:"reading_time"` // minutes
+	ExpiresAt        *time.Time `json:"expires_at"`
+	ArchivedAt       *time.Time `json:"archived_at"`
+	AvailableLocales []string   `json:"available_locales"`
+
 	Author UserDTO         `json:"author"`
+	Tags   []TagDTO        `json:"tags"`
 	Images []AttachmentDTO `json:"images"` // images file eg. jpeg jpg png
 	Docs   []AttachmentDTO `json:"docs"`   // docs file eg. pdf xlsx pptx
+
+	Links        []DocumentLinkDTO `json:"links"`         // documents this one links to
+	Backlinks    []DocumentLinkDTO `json:"backlinks"`     // documents linking to this one
+	SupersededBy *string           `json:"superseded_by"` // ID of the newest document superseding this one
 }
 
 type CreateDocumentDTO struct {
 	ID      string  `json:"id"`
 	Title   string  `json:"title" validate:"required"`
+	Slug    string  `json:"slug"` // generated from the title when empty
 	Content string  `json:"content" validate:"required"`
+	Format  string  `json:"format"` // format of content: markdown, html; defaults to markdown
+	Excerpt *string `json:"excerpt"`
+	Locale  string  `json:"locale"` // locale: th, en; defaults to th
 	Banner  *string `json:"banner"`
 	Cover   *string `json:"cover"`
 	UserID  string  `json:"user_id" validate:"required"`
 	TypeID  string  `json:"type_id" validate:"required"`
+
+	ExpiresAt *time.Time `json:"expires_at"` // archived once passed, never when empty
 }
 
 type UpdateDocumentDTO struct {
 	Title   string  `json:"title"`
 	Content string  `json:"content"`
+	Format  string  `json:"format"` // format of content: markdown, html; keeps the current one when empty
+	Excerpt *string `json:"excerpt"`
 	Banner  *string `json:"banner"`
 	Cover   *string `json:"cover"`
 }
 
+type UpdateDocumentSlugDTO struct {
+	Slug string `json:"slug" validate:"required,max=255"` // normalized with the same rules as generated slugs
+}
+
+type UpdateDocumentExpiryDTO struct {
+	ExpiresAt *time.Time `json:"expires_at"` // null removes the expiry and brings an archived document back
+}
+
 type GetAllDocumentsDTO struct {
 	Page         int
 	PageSize     int
-	Title        string
-	Organization string // organization: sccu, sgcu
-	DocumentType string // type: statistic, budget, announcement
+	Query        string   // full-text search over title and content
+	Tags         []string // tag names
+	TagMode      string   // tag mode: any, all
+	Lang         string   // locale to show, see utils.ResolveLocale
+	Locale       string   // only documents readable in this locale
+	Organization string   // organization: sccu, sgcu
+	DocumentType string   // type: statistic, budget, announcement
+	Archived     bool     // list the archive instead of the live documents
 	StartTime    time.Time
 	EndTime      time.Time
 }
@@ -48,10 +83,161 @@ type GetAllDocumentsDTO struct {
 type GetAllDocumentsByRoleDTO struct {
 	Page         int
 	PageSize     int
-	Title        string
-	DocumentType string // type: statistic, budget, announcement
-	Organization string // organization: sccu, sgcu
+	Query        string   // full-text search over title and content
+	Tags         []string // tag names
+	TagMode      string   // tag mode: any, all
+	Lang         string   // locale to show, see utils.ResolveLocale
+	Locale       string   // only documents readable in this locale
+	DocumentType string   // type: statistic, budget, announcement
+	Organization string   // organization: sccu, sgcu
 	Role         string
 	StartTime    time.Time
 	EndTime      time.Time
 }
+
+type ReviewDocumentDTO struct {
+	Comment string `json:"comment" validate:"required"` // reason shown to the author
+}
+
+type DocumentReviewDTO struct {
+	ID         string    `json:"id"`
+	DocumentID string    `json:"document_id"`
+	Action     string    `json:"action"`      // action: submit, approve, reject, request_changes
+	FromStatus string    `json:"from_status"` // status before the transition
+	ToStatus   string    `json:"to_status"`   // status after the transition
+	Comment    *string   `json:"comment"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	Actor UserDTO `json:"actor"`
+}
+
+type DocumentTranslationDTO struct {
+	Locale      string    `json:"locale"` // locale: th, en
+	Title       string    `json:"title"`
+	Content     string    `json:"content"` // sanitized HTML
+	Source      string    `json:"source"`  // content as written
+	Format      string    `json:"format"`  // format of source: markdown, html
+	Excerpt     *string   `json:"excerpt"`
+	ReadingTime int       `json:"reading_time"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type UpdateDocumentTranslationDTO struct {
+	Title   string  `json:"title" validate:"required,max=255"`
+	Content string  `json:"content" validate:"required"`
+	Format  string  `json:"format"` // format of content: markdown, html; defaults to markdown
+	Excerpt *string `json:"excerpt"`
+}
+
+type GetPendingDocumentsDTO struct {
+	Page     int
+	PageSize int
+}
+
+type DocumentRevisionDTO struct {
+	ID           string    `json:"id"`
+	DocumentID   string    `json:"document_id"`
+	Revision     int       `json:"revision"`
+	Title        string    `json:"title"`
+	Content      string    `json:"content,omitempty"` // omitted in revision listings
+	Source       string    `json:"source,omitempty"`  // content as written, omitted in revision listings
+	Format       string    `json:"format"`            // format of source: markdown, html
+	Banner       *string   `json:"banner"`
+	Cover        *string   `json:"cover"`
+	RestoredFrom *int      `json:"restored_from"` // revision this one was restored from
+	CreatedAt    time.Time `json:"created_at"`
+
+	Editor UserDTO `json:"editor"`
+}
+
+type DocumentFieldDiffDTO struct {
+	Field string  `json:"field"` // field: title, banner, cover
+	From  *string `json:"from"`
+	To    *string `json:"to"`
+}
+
+type DocumentLineDiffDTO struct {
+	Op   string `json:"op"` // op: equal, insert, delete
+	Text string `json:"text"`
+}
+
+type DocumentRevisionDiffDTO struct {
+	DocumentID string                 `json:"document_id"`
+	From       int                    `json:"from"`
+	To         int                    `json:"to"`
+	Fields     []DocumentFieldDiffDTO `json:"fields"`  // changed fields only
+	Content    []DocumentLineDiffDTO  `json:"content"` // line-level diff of the content
+}
+
+type GetTrashedDocumentsDTO struct {
+	Page     int
+	PageSize int
+}
+
+type FeaturedDocumentDTO struct {
+	Scope     string                 `json:"scope"`    // scope: global, sgcu, sccu
+	Position  int                    `json:"position"` // starts at 1 within a scope
+	ExpiresAt *time.Time             `json:"expires_at"`
+	Document  map[string]interface{} `json:"document"` // same fields as a listing item
+}
+
+type FeaturedSlotDTO struct {
+	DocumentID string     `json:"document_id" validate:"required"`
+	ExpiresAt  *time.Time `json:"expires_at"` // never expires when empty
+}
+
+type UpdateFeaturedDocumentsDTO struct {
+	Slots []FeaturedSlotDTO `json:"slots" validate:"dive"` // in display order, replaces every slot of the scope
+}
+
+type DocumentLinkDTO struct {
+	Type     string            `json:"type"` // type: amends, supersedes, related, follow_up; for backlinks amended_by, superseded_by, related, followed_up_by
+	Document LinkedDocumentDTO `json:"document"`
+}
+
+type LinkedDocumentDTO struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	TypeID    string    `json:"type_id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DocumentLinksDTO struct {
+	Links     []DocumentLinkDTO `json:"links"`
+	Backlinks []DocumentLinkDTO `json:"backlinks"`
+}
+
+type DocumentLinkTargetDTO struct {
+	DocumentID string `json:"document_id" validate:"required"`
+	Type       string `json:"type" validate:"required"` // type: amends, supersedes, related, follow_up
+}
+
+type UpdateDocumentLinksDTO struct {
+	Links []DocumentLinkTargetDTO `json:"links" validate:"dive"` // replaces every link going out of the document
+}
+
+type BulkDocumentsDTO struct {
+	DocumentIDs []string `json:"document_ids" validate:"required,min=1"`
+	Operation   string   `json:"operation" validate:"required"` // operation: delete, restore, publish, archive, change_type, add_tag
+	Mode        string   `json:"mode"`                          // mode: atomic, best_effort; defaults to atomic
+	TypeID      string   `json:"type_id"`                       // new type for change_type
+	TagIDs      []string `json:"tag_ids"`                       // tags for add_tag
+}
+
+type BulkDocumentsResultDTO struct {
+	Operation string                  `json:"operation"`
+	Mode      string                  `json:"mode"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+	Results   []BulkDocumentResultDTO `json:"results"` // in the order of document_ids
+}
+
+type BulkDocumentResultDTO struct {
+	DocumentID string  `json:"document_id"`
+	Success    bool    `json:"success"`
+	Code       int     `json:"code"` // HTTP status the item would get on its own
+	Error      *string `json:"error"`
+}
@@ -0,0 +1,33 @@
+package dtos
+
+import "time"
+
+type DocumentTypeDTO struct {
+	ID             string    `json:"id"` // e.g. announcement, meeting_minutes
+	NameTH         string    `json:"name_th"`
+	NameEN         string    `json:"name_en"`
+	Description    *string   `json:"description"`
+	SortOrder      int       `json:"sort_order"`
+	RequiresReview bool      `json:"requires_review"` // documents of ordinary admins wait for approval
+	Core           bool      `json:"core"`            // built-in types cannot be deleted
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type CreateDocumentTypeDTO struct {
+	ID             string  `json:"id" validate:"required,max=100"` // letters, digits and underscores
+	NameTH         string  `json:"name_th" validate:"required,max=255"`
+	NameEN         string  `json:"name_en" validate:"required,max=255"`
+	Description    *string `json:"description"`
+	SortOrder      int     `json:"sort_order"`
+	RequiresReview bool    `json:"requires_review"`
+}
+
+// UpdateDocumentTypeDTO only changes the fields that are present
+type UpdateDocumentTypeDTO struct {
+	NameTH         *string `json:"name_th" validate:"omitempty,min=1,max=255"`
+	NameEN         *string `json:"name_en" validate:"omitempty,min=1,max=255"`
+	Description    *string `json:"description"`
+	SortOrder      *int    `json:"sort_order"`
+	RequiresReview *bool   `json:"requires_review"`
+}
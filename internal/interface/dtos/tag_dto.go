@@ -0,0 +1,29 @@
+package dtos
+
+import "time"
+
+type TagDTO struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateTagDTO struct {
+	Name string `json:"name" validate:"required,max=100"` // e.g. scholarship, sports day, fy2567
+}
+
+type UpdateTagDTO struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type TagUsageDTO struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	Total         int64            `json:"total"`         // published documents carrying the tag
+	Organizations map[string]int64 `json:"organizations"` // organization: sccu, sgcu -> published documents
+}
+
+type UpdateDocumentTagsDTO struct {
+	TagIDs []string `json:"tag_ids"` // replaces every tag of the document, empty clears them
+}
@@ -1,57 +1,194 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/usecases"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/response"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/validator"
+	"github.com/isd-sgcu/sucu-backend-2024/utils"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
 )
 
 type AttachmentHandler struct {
 	attachmentUsecase usecases.AttachmentUsecase
+	validator         validator.DTOValidator
 }
 
-func NewAttachmentHandler(attachmentUsecase usecases.AttachmentUsecase) *AttachmentHandler {
+func NewAttachmentHandler(attachmentUsecase usecases.AttachmentUsecase, validator validator.DTOValidator) *AttachmentHandler {
 	return &AttachmentHandler{
 		attachmentUsecase: attachmentUsecase,
+		validator:         validator,
 	}
 }
 
 // GetAllAttachments godoc
-// @Summary Get all attachments
+// @Summary Get attachments of published documents
 // @Tags Attachments
 // @Produce json
-// @Success 200 {object} []dtos.AttachmentDTO
+// @Param page query int false "Page"
+// @Param page_size query int false "Page size"
+// @Param type query string false "Attachment type" Enums(image, docs)
+// @Param document_id query string false "Document ID"
+// @Param organization query string false "Organization" Enums(sgcu, sccu)
+// @Param start_time query string false "Uploaded at or after, RFC 3339"
+// @Param end_time query string false "Uploaded at or before, RFC 3339"
+// @Success 200 {object} response.Response{data=dtos.PaginationResponse{data=[]dtos.AttachmentDTO}}
 // @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
 // @Router /attachments [get]
 func (h *AttachmentHandler) GetAllAttachments(c *fiber.Ctx) error {
-	return nil
+	getAttachmentsDTO := dtos.GetAllAttachmentsDTO{
+		Page:         c.QueryInt("page", 1),
+		PageSize:     c.QueryInt("page_size", 10),
+		TypeID:       c.Query("type"),
+		DocumentID:   c.Query("document_id"),
+		Organization: c.Query("organization"),
+	}
+
+	errors := validateAttachmentFilters(getAttachmentsDTO.TypeID, getAttachmentsDTO.Organization, getAttachmentsDTO.PageSize)
+
+	startTime, endTime, err := parseUploadTimes(c)
+	if err != nil {
+		errors = append(errors, constant.ErrInvalidTimeFormat)
+	}
+
+	if len(errors) != 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errors, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	getAttachmentsDTO.StartTime = startTime
+	getAttachmentsDTO.EndTime = endTime
+
+	paginationResp, apperr := h.attachmentUsecase.GetAllAttachments(&getAttachmentsDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, paginationResp)
+	return resp.SendResponse(c, fiber.StatusOK)
 }
 
 // GetAllAttachmentsByRole godoc
-// @Summary Get all attachments by role
+// @Summary Get attachments of documents written by a role, including unpublished ones
 // @Tags Attachments
 // @Produce json
-// @Param role_id path string true "Role of the user"
-// @Success 200 {object} []dtos.AttachmentDTO
+// @Param role_id path string true "Role of the document author"
+// @Param page query int false "Page"
+// @Param page_size query int false "Page size"
+// @Param type query string false "Attachment type" Enums(image, docs)
+// @Param document_id query string false "Document ID"
+// @Param organization query string false "Organization" Enums(sgcu, sccu)
+// @Param start_time query string false "Uploaded at or after, RFC 3339"
+// @Param end_time query string false "Uploaded at or before, RFC 3339"
+// @Success 200 {object} response.Response{data=dtos.PaginationResponse{data=[]dtos.AttachmentDTO}}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
 // @Router /attachments/role/{role_id} [get]
+// @Security BearerAuth
 func (h *AttachmentHandler) GetAllAttachmentsByRole(c *fiber.Ctx) error {
-	return nil
+	getAttachmentsDTO := dtos.GetAllAttachmentsByRoleDTO{
+		Page:         c.QueryInt("page", 1),
+		PageSize:     c.QueryInt("page_size", 10),
+		TypeID:       c.Query("type"),
+		DocumentID:   c.Query("document_id"),
+		Organization: c.Query("organization"),
+		Role:         c.Params("role_id"),
+	}
+
+	errors := validateAttachmentFilters(getAttachmentsDTO.TypeID, getAttachmentsDTO.Organization, getAttachmentsDTO.PageSize)
+
+	if role := getAttachmentsDTO.Role; role == "" || !utils.ValidateRole(role) {
+		errors = append(errors, constant.ErrInvalidRole)
+	}
+
+	startTime, endTime, err := parseUploadTimes(c)
+	if err != nil {
+		errors = append(errors, constant.ErrInvalidTimeFormat)
+	}
+
+	if len(errors) != 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errors, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	getAttachmentsDTO.StartTime = startTime
+	getAttachmentsDTO.EndTime = endTime
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	paginationResp, apperr := h.attachmentUsecase.GetAllAttachmentsByRole(user, &getAttachmentsDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, paginationResp)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+func validateAttachmentFilters(typeID, organization string, pageSize int) []string {
+	var errors []string
+
+	if !utils.ValidateAttachmentType(typeID) {
+		errors = append(errors, constant.ErrInvalidAttachmentType)
+	}
+
+	if !utils.ValidateOrg(organization) {
+		errors = append(errors, constant.ErrInvalidOrg)
+	}
+
+	if pageSize > constant.MAX_PAGE_SIZE || pageSize <= 0 {
+		errors = append(errors, constant.ErrInvalidPageSize)
+	}
+
+	return errors
+}
+
+// parseUploadTimes reads the upload date range, which defaults to everything uploaded until now
+func parseUploadTimes(c *fiber.Ctx) (time.Time, time.Time, error) {
+	startTime, err := time.Parse(time.RFC3339, c.Query("start_time", time.Time{}.UTC().Format(time.RFC3339)))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	endTime, err := time.Parse(time.RFC3339, c.Query("end_time", time.Now().UTC().Format(time.RFC3339)))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return startTime, endTime, nil
 }
 
 // CreateAttachments godoc
 // @Summary Create new attachments
+// @Description The content of each file must match its extension. SVGs with script, event handlers or external links are rejected. Every rejected file is named in the error.
 // @Tags Attachments
 // @Accept multipart/form-data
 // @Produce json
 // @Param document_id path string true "Document ID"
 // @Param file formData file true "Attachment files"
+// @Param visibility formData string false "Private files are only reachable through a signed URL" Enums(public, private) default(public)
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 413 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /attachments/{document_id} [post]
+// @Security BearerAuth
 func (h *AttachmentHandler) CreateAttachments(c *fiber.Ctx) error {
 	documentID := strings.Trim(c.Params("document_id"), " ")
 
@@ -61,15 +198,297 @@ func (h *AttachmentHandler) CreateAttachments(c *fiber.Ctx) error {
 		return resp.SendResponse(c, fiber.StatusBadRequest)
 	}
 
-	if err := h.attachmentUsecase.CreateAttachments(documentID, form.File); err != nil {
+	user := c.Locals("user").(*dtos.UserDTO)
+	if err := h.attachmentUsecase.CreateAttachments(user, documentID, c.FormValue("visibility"), form.File); err != nil {
 		resp := response.NewResponseFactory(response.ERROR, err.Error())
-		return resp.SendResponse(c, fiber.StatusInternalServerError)
+		return resp.SendResponse(c, err.HttpCode)
 	}
 
 	resp := response.NewResponseFactory(response.SUCCESS, nil)
 	return resp.SendResponse(c, fiber.StatusOK)
 }
 
+// GetAttachmentURL godoc
+// @Summary Get a download URL of an attachment
+// @Description Public attachments have a stable URL. Private ones get a presigned URL that expires after 15 minutes, issued to the author or a superadmin of the author's organization.
+// @Tags Attachments
+// @Produce json
+// @Param attachment_id path string true "Attachment ID"
+// @Param width query int false "Width of an image variant, the original when empty"
+// @Param format query string false "Format of the image variant, the original format when empty" Enums(jpeg, png, webp)
+// @Success 200 {object} response.Response{data=dtos.AttachmentURLDTO}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /attachments/{attachment_id}/url [get]
+// @Security BearerAuth
+func (h *AttachmentHandler) GetAttachmentURL(c *fiber.Ctx) error {
+	user := c.Locals("user").(*dtos.UserDTO)
+	url, apperr := h.attachmentUsecase.GetAttachmentURL(user, c.Params("attachment_id"), c.QueryInt("width", 0), strings.ToLower(c.Query("format")))
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, url)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// CreateAttachmentUploads godoc
+// @Summary Start uploading files straight to storage
+// @Description Each file gets a presigned PUT URL valid for an hour. Upload the file with the returned headers, then complete the upload. Uploads that are not completed in time are removed.
+// @Tags Attachments
+// @Accept json
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param uploads body dtos.CreateAttachmentUploadsDTO true "Files to upload"
+// @Success 201 {object} response.Response{data=[]dtos.AttachmentUploadDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /attachments/{document_id}/uploads [post]
+// @Security BearerAuth
+func (h *AttachmentHandler) CreateAttachmentUploads(c *fiber.Ctx) error {
+	var createUploadsDTO dtos.CreateAttachmentUploadsDTO
+	if err := c.BodyParser(&createUploadsDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(createUploadsDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	uploads, apperr := h.attachmentUsecase.CreateAttachmentUploads(user, c.Params("document_id"), &createUploadsDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, uploads)
+	return resp.SendResponse(c, fiber.StatusCreated)
+}
+
+// CompleteAttachmentUpload godoc
+// @Summary Complete a direct upload
+// @Description The file in storage must have the size and content type given when the upload was started.
+// @Tags Attachments
+// @Produce json
+// @Param upload_id path string true "Upload ID"
+// @Success 201 {object} response.Response{data=dtos.AttachmentDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /attachments/uploads/{upload_id}/complete [post]
+// @Security BearerAuth
+func (h *AttachmentHandler) CompleteAttachmentUpload(c *fiber.Ctx) error {
+	user := c.Locals("user").(*dtos.UserDTO)
+	attachment, apperr := h.attachmentUsecase.CompleteAttachmentUpload(user, c.Params("upload_id"))
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, attachment)
+	return resp.SendResponse(c, fiber.StatusCreated)
+}
+
+// TusResumable answers every resumable upload request with the tus version,
+// and turns away requests other than OPTIONS made for another version
+func (h *AttachmentHandler) TusResumable(c *fiber.Ctx) error {
+	c.Set("Tus-Resumable", constant.TUS_VERSION)
+	if c.Method() != fiber.MethodOptions && c.Get("Tus-Resumable") != constant.TUS_VERSION {
+		c.Set("Tus-Version", constant.TUS_VERSION)
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrTusVersionUnsupported)
+		return resp.SendResponse(c, fiber.StatusPreconditionFailed)
+	}
+	return c.Next()
+}
+
+// OptionsTusUpload godoc
+// @Summary Describe the resumable upload endpoint
+// @Description tus 1.0 discovery, lists the supported versions, extensions and the largest upload.
+// @Tags Attachments
+// @Param document_id path string true "Document ID"
+// @Success 204
+// @Header 204 {string} Tus-Version "Supported tus versions"
+// @Header 204 {string} Tus-Extension "Supported tus extensions"
+// @Header 204 {integer} Tus-Max-Size "Largest upload in bytes"
+// @Router /attachments/{document_id}/tus [options]
+func (h *AttachmentHandler) OptionsTusUpload(c *fiber.Ctx) error {
+	c.Set("Tus-Version", constant.TUS_VERSION)
+	c.Set("Tus-Extension", constant.TUS_EXTENSIONS)
+	c.Set("Tus-Max-Size", strconv.FormatInt(constant.MAX_FILE_SIZE, 10))
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// CreateTusUpload godoc
+// @Summary Start a resumable upload
+// @Description tus 1.0 creation. Upload-Metadata must carry the base64 encoded filename, or name, and may carry the visibility, public by default.
+// @Description Send the file with PATCH requests to the returned Location. An upload that gets no chunk for a day is removed.
+// @Tags Attachments
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param Tus-Resumable header string true "tus version" Enums(1.0.0)
+// @Param Upload-Length header int true "File size in bytes"
+// @Param Upload-Metadata header string true "e.g. filename cmVwb3J0LnBkZg==,visibility cHJpdmF0ZQ=="
+// @Success 201
+// @Header 201 {string} Location "URL of the upload"
+// @Header 201 {string} Upload-Expires "When the upload is removed unless another chunk arrives"
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /attachments/{document_id}/tus [post]
+// @Security BearerAuth
+func (h *AttachmentHandler) CreateTusUpload(c *fiber.Ctx) error {
+	length, err := strconv.ParseInt(c.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidUploadSize)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	metadata, err := parseUploadMetadata(c.Get("Upload-Metadata"))
+	// Uppy sends the file name as name, most other clients as filename
+	name := metadata["filename"]
+	if name == "" {
+		name = metadata["name"]
+	}
+	if err != nil || name == "" {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidUploadMetadata)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	upload, apperr := h.attachmentUsecase.CreateTusUpload(user, c.Params("document_id"), &dtos.CreateTusUploadDTO{
+		Name:       name,
+		Length:     length,
+		Visibility: metadata["visibility"],
+	})
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	c.Location(strings.TrimSuffix(c.Path(), "/") + "/" + upload.ID)
+	c.Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// parseUploadMetadata decodes the comma separated "key base64value" pairs of Upload-Metadata, a key may come without a value
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		fields := strings.Fields(pair)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, errors.New(constant.ErrInvalidUploadMetadata)
+		}
+		if len(fields) == 1 {
+			metadata[fields[0]] = ""
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		metadata[fields[0]] = string(value)
+	}
+	return metadata, nil
+}
+
+// HeadTusUpload godoc
+// @Summary Get the offset of a resumable upload
+// @Description tus 1.0 offset query, only the user who started the upload may resume it.
+// @Tags Attachments
+// @Param document_id path string true "Document ID"
+// @Param upload_id path string true "Upload ID"
+// @Param Tus-Resumable header string true "tus version" Enums(1.0.0)
+// @Success 200
+// @Header 200 {integer} Upload-Offset "Bytes received so far"
+// @Header 200 {integer} Upload-Length "File size in bytes"
+// @Header 200 {string} Upload-Expires "When the upload is removed unless another chunk arrives"
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 410 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Router /attachments/{document_id}/tus/{upload_id} [head]
+// @Security BearerAuth
+func (h *AttachmentHandler) HeadTusUpload(c *fiber.Ctx) error {
+	// the offset changes with every chunk
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	upload, apperr := h.attachmentUsecase.GetTusUpload(user, c.Params("document_id"), c.Params("upload_id"))
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	c.Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	c.Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// PatchTusUpload godoc
+// @Summary Send a chunk of a resumable upload
+// @Description tus 1.0 PATCH. Upload-Offset must be the offset of the upload. Once the last byte arrives the file is checked like CreateAttachments and becomes an attachment of the document.
+// @Description When that fails the upload stays one byte short, so resuming it sends the last byte again and tries once more.
+// @Tags Attachments
+// @Accept application/offset+octet-stream
+// @Param document_id path string true "Document ID"
+// @Param upload_id path string true "Upload ID"
+// @Param Tus-Resumable header string true "tus version" Enums(1.0.0)
+// @Param Upload-Offset header int true "Offset of the chunk in bytes"
+// @Success 204
+// @Header 204 {integer} Upload-Offset "Bytes received so far"
+// @Header 204 {string} Upload-Expires "When the upload is removed unless another chunk arrives"
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 410 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 415 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /attachments/{document_id}/tus/{upload_id} [patch]
+// @Security BearerAuth
+func (h *AttachmentHandler) PatchTusUpload(c *fiber.Ctx) error {
+	if contentType, _, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType)); err != nil || contentType != constant.TUS_CONTENT_TYPE {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidChunkContentType)
+		return resp.SendResponse(c, fiber.StatusUnsupportedMediaType)
+	}
+
+	offset, err := strconv.ParseInt(c.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidUploadOffset)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	upload, apperr := h.attachmentUsecase.AppendTusUpload(user, c.Params("document_id"), c.Params("upload_id"), offset, body)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	c.Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
 // DeleteAttachment godoc
 // @Summary Delete an attachment by ID
 // @Tags Attachments
@@ -90,3 +509,106 @@ func (h *AttachmentHandler) DeleteAttachment(c *fiber.Ctx) error {
 	resp := response.NewResponseFactory(response.SUCCESS, nil)
 	return resp.SendResponse(c, fiber.StatusOK)
 }
+
+// GetStorageSavings godoc
+// @Summary Get the storage saved by sharing one object between attachments of the same content
+// @Description Attachments with the same content and visibility share a stored object. Files uploaded before deduplication are not counted.
+// @Tags Attachments
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=dtos.StorageSavingsDTO}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /attachments/storage/savings [get]
+func (h *AttachmentHandler) GetStorageSavings(c *fiber.Ctx) error {
+	savings, apperr := h.attachmentUsecase.GetStorageSavings()
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, savings)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetStoredFile godoc
+// @Summary Download a stored file
+// @Description Only served by the local and memory storage drivers. Private files need the query of a presigned URL.
+// @Tags Files
+// @Produce octet-stream
+// @Param bucket path string true "Bucket name"
+// @Param key path string true "Object key"
+// @Param expires query int false "Expiry of a presigned URL, unix seconds"
+// @Param signature query string false "Signature of a presigned URL"
+// @Success 200 {file} file
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /files/{bucket}/{key} [get]
+func (h *AttachmentHandler) GetStoredFile(c *fiber.Ctx) error {
+	objectKey, query, err := storedFilePath(c)
+	if err != nil {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidStoredFileKey)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	body, info, apperr := h.attachmentUsecase.GetStoredFile(c.Params("bucket"), objectKey, query)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	c.Set(fiber.HeaderContentType, info.ContentType)
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	return c.SendStream(body, int(info.Size))
+}
+
+// PutStoredFile godoc
+// @Summary Upload a file to a presigned URL
+// @Description Only served by the local and memory storage drivers. The Content-Type header must be the one the URL was signed for.
+// @Tags Files
+// @Accept octet-stream
+// @Produce json
+// @Param bucket path string true "Bucket name"
+// @Param key path string true "Object key"
+// @Param expires query int true "Expiry of the presigned URL, unix seconds"
+// @Param public query bool true "Whether the file is public"
+// @Param signature query string true "Signature of the presigned URL"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /files/{bucket}/{key} [put]
+func (h *AttachmentHandler) PutStoredFile(c *fiber.Ctx) error {
+	objectKey, query, err := storedFilePath(c)
+	if err != nil {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidStoredFileKey)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	if apperr := h.attachmentUsecase.PutStoredFile(c.Params("bucket"), objectKey, c.Get(fiber.HeaderContentType), query, body); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, nil)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// storedFilePath reads the object key from the wildcard of the route and the presigned query
+func storedFilePath(c *fiber.Ctx) (string, url.Values, error) {
+	objectKey, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return "", nil, err
+	}
+	query, err := url.ParseQuery(string(c.Context().QueryArgs().QueryString()))
+	if err != nil {
+		return "", nil, err
+	}
+	return objectKey, query, nil
+}
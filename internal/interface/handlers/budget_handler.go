@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/usecases"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/response"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/validator"
+	"github.com/isd-sgcu/sucu-backend-2024/utils"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+type BudgetHandler struct {
+	budgetUsecase usecases.BudgetUsecase
+	validator     validator.DTOValidator
+}
+
+func NewBudgetHandler(budgetUsecase usecases.BudgetUsecase, validator validator.DTOValidator) *BudgetHandler {
+	return &BudgetHandler{
+		budgetUsecase: budgetUsecase,
+		validator:     validator,
+	}
+}
+
+// GetBudgets godoc
+// @Summary Get budgets of published documents with their totals
+// @Tags Budgets
+// @Produce json
+// @Param page query int false "Page"
+// @Param page_size query int false "Page size"
+// @Param organization query string false "Organization" Enums(sgcu, sccu)
+// @Param fiscal_year query int false "Fiscal year in the Buddhist era, e.g. 2567"
+// @Success 200 {object} response.Response{data=dtos.PaginationResponse}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /budgets [get]
+func (h *BudgetHandler) GetBudgets(c *fiber.Ctx) error {
+	getBudgetsDTO := dtos.GetBudgetsDTO{
+		Page:         c.QueryInt("page", 1),
+		PageSize:     c.QueryInt("page_size", 10),
+		Organization: c.Query("organization"),
+		FiscalYear:   c.QueryInt("fiscal_year", 0),
+	}
+
+	var errors []string
+
+	if !utils.ValidateOrg(getBudgetsDTO.Organization) {
+		errors = append(errors, constant.ErrInvalidOrg)
+	}
+
+	if fy := getBudgetsDTO.FiscalYear; fy != 0 && (fy < constant.MIN_FISCAL_YEAR || fy > constant.MAX_FISCAL_YEAR) {
+		errors = append(errors, constant.ErrInvalidFiscalYear)
+	}
+
+	if ps := getBudgetsDTO.PageSize; ps > constant.MAX_PAGE_SIZE || ps <= 0 {
+		errors = append(errors, constant.ErrInvalidPageSize)
+	}
+
+	if len(errors) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errors, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	paginationResp, apperr := h.budgetUsecase.GetBudgets(&getBudgetsDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, paginationResp)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetBudget godoc
+// @Summary Get the budget of a published document with every line item
+// @Tags Budgets
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response{data=dtos.BudgetDTO}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /budgets/{document_id} [get]
+func (h *BudgetHandler) GetBudget(c *fiber.Ctx) error {
+	budget, apperr := h.budgetUsecase.GetBudget(c.Params("document_id"))
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, budget)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetBudgetSummary godoc
+// @Summary Get category totals and planned-vs-actual variance of a budget
+// @Tags Budgets
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response{data=dtos.BudgetSummaryDTO}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /budgets/{document_id}/summary [get]
+func (h *BudgetHandler) GetBudgetSummary(c *fiber.Ctx) error {
+	summary, apperr := h.budgetUsecase.GetBudgetSummary(c.Params("document_id"))
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, summary)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// UpdateBudget godoc
+// @Summary Create or replace the budget of a BUDGET document
+// @Description Totals are computed from the line items, declared totals are checked against them.
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param budget body dtos.UpdateBudgetDTO true "Budget data"
+// @Success 200 {object} response.Response{data=dtos.BudgetDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /budgets/{document_id} [put]
+// @Security BearerAuth
+func (h *BudgetHandler) UpdateBudget(c *fiber.Ctx) error {
+	var updateBudgetDTO dtos.UpdateBudgetDTO
+	if err := c.BodyParser(&updateBudgetDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(updateBudgetDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	budget, apperr := h.budgetUsecase.UpdateBudget(user, c.Params("document_id"), &updateBudgetDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, budget)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// ImportBudget godoc
+// @Summary Replace the line items of a budget from a CSV or XLSX file
+// @Description The first row names the columns: category, description, planned, actual. Only category and planned are required.
+// @Tags Budgets
+// @Accept multipart/form-data
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param file formData file true "CSV or XLSX file"
+// @Param fiscal_year formData int true "Fiscal year in the Buddhist era, e.g. 2567"
+// @Param currency formData string false "Currency" Enums(THB, USD, EUR)
+// @Param total_planned formData string false "Declared total of the planned amounts"
+// @Param total_actual formData string false "Declared total of the actual amounts"
+// @Success 200 {object} response.Response{data=dtos.BudgetDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /budgets/{document_id}/import [post]
+// @Security BearerAuth
+func (h *BudgetHandler) ImportBudget(c *fiber.Ctx) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	importBudgetDTO := dtos.ImportBudgetDTO{
+		Currency:     c.FormValue("currency"),
+		TotalPlanned: c.FormValue("total_planned"),
+		TotalActual:  c.FormValue("total_actual"),
+		File:         file,
+	}
+
+	// a missing or malformed fiscal year is left at 0 and rejected by the usecase
+	importBudgetDTO.FiscalYear, _ = strconv.Atoi(strings.TrimSpace(c.FormValue("fiscal_year")))
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	budget, apperr := h.budgetUsecase.ImportBudget(user, c.Params("document_id"), &importBudgetDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, budget)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// DeleteBudget godoc
+// @Summary Delete the budget of a document, the document itself is kept
+// @Tags Budgets
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /budgets/{document_id} [delete]
+// @Security BearerAuth
+func (h *BudgetHandler) DeleteBudget(c *fiber.Ctx) error {
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.budgetUsecase.DeleteBudget(user, c.Params("document_id")); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
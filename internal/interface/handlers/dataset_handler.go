@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/usecases"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/response"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/validator"
+	"github.com/isd-sgcu/sucu-backend-2024/utils"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+type DatasetHandler struct {
+	datasetUsecase usecases.DatasetUsecase
+	validator      validator.DTOValidator
+}
+
+func NewDatasetHandler(datasetUsecase usecases.DatasetUsecase, validator validator.DTOValidator) *DatasetHandler {
+	return &DatasetHandler{
+		datasetUsecase: datasetUsecase,
+		validator:      validator,
+	}
+}
+
+// GetDataset godoc
+// @Summary Get the dataset of a published document with every series and point
+// @Tags Datasets
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response{data=dtos.DatasetDTO}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /datasets/{document_id} [get]
+func (h *DatasetHandler) GetDataset(c *fiber.Ctx) error {
+	dataset, apperr := h.datasetUsecase.GetDataset(c.Params("document_id"))
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, dataset)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetDatasetChart godoc
+// @Summary Get a dataset as chart data, one value per label and series
+// @Tags Datasets
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param group_by query string false "Field giving the labels, label or a dimension name" default(label)
+// @Param aggregate query string false "Aggregate of the points sharing a label" Enums(sum, avg, min, max, count) default(sum)
+// @Param series query []string false "Only these series" collectionFormat(multi)
+// @Param filter query []string false "Only points with this value, as name:value" collectionFormat(multi)
+// @Success 200 {object} response.Response{data=dtos.DatasetChartDTO}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /datasets/{document_id}/chart [get]
+func (h *DatasetHandler) GetDatasetChart(c *fiber.Ctx) error {
+	query, errs := parseDatasetQuery(c)
+	if len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	chart, apperr := h.datasetUsecase.GetDatasetChart(c.Params("document_id"), query)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, chart)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// AggregateDataset godoc
+// @Summary Group the points of a dataset by label or dimensions
+// @Description Without group_by every series is reduced to a single group.
+// @Tags Datasets
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param group_by query string false "Comma-separated fields, label or dimension names"
+// @Param aggregate query string false "Aggregate of each group" Enums(sum, avg, min, max, count) default(sum)
+// @Param series query []string false "Only these series" collectionFormat(multi)
+// @Param filter query []string false "Only points with this value, as name:value" collectionFormat(multi)
+// @Success 200 {object} response.Response{data=dtos.DatasetAggregateDTO}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /datasets/{document_id}/aggregate [get]
+func (h *DatasetHandler) AggregateDataset(c *fiber.Ctx) error {
+	query, errs := parseDatasetQuery(c)
+	if len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	aggregate, apperr := h.datasetUsecase.AggregateDataset(c.Params("document_id"), query)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, aggregate)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+func parseDatasetQuery(c *fiber.Ctx) (*dtos.DatasetQueryDTO, []string) {
+	query := dtos.DatasetQueryDTO{
+		GroupBy:   make([]string, 0),
+		Aggregate: strings.ToLower(c.Query("aggregate", constant.DEFAULT_AGGREGATE)),
+		Series:    make([]string, 0),
+		Filters:   make(map[string]string),
+	}
+
+	var errors []string
+
+	for _, name := range strings.Split(c.Query("group_by"), ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			query.GroupBy = append(query.GroupBy, name)
+		}
+	}
+
+	if !utils.ValidateAggregate(query.Aggregate) || query.Aggregate == "" {
+		errors = append(errors, constant.ErrInvalidAggregate)
+	}
+
+	// series names and filter values may hold commas, so both are repeated instead
+	for _, series := range c.Context().QueryArgs().PeekMulti("series") {
+		query.Series = append(query.Series, strings.TrimSpace(string(series)))
+	}
+
+	for _, filter := range c.Context().QueryArgs().PeekMulti("filter") {
+		name, value, ok := strings.Cut(string(filter), ":")
+		if !ok {
+			errors = append(errors, constant.ErrInvalidDatasetFilter)
+			break
+		}
+		query.Filters[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+
+	return &query, errors
+}
+
+// UpdateDataset godoc
+// @Summary Create or replace the dataset of a STATISTIC document
+// @Tags Datasets
+// @Accept json
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param dataset body dtos.UpdateDatasetDTO true "Dataset data"
+// @Success 200 {object} response.Response{data=dtos.DatasetDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /datasets/{document_id} [put]
+// @Security BearerAuth
+func (h *DatasetHandler) UpdateDataset(c *fiber.Ctx) error {
+	var updateDatasetDTO dtos.UpdateDatasetDTO
+	if err := c.BodyParser(&updateDatasetDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(updateDatasetDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	dataset, apperr := h.datasetUsecase.UpdateDataset(user, c.Params("document_id"), &updateDatasetDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, dataset)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// ImportDataset godoc
+// @Summary Replace the dataset of a STATISTIC document from a CSV or XLSX file
+// @Description One point per row. The first row names the columns: value is required, series, label and unit are optional and every other column is a dimension.
+// @Tags Datasets
+// @Accept multipart/form-data
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param file formData file true "CSV or XLSX file"
+// @Param series formData string false "Series name when the file has no series column" default(value)
+// @Param unit formData string false "Unit when the file has no unit column"
+// @Success 200 {object} response.Response{data=dtos.DatasetDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /datasets/{document_id}/import [post]
+// @Security BearerAuth
+func (h *DatasetHandler) ImportDataset(c *fiber.Ctx) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	importDatasetDTO := dtos.ImportDatasetDTO{
+		Series: c.FormValue("series"),
+		Unit:   c.FormValue("unit"),
+		File:   file,
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	dataset, apperr := h.datasetUsecase.ImportDataset(user, c.Params("document_id"), &importDatasetDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, dataset)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// DeleteDataset godoc
+// @Summary Delete the dataset of a document, the document itself is kept
+// @Tags Datasets
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /datasets/{document_id} [delete]
+// @Security BearerAuth
+func (h *DatasetHandler) DeleteDataset(c *fiber.Ctx) error {
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.datasetUsecase.DeleteDataset(user, c.Params("document_id")); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,29 +31,93 @@ func NewDocumentHandler(documentUsecase usecases.DocumentUsecase, validator vali
 // @Summary Get all documents
 // @Tags Documents
 // @Produce json
+// @Param document_type query string false "Document type ID from /document-types"
+// @Param q query string false "Search title and content, results are ranked by relevance"
+// @Param tags query string false "Comma separated tag names"
+// @Param tag_mode query string false "Match any or all of the tags" Enums(any, all) default(any)
+// @Param lang query string false "Preferred locale, overrides Accept-Language" Enums(th, en)
+// @Param locale query string false "Only documents readable in this locale" Enums(th, en)
 // @Success 200 {object} response.Response{data=[]dtos.DocumentDTO}
 // @Failure 500 {object} response.Response
 // @Router /documents [get]
 func (h *DocumentHandler) GetAllDocuments(c *fiber.Ctx) error {
+	getallDocumentsDTO, errors := parseGetAllDocuments(c)
+	if len(errors) != 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errors, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	paginationResp, err := h.documentUsecase.GetAllDocuments(getallDocumentsDTO)
+	if err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, err.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, paginationResp)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetArchivedDocuments godoc
+// @Summary Get archived documents, those past their expiry
+// @Tags Documents
+// @Produce json
+// @Param document_type query string false "Document type ID from /document-types"
+// @Param q query string false "Search title and content, results are ranked by relevance"
+// @Param tags query string false "Comma separated tag names"
+// @Param tag_mode query string false "Match any or all of the tags" Enums(any, all) default(any)
+// @Param lang query string false "Preferred locale, overrides Accept-Language" Enums(th, en)
+// @Param locale query string false "Only documents readable in this locale" Enums(th, en)
+// @Success 200 {object} response.Response{data=[]dtos.DocumentDTO}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/archive [get]
+func (h *DocumentHandler) GetArchivedDocuments(c *fiber.Ctx) error {
+	getallDocumentsDTO, errors := parseGetAllDocuments(c)
+	if len(errors) != 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errors, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+	getallDocumentsDTO.Archived = true
+
+	paginationResp, err := h.documentUsecase.GetAllDocuments(getallDocumentsDTO)
+	if err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, err.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, paginationResp)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// parseGetAllDocuments reads the filters shared by the public listings
+func parseGetAllDocuments(c *fiber.Ctx) (*dtos.GetAllDocumentsDTO, []string) {
 	// validate parameter
 	getallDocumentsDTO := dtos.GetAllDocumentsDTO{
 		Page:         c.QueryInt("page", 1),
 		PageSize:     c.QueryInt("page_size", 10),
-		Title:        c.Query("title"),
+		Query:        c.Query("q", c.Query("title")),
+		Tags:         utils.ParseTagNames(c.Query("tags")),
+		TagMode:      strings.ToLower(c.Query("tag_mode", constant.TAG_MODE_ANY)),
+		Lang:         utils.ResolveLocale(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage)),
+		Locale:       strings.ToLower(c.Query("locale")),
 		Organization: c.Query("organization"),
 		DocumentType: c.Query("document_type"),
 	}
 
 	var errors []string
 
-	if !utils.ValidateDocType(getallDocumentsDTO.DocumentType) {
-		errors = append(errors, constant.ErrInvalidDocType)
-	}
-
 	if !utils.ValidateOrg(getallDocumentsDTO.Organization) {
 		errors = append(errors, constant.ErrInvalidOrg)
 	}
 
+	if !utils.ValidateTagMode(getallDocumentsDTO.TagMode) {
+		errors = append(errors, constant.ErrInvalidTagMode)
+	}
+
+	if !utils.ValidateLocale(getallDocumentsDTO.Locale) {
+		errors = append(errors, constant.ErrInvalidLocale)
+	}
+
 	if ps := getallDocumentsDTO.PageSize; ps > constant.MAX_PAGE_SIZE || ps < 0 {
 		errors = append(errors, constant.ErrInvalidPageSize)
 	}
@@ -62,22 +128,10 @@ func (h *DocumentHandler) GetAllDocuments(c *fiber.Ctx) error {
 		errors = append(errors, constant.ErrInvalidTimeFormat)
 	}
 
-	if len(errors) != 0 {
-		resp := response.NewResponseFactory(response.ERROR, strings.Join(errors, ", "))
-		return resp.SendResponse(c, fiber.StatusBadRequest)
-	}
-
 	getallDocumentsDTO.StartTime = startTime
 	getallDocumentsDTO.EndTime = endTime
 
-	paginationResp, err := h.documentUsecase.GetAllDocuments(&getallDocumentsDTO)
-	if err != nil {
-		resp := response.NewResponseFactory(response.ERROR, err.Error())
-		return resp.SendResponse(c, err.HttpCode)
-	}
-
-	resp := response.NewResponseFactory(response.SUCCESS, paginationResp)
-	return resp.SendResponse(c, fiber.StatusOK)
+	return &getallDocumentsDTO, errors
 }
 
 // GetDocumentByID godoc
@@ -85,12 +139,67 @@ func (h *DocumentHandler) GetAllDocuments(c *fiber.Ctx) error {
 // @Tags Documents
 // @Produce json
 // @Param document_id path string true "Document ID"
+// @Param lang query string false "Preferred locale, overrides Accept-Language" Enums(th, en)
 // @Success 200 {object} response.Response{data=dtos.DocumentDTO}
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /documents/{document_id} [get]
 func (h *DocumentHandler) GetDocumentByID(c *fiber.Ctx) error {
-	return nil
+	lang := utils.ResolveLocale(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage))
+	document, apperr := h.documentUsecase.GetDocumentByID(c.Params("document_id"), lang)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	c.Set(fiber.HeaderContentLanguage, document.Locale)
+	c.Vary(fiber.HeaderAcceptLanguage)
+
+	resp := response.NewResponseFactory(response.SUCCESS, document)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetDocumentBySlug godoc
+// @Summary Get document by its permalink
+// @Description A former slug answers with a permanent redirect to the current one.
+// @Tags Documents
+// @Produce json
+// @Param slug path string true "Document slug"
+// @Param lang query string false "Preferred locale, overrides Accept-Language" Enums(th, en)
+// @Success 200 {object} response.Response{data=dtos.DocumentDTO}
+// @Success 301 "Moved Permanently"
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/slug/{slug} [get]
+func (h *DocumentHandler) GetDocumentBySlug(c *fiber.Ctx) error {
+	// Thai slugs arrive percent-encoded
+	slug, err := url.PathUnescape(c.Params("slug"))
+	if err != nil {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidSlug)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	lang := utils.ResolveLocale(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage))
+	document, apperr := h.documentUsecase.GetDocumentBySlug(slug, lang)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	if document.Slug != slug {
+		location := strings.TrimSuffix(c.Path(), c.Params("slug")) + url.PathEscape(document.Slug)
+		if query := string(c.Request().URI().QueryString()); query != "" {
+			location += "?" + query
+		}
+		return c.Redirect(location, fiber.StatusMovedPermanently)
+	}
+
+	c.Set(fiber.HeaderContentLanguage, document.Locale)
+	c.Vary(fiber.HeaderAcceptLanguage)
+
+	resp := response.NewResponseFactory(response.SUCCESS, document)
+	return resp.SendResponse(c, fiber.StatusOK)
 }
 
 // GetDocumentsByRole godoc
@@ -98,6 +207,12 @@ func (h *DocumentHandler) GetDocumentByID(c *fiber.Ctx) error {
 // @Tags Documents
 // @Produce json
 // @Param role_id path string true "User role"
+// @Param document_type query string false "Document type ID from /document-types"
+// @Param q query string false "Search title and content, results are ranked by relevance"
+// @Param tags query string false "Comma separated tag names"
+// @Param tag_mode query string false "Match any or all of the tags" Enums(any, all) default(any)
+// @Param lang query string false "Preferred locale, overrides Accept-Language" Enums(th, en)
+// @Param locale query string false "Only documents readable in this locale" Enums(th, en)
 // @Success 200 {object} response.Response{data=[]dtos.DocumentDTO}
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -107,7 +222,11 @@ func (h *DocumentHandler) GetDocumentsByRole(c *fiber.Ctx) error {
 	getallDocumentsByRoleDTO := dtos.GetAllDocumentsByRoleDTO{
 		Page:         c.QueryInt("page", 1),
 		PageSize:     c.QueryInt("page_size", 10),
-		Title:        c.Query("title"),
+		Query:        c.Query("q", c.Query("title")),
+		Tags:         utils.ParseTagNames(c.Query("tags")),
+		TagMode:      strings.ToLower(c.Query("tag_mode", constant.TAG_MODE_ANY)),
+		Lang:         utils.ResolveLocale(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage)),
+		Locale:       strings.ToLower(c.Query("locale")),
 		DocumentType: c.Query("document_type"),
 		Organization: c.Query("organization"),
 		Role:         c.Params("role"),
@@ -115,14 +234,18 @@ func (h *DocumentHandler) GetDocumentsByRole(c *fiber.Ctx) error {
 
 	var errors []string
 
-	if !utils.ValidateDocType(getallDocumentsByRoleDTO.DocumentType) {
-		errors = append(errors, constant.ErrInvalidDocType)
-	}
-
 	if !utils.ValidateOrg(getallDocumentsByRoleDTO.Organization) {
 		errors = append(errors, constant.ErrInvalidOrg)
 	}
 
+	if !utils.ValidateTagMode(getallDocumentsByRoleDTO.TagMode) {
+		errors = append(errors, constant.ErrInvalidTagMode)
+	}
+
+	if !utils.ValidateLocale(getallDocumentsByRoleDTO.Locale) {
+		errors = append(errors, constant.ErrInvalidLocale)
+	}
+
 	if role := getallDocumentsByRoleDTO.Role; role == "" || !utils.ValidateRole(role) {
 		errors = append(errors, constant.ErrInvalidRole)
 	}
@@ -192,6 +315,7 @@ func (h *DocumentHandler) CreateDocument(c *fiber.Ctx) error {
 
 // UpdateDocumentByID godoc
 // @Summary Update document by ID
+// @Description Only the author or a superadmin of the author's organization may edit, the author not while the document is pending review.
 // @Tags Documents
 // @Accept json
 // @Produce json
@@ -199,9 +323,11 @@ func (h *DocumentHandler) CreateDocument(c *fiber.Ctx) error {
 // @Param document body dtos.UpdateDocumentDTO true "Updated document data"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /documents/{document_id} [patch]
+// @Security BearerAuth
 func (h *DocumentHandler) UpdateDocumentByID(c *fiber.Ctx) error {
 	documentID := c.Params("document_id")
 	if documentID == "" {
@@ -216,7 +342,8 @@ func (h *DocumentHandler) UpdateDocumentByID(c *fiber.Ctx) error {
 		return resp.SendResponse(c, fiber.StatusBadRequest)
 	}
 
-	apperr := h.documentUsecase.UpdateDocumentByID(documentID, updateDocumentDTO)
+	user := c.Locals("user").(*dtos.UserDTO)
+	apperr := h.documentUsecase.UpdateDocumentByID(user, documentID, &updateDocumentDTO)
 	if apperr != nil {
 		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
 		return resp.SendResponse(c, apperr.HttpCode)
@@ -252,3 +379,792 @@ func (h *DocumentHandler) DeleteDocumentByID(c *fiber.Ctx) error {
 	resp := response.NewResponseFactory(response.SUCCESS, "Document deleted successfully")
 	return resp.SendResponse(c, fiber.StatusOK)
 }
+
+// UpdateDocumentSlug godoc
+// @Summary Change the permalink of a document
+// @Description The previous slug keeps redirecting to the document.
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param slug body dtos.UpdateDocumentSlugDTO true "New slug"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/slug [put]
+// @Security BearerAuth
+func (h *DocumentHandler) UpdateDocumentSlug(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+
+	var updateSlugDTO dtos.UpdateDocumentSlugDTO
+	if err := c.BodyParser(&updateSlugDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(updateSlugDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.UpdateDocumentSlug(user, documentID, &updateSlugDTO); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document slug updated successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetDocumentTranslations godoc
+// @Summary Get every translation of a document
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response{data=[]dtos.DocumentTranslationDTO}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/translations [get]
+// @Security BearerAuth
+func (h *DocumentHandler) GetDocumentTranslations(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	translations, apperr := h.documentUsecase.GetDocumentTranslations(user, documentID)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, translations)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// UpdateDocumentTranslation godoc
+// @Summary Create or replace the translation of a document in a locale
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param locale path string true "Locale" Enums(th, en)
+// @Param translation body dtos.UpdateDocumentTranslationDTO true "Translated title, content and excerpt"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/translations/{locale} [put]
+// @Security BearerAuth
+func (h *DocumentHandler) UpdateDocumentTranslation(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	locale := c.Params("locale")
+
+	var translationDTO dtos.UpdateDocumentTranslationDTO
+	if err := c.BodyParser(&translationDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(translationDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.UpdateDocumentTranslation(user, documentID, locale, &translationDTO); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document translation updated successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// UpdateDocumentExpiry godoc
+// @Summary Set or remove the expiry of a published or archived document
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param expiry body dtos.UpdateDocumentExpiryDTO true "New expiry, null to never expire"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/expiry [put]
+// @Security BearerAuth
+func (h *DocumentHandler) UpdateDocumentExpiry(c *fiber.Ctx) error {
+	var updateExpiryDTO dtos.UpdateDocumentExpiryDTO
+	if err := c.BodyParser(&updateExpiryDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.UpdateDocumentExpiry(user, c.Params("document_id"), &updateExpiryDTO); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document expiry updated successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// BulkUpdateDocuments godoc
+// @Summary Apply one operation to many documents
+// @Description Every document is authorized on its own and gets its own result. Publish approves a pending document and archive takes a published one.
+// @Description In atomic mode one failure leaves every document untouched, in best_effort mode the others are still applied.
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Param bulk body dtos.BulkDocumentsDTO true "Documents and operation"
+// @Success 200 {object} response.Response{data=dtos.BulkDocumentsResultDTO}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/bulk [post]
+// @Security BearerAuth
+func (h *DocumentHandler) BulkUpdateDocuments(c *fiber.Ctx) error {
+	var bulkDTO dtos.BulkDocumentsDTO
+	if err := c.BodyParser(&bulkDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(bulkDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	result, apperr := h.documentUsecase.BulkUpdateDocuments(user, &bulkDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, result)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// DeleteDocumentTranslation godoc
+// @Summary Delete the translation of a document in a locale
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param locale path string true "Locale" Enums(th, en)
+// @Success 204 "No Content"
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/translations/{locale} [delete]
+// @Security BearerAuth
+func (h *DocumentHandler) DeleteDocumentTranslation(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	locale := c.Params("locale")
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.DeleteDocumentTranslation(user, documentID, locale); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// UpdateDocumentTags godoc
+// @Summary Replace the tags of a document
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param tags body dtos.UpdateDocumentTagsDTO true "Tag IDs"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/tags [put]
+// @Security BearerAuth
+func (h *DocumentHandler) UpdateDocumentTags(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+
+	var updateTagsDTO dtos.UpdateDocumentTagsDTO
+	if err := c.BodyParser(&updateTagsDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.UpdateDocumentTags(user, documentID, &updateTagsDTO); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document tags updated successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetFeaturedDocuments godoc
+// @Summary Get featured documents, global slots first then those of the organization
+// @Tags Documents
+// @Produce json
+// @Param organization query string false "Organization" Enums(sgcu, sccu)
+// @Param lang query string false "Preferred locale, overrides Accept-Language" Enums(th, en)
+// @Success 200 {object} response.Response{data=[]dtos.FeaturedDocumentDTO}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/featured [get]
+func (h *DocumentHandler) GetFeaturedDocuments(c *fiber.Ctx) error {
+	organization := c.Query("organization")
+	if !utils.ValidateOrg(organization) {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidOrg)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	lang := utils.ResolveLocale(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage))
+	documents, apperr := h.documentUsecase.GetFeaturedDocuments(organization, lang)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	c.Vary(fiber.HeaderAcceptLanguage)
+
+	resp := response.NewResponseFactory(response.SUCCESS, documents)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetFeaturedSlots godoc
+// @Summary Get every featured slot of a scope, expired ones included
+// @Tags Documents
+// @Produce json
+// @Param scope path string true "Scope" Enums(global, sgcu, sccu)
+// @Success 200 {object} response.Response{data=[]dtos.FeaturedDocumentDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/featured/{scope} [get]
+// @Security BearerAuth
+func (h *DocumentHandler) GetFeaturedSlots(c *fiber.Ctx) error {
+	user := c.Locals("user").(*dtos.UserDTO)
+	slots, apperr := h.documentUsecase.GetFeaturedSlots(user, c.Params("scope"))
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, slots)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// UpdateFeaturedDocuments godoc
+// @Summary Replace the featured slots of a scope
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Param scope path string true "Scope" Enums(global, sgcu, sccu)
+// @Param slots body dtos.UpdateFeaturedDocumentsDTO true "Featured documents in display order"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/featured/{scope} [put]
+// @Security BearerAuth
+func (h *DocumentHandler) UpdateFeaturedDocuments(c *fiber.Ctx) error {
+	var updateFeaturedDTO dtos.UpdateFeaturedDocumentsDTO
+	if err := c.BodyParser(&updateFeaturedDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(updateFeaturedDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.UpdateFeaturedDocuments(user, c.Params("scope"), &updateFeaturedDTO); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Featured documents updated successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// DeleteFeaturedDocument godoc
+// @Summary Unpin a document from a scope
+// @Tags Documents
+// @Produce json
+// @Param scope path string true "Scope" Enums(global, sgcu, sccu)
+// @Param document_id path string true "Document ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/featured/{scope}/{document_id} [delete]
+// @Security BearerAuth
+func (h *DocumentHandler) DeleteFeaturedDocument(c *fiber.Ctx) error {
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.DeleteFeaturedDocument(user, c.Params("scope"), c.Params("document_id")); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetDocumentLinks godoc
+// @Summary Get the links and backlinks of a document, including unpublished documents
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response{data=dtos.DocumentLinksDTO}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/links [get]
+// @Security BearerAuth
+func (h *DocumentHandler) GetDocumentLinks(c *fiber.Ctx) error {
+	user := c.Locals("user").(*dtos.UserDTO)
+	links, apperr := h.documentUsecase.GetDocumentLinks(user, c.Params("document_id"))
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, links)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// UpdateDocumentLinks godoc
+// @Summary Replace the links going out of a document
+// @Description Each linked document shows the link as a backlink: amends as amended_by, supersedes as superseded_by, follow_up as followed_up_by.
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param links body dtos.UpdateDocumentLinksDTO true "Document links"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/links [put]
+// @Security BearerAuth
+func (h *DocumentHandler) UpdateDocumentLinks(c *fiber.Ctx) error {
+	var updateLinksDTO dtos.UpdateDocumentLinksDTO
+	if err := c.BodyParser(&updateLinksDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(updateLinksDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.UpdateDocumentLinks(user, c.Params("document_id"), &updateLinksDTO); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document links updated successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetPendingDocuments godoc
+// @Summary Get documents waiting for review in the reviewer's organization
+// @Tags Documents
+// @Produce json
+// @Param page query int false "Page"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.Response{data=dtos.PaginationResponse}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/reviews/pending [get]
+// @Security BearerAuth
+func (h *DocumentHandler) GetPendingDocuments(c *fiber.Ctx) error {
+	getPendingDocumentsDTO := dtos.GetPendingDocumentsDTO{
+		Page:     c.QueryInt("page", 1),
+		PageSize: c.QueryInt("page_size", 10),
+	}
+
+	if ps := getPendingDocumentsDTO.PageSize; ps > constant.MAX_PAGE_SIZE || ps <= 0 {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidPageSize)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	paginationResp, err := h.documentUsecase.GetPendingDocuments(user, &getPendingDocumentsDTO)
+	if err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, err.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, paginationResp)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetDocumentReviews godoc
+// @Summary Get review history of a document
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response{data=[]dtos.DocumentReviewDTO}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/reviews [get]
+// @Security BearerAuth
+func (h *DocumentHandler) GetDocumentReviews(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	user := c.Locals("user").(*dtos.UserDTO)
+
+	reviews, apperr := h.documentUsecase.GetDocumentReviews(user, documentID)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, reviews)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// SubmitDocument godoc
+// @Summary Submit a document for review
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/submit [post]
+// @Security BearerAuth
+func (h *DocumentHandler) SubmitDocument(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	user := c.Locals("user").(*dtos.UserDTO)
+
+	if apperr := h.documentUsecase.SubmitDocument(user, documentID); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document submitted for review")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// ApproveDocument godoc
+// @Summary Approve a pending document and publish it
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/approve [post]
+// @Security BearerAuth
+func (h *DocumentHandler) ApproveDocument(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	user := c.Locals("user").(*dtos.UserDTO)
+
+	if apperr := h.documentUsecase.ApproveDocument(user, documentID); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document approved successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// RejectDocument godoc
+// @Summary Reject a pending document with a comment
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param review body dtos.ReviewDocumentDTO true "Reason for rejection"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/reject [post]
+// @Security BearerAuth
+func (h *DocumentHandler) RejectDocument(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+
+	var reviewDocumentDTO dtos.ReviewDocumentDTO
+	if err := c.BodyParser(&reviewDocumentDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(reviewDocumentDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.RejectDocument(user, documentID, &reviewDocumentDTO); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document rejected successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// RequestDocumentChanges godoc
+// @Summary Send a pending document back to its author for changes
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param review body dtos.ReviewDocumentDTO true "Requested changes"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/request-changes [post]
+// @Security BearerAuth
+func (h *DocumentHandler) RequestDocumentChanges(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+
+	var reviewDocumentDTO dtos.ReviewDocumentDTO
+	if err := c.BodyParser(&reviewDocumentDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(reviewDocumentDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.RequestDocumentChanges(user, documentID, &reviewDocumentDTO); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Changes requested successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetDocumentRevisions godoc
+// @Summary Get revision history of a document
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response{data=[]dtos.DocumentRevisionDTO}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/revisions [get]
+// @Security BearerAuth
+func (h *DocumentHandler) GetDocumentRevisions(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	user := c.Locals("user").(*dtos.UserDTO)
+
+	revisions, apperr := h.documentUsecase.GetDocumentRevisions(user, documentID)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, revisions)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetDocumentRevision godoc
+// @Summary Get a single revision of a document
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} response.Response{data=dtos.DocumentRevisionDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/revisions/{rev} [get]
+// @Security BearerAuth
+func (h *DocumentHandler) GetDocumentRevision(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	revision, err := strconv.Atoi(c.Params("rev"))
+	if err != nil || revision <= 0 {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidRevision)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	revisionDTO, apperr := h.documentUsecase.GetDocumentRevision(user, documentID, revision)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, revisionDTO)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// DiffDocumentRevisions godoc
+// @Summary Diff two revisions of a document
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param from query int true "Base revision"
+// @Param to query int true "Target revision"
+// @Success 200 {object} response.Response{data=dtos.DocumentRevisionDiffDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/revisions/diff [get]
+// @Security BearerAuth
+func (h *DocumentHandler) DiffDocumentRevisions(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	from := c.QueryInt("from", 0)
+	to := c.QueryInt("to", 0)
+	if from <= 0 || to <= 0 {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidRevision)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	diff, apperr := h.documentUsecase.DiffDocumentRevisions(user, documentID, from, to)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, diff)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// RestoreDocumentRevision godoc
+// @Summary Restore a document to an earlier revision
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/revisions/{rev}/restore [post]
+// @Security BearerAuth
+func (h *DocumentHandler) RestoreDocumentRevision(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	revision, err := strconv.Atoi(c.Params("rev"))
+	if err != nil || revision <= 0 {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidRevision)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	if apperr := h.documentUsecase.RestoreDocumentRevision(user, documentID, revision); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document restored successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetTrashedDocuments godoc
+// @Summary Get deleted documents of the superadmin's organization
+// @Tags Documents
+// @Produce json
+// @Param page query int false "Page"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.Response{data=dtos.PaginationResponse}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/trash [get]
+// @Security BearerAuth
+func (h *DocumentHandler) GetTrashedDocuments(c *fiber.Ctx) error {
+	getTrashedDocumentsDTO := dtos.GetTrashedDocumentsDTO{
+		Page:     c.QueryInt("page", 1),
+		PageSize: c.QueryInt("page_size", 10),
+	}
+
+	if ps := getTrashedDocumentsDTO.PageSize; ps > constant.MAX_PAGE_SIZE || ps <= 0 {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidPageSize)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	user := c.Locals("user").(*dtos.UserDTO)
+	paginationResp, err := h.documentUsecase.GetTrashedDocuments(user, &getTrashedDocumentsDTO)
+	if err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, err.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, paginationResp)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// RestoreDocumentByID godoc
+// @Summary Restore a deleted document and the attachments deleted with it
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/restore [post]
+// @Security BearerAuth
+func (h *DocumentHandler) RestoreDocumentByID(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	user := c.Locals("user").(*dtos.UserDTO)
+
+	if apperr := h.documentUsecase.RestoreDocumentByID(user, documentID); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document restored successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// PurgeDocumentByID godoc
+// @Summary Permanently delete a document in trash with its attachments
+// @Tags Documents
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /documents/{document_id}/purge [delete]
+// @Security BearerAuth
+func (h *DocumentHandler) PurgeDocumentByID(c *fiber.Ctx) error {
+	documentID := c.Params("document_id")
+	user := c.Locals("user").(*dtos.UserDTO)
+
+	if apperr := h.documentUsecase.PurgeDocumentByID(user, documentID); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document purged successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
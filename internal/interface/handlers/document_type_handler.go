@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/usecases"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/response"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/validator"
+)
+
+type DocumentTypeHandler struct {
+	documentTypeUsecase usecases.DocumentTypeUsecase
+	validator           validator.DTOValidator
+}
+
+func NewDocumentTypeHandler(documentTypeUsecase usecases.DocumentTypeUsecase, validator validator.DTOValidator) *DocumentTypeHandler {
+	return &DocumentTypeHandler{
+		documentTypeUsecase: documentTypeUsecase,
+		validator:           validator,
+	}
+}
+
+// GetAllDocumentTypes godoc
+// @Summary Get all document types in display order
+// @Tags DocumentTypes
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dtos.DocumentTypeDTO}
+// @Failure 500 {object} response.Response
+// @Router /document-types [get]
+func (h *DocumentTypeHandler) GetAllDocumentTypes(c *fiber.Ctx) error {
+	documentTypes, apperr := h.documentTypeUsecase.GetAllDocumentTypes()
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, documentTypes)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetDocumentTypeByID godoc
+// @Summary Get document type by ID
+// @Tags DocumentTypes
+// @Produce json
+// @Param type_id path string true "Document type ID"
+// @Success 200 {object} response.Response{data=dtos.DocumentTypeDTO}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /document-types/{type_id} [get]
+func (h *DocumentTypeHandler) GetDocumentTypeByID(c *fiber.Ctx) error {
+	documentType, apperr := h.documentTypeUsecase.GetDocumentTypeByID(c.Params("type_id"))
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, documentType)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// CreateDocumentType godoc
+// @Summary Create a new document type
+// @Tags DocumentTypes
+// @Accept json
+// @Produce json
+// @Param document_type body dtos.CreateDocumentTypeDTO true "Document type data"
+// @Success 201 {object} response.Response{data=dtos.DocumentTypeDTO}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /document-types [post]
+// @Security BearerAuth
+func (h *DocumentTypeHandler) CreateDocumentType(c *fiber.Ctx) error {
+	var createDocumentTypeDTO dtos.CreateDocumentTypeDTO
+	if err := c.BodyParser(&createDocumentTypeDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(createDocumentTypeDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	documentType, apperr := h.documentTypeUsecase.CreateDocumentType(&createDocumentTypeDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, documentType)
+	return resp.SendResponse(c, fiber.StatusCreated)
+}
+
+// UpdateDocumentTypeByID godoc
+// @Summary Update document type by ID
+// @Tags DocumentTypes
+// @Accept json
+// @Produce json
+// @Param type_id path string true "Document type ID"
+// @Param document_type body dtos.UpdateDocumentTypeDTO true "Updated document type data"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /document-types/{type_id} [patch]
+// @Security BearerAuth
+func (h *DocumentTypeHandler) UpdateDocumentTypeByID(c *fiber.Ctx) error {
+	var updateDocumentTypeDTO dtos.UpdateDocumentTypeDTO
+	if err := c.BodyParser(&updateDocumentTypeDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(updateDocumentTypeDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if apperr := h.documentTypeUsecase.UpdateDocumentTypeByID(c.Params("type_id"), &updateDocumentTypeDTO); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, "Document type updated successfully")
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// DeleteDocumentTypeByID godoc
+// @Summary Delete an unused document type
+// @Tags DocumentTypes
+// @Produce json
+// @Param type_id path string true "Document type ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /document-types/{type_id} [delete]
+// @Security BearerAuth
+func (h *DocumentTypeHandler) DeleteDocumentTypeByID(c *fiber.Ctx) error {
+	if apperr := h.documentTypeUsecase.DeleteDocumentTypeByID(c.Params("type_id")); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
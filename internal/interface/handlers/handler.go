@@ -6,4 +6,8 @@ type Handler interface {
 	User() *UserHandler
 	Attachment() *AttachmentHandler
 	Document() *DocumentHandler
+	Tag() *TagHandler
+	DocumentType() *DocumentTypeHandler
+	Budget() *BudgetHandler
+	Dataset() *DatasetHandler
 }
@@ -6,20 +6,28 @@ import (
 )
 
 type handler struct {
-	MiddlewareHandler *MiddlewareHandler
-	AuthHandler       *AuthHandler
-	UserHandler       *UserHandler
-	AttachmentHandler *AttachmentHandler
-	DocumentHandler   *DocumentHandler
+	MiddlewareHandler   *MiddlewareHandler
+	AuthHandler         *AuthHandler
+	UserHandler         *UserHandler
+	AttachmentHandler   *AttachmentHandler
+	DocumentHandler     *DocumentHandler
+	TagHandler          *TagHandler
+	DocumentTypeHandler *DocumentTypeHandler
+	BudgetHandler       *BudgetHandler
+	DatasetHandler      *DatasetHandler
 }
 
 func NewHandler(usecases usecases.Usecase, validator validator.DTOValidator) Handler {
 	return &handler{
-		MiddlewareHandler: NewMiddlewareHandler(usecases.Middleware()),
-		AuthHandler:       NewAuthHandler(usecases.Auth()),
-		UserHandler:       NewUserHandler(usecases.User(), validator),
-		AttachmentHandler: NewAttachmentHandler(usecases.Attachment()),
-		DocumentHandler:   NewDocumentHandler(usecases.Document(), validator),
+		MiddlewareHandler:   NewMiddlewareHandler(usecases.Middleware()),
+		AuthHandler:         NewAuthHandler(usecases.Auth()),
+		UserHandler:         NewUserHandler(usecases.User(), validator),
+		AttachmentHandler:   NewAttachmentHandler(usecases.Attachment(), validator),
+		DocumentHandler:     NewDocumentHandler(usecases.Document(), validator),
+		TagHandler:          NewTagHandler(usecases.Tag(), validator),
+		DocumentTypeHandler: NewDocumentTypeHandler(usecases.DocumentType(), validator),
+		BudgetHandler:       NewBudgetHandler(usecases.Budget(), validator),
+		DatasetHandler:      NewDatasetHandler(usecases.Dataset(), validator),
 	}
 }
 
@@ -42,3 +50,19 @@ func (h *handler) Attachment() *AttachmentHandler {
 func (h *handler) Document() *DocumentHandler {
 	return h.DocumentHandler
 }
+
+func (h *handler) Tag() *TagHandler {
+	return h.TagHandler
+}
+
+func (h *handler) DocumentType() *DocumentTypeHandler {
+	return h.DocumentTypeHandler
+}
+
+func (h *handler) Budget() *BudgetHandler {
+	return h.BudgetHandler
+}
+
+func (h *handler) Dataset() *DatasetHandler {
+	return h.DatasetHandler
+}
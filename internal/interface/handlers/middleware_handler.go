@@ -59,6 +59,27 @@ func (h *MiddlewareHandler) IsLogin(c *fiber.Ctx) error {
 	return c.Next()
 }
 
+// BodyLimit turns away requests whose Content-Length is above limit, and chunked requests that have none.
+// The server reads large and chunked bodies as a stream without a cap, so every route that reads a body needs one.
+func (h *MiddlewareHandler) BodyLimit(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// -1 is a chunked body, -2 a request without one
+		length := c.Request().Header.ContentLength()
+		if length != -1 && length <= limit {
+			return c.Next()
+		}
+
+		// the body is left unread, so the connection cannot take another request
+		c.Context().SetConnectionClose()
+		if length == -1 {
+			resp := response.NewResponseFactory(response.ERROR, constant.ErrRequestBodyLengthRequired)
+			return resp.SendResponse(c, fiber.StatusLengthRequired)
+		}
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrRequestBodyTooLarge)
+		return resp.SendResponse(c, fiber.StatusRequestEntityTooLarge)
+	}
+}
+
 func (h *MiddlewareHandler) SuperAdmin(c *fiber.Ctx) error {
 	userDTO := c.Locals("user").(*dtos.UserDTO)
 	if userDTO.Role != constant.SGCU_SUPERADMIN && userDTO.Role != constant.SCCU_SUPERADMIN {
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/usecases"
+	"github.com/isd-sgcu/sucu-backend-2024/internal/interface/dtos"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/response"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/validator"
+	"github.com/isd-sgcu/sucu-backend-2024/utils"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+type TagHandler struct {
+	tagUsecase usecases.TagUsecase
+	validator  validator.DTOValidator
+}
+
+func NewTagHandler(tagUsecase usecases.TagUsecase, validator validator.DTOValidator) *TagHandler {
+	return &TagHandler{
+		tagUsecase: tagUsecase,
+		validator:  validator,
+	}
+}
+
+// GetAllTags godoc
+// @Summary Get all tags
+// @Tags Tags
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dtos.TagDTO}
+// @Failure 500 {object} response.Response
+// @Router /tags [get]
+func (h *TagHandler) GetAllTags(c *fiber.Ctx) error {
+	tags, apperr := h.tagUsecase.GetAllTags()
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, tags)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// GetTagUsage godoc
+// @Summary Count published documents per tag and organization
+// @Tags Tags
+// @Produce json
+// @Param organization query string false "Organization" Enums(sgcu, sccu)
+// @Success 200 {object} response.Response{data=[]dtos.TagUsageDTO}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags/usage [get]
+func (h *TagHandler) GetTagUsage(c *fiber.Ctx) error {
+	organization := c.Query("organization")
+	if !utils.ValidateOrg(organization) {
+		resp := response.NewResponseFactory(response.ERROR, constant.ErrInvalidOrg)
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	usage, apperr := h.tagUsecase.GetTagUsage(organization)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, usage)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// CreateTag godoc
+// @Summary Create a new tag
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param tag body dtos.CreateTagDTO true "Tag data"
+// @Success 201 {object} response.Response{data=dtos.TagDTO}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags [post]
+// @Security BearerAuth
+func (h *TagHandler) CreateTag(c *fiber.Ctx) error {
+	var createTagDTO dtos.CreateTagDTO
+	if err := c.BodyParser(&createTagDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(createTagDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	tag, apperr := h.tagUsecase.CreateTag(&createTagDTO)
+	if apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, tag)
+	return resp.SendResponse(c, fiber.StatusCreated)
+}
+
+// UpdateTagByID godoc
+// @Summary Rename a tag
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param tag_id path string true "Tag ID"
+// @Param tag body dtos.UpdateTagDTO true "Updated tag data"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags/{tag_id} [put]
+// @Security BearerAuth
+func (h *TagHandler) UpdateTagByID(c *fiber.Ctx) error {
+	var updateTagDTO dtos.UpdateTagDTO
+	if err := c.BodyParser(&updateTagDTO); err != nil {
+		resp := response.NewResponseFactory(response.ERROR, err.Error())
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	if errs := h.validator.Validate(updateTagDTO); len(errs) > 0 {
+		resp := response.NewResponseFactory(response.ERROR, strings.Join(errs, ", "))
+		return resp.SendResponse(c, fiber.StatusBadRequest)
+	}
+
+	tagID := c.Params("tag_id")
+	if apperr := h.tagUsecase.UpdateTagByID(tagID, &updateTagDTO); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	resp := response.NewResponseFactory(response.SUCCESS, updateTagDTO)
+	return resp.SendResponse(c, fiber.StatusOK)
+}
+
+// DeleteTagByID godoc
+// @Summary Delete a tag and remove it from every document
+// @Tags Tags
+// @Produce json
+// @Param tag_id path string true "Tag ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags/{tag_id} [delete]
+// @Security BearerAuth
+func (h *TagHandler) DeleteTagByID(c *fiber.Ctx) error {
+	tagID := c.Params("tag_id")
+	if apperr := h.tagUsecase.DeleteTagByID(tagID); apperr != nil {
+		resp := response.NewResponseFactory(response.ERROR, apperr.Error())
+		return resp.SendResponse(c, apperr.HttpCode)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
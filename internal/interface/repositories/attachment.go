@@ -2,20 +2,69 @@ package repositories
 
 import (
 	"io"
+	"net/url"
+	"time"
 
 	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/s3client"
 )
 
 type AttachmentRepository interface {
 	// client side
-	FindAllAttachments() (*entities.Attachment, error)
+	FindAllAttachments(args *FindAllAttachmentsArgs) (*[]entities.Attachment, int64, error)
 
 	// back office
 	FindAttachmentByID(ID string) (*entities.Attachment, error)
 
 	InsertAttachments(attachments *[]entities.Attachment) error
-	UploadAttachmentToS3(bucketName string, fileReaders map[string]io.Reader) error
+	CommitAttachments(attachments *[]entities.Attachment) ([]string, error)
+	DeletePendingAttachments(IDs []string) error
+	UploadAttachmentToS3(bucketName, objectKey, contentType string, file io.Reader, public bool) error
+	AttachmentURL(bucketName, objectKey string) string
+	SignAttachmentURL(bucketName, objectKey string, expiry time.Duration) (string, error)
 
 	DeleteAttachmentByID(ID string) error
 	DeleteAttachmentFromS3(bucketName, objectKey string) error
+
+	// direct upload
+	InsertAttachmentUploads(uploads *[]entities.AttachmentUpload) error
+	FindAttachmentUploadByID(ID string) (*entities.AttachmentUpload, error)
+	CompleteAttachmentUpload(upload *entities.AttachmentUpload, attachment *entities.Attachment) (string, error)
+	FindExpiredAttachmentUploads(now time.Time) (*[]entities.AttachmentUpload, error)
+	DeleteAttachmentUploadByID(ID string) error
+	SignAttachmentUploadURL(bucketName, objectKey, contentType string, public bool, expiry time.Duration) (string, map[string]string, error)
+	StatAttachmentObject(bucketName, objectKey string) (*s3client.ObjectInfo, error)
+	ReadAttachmentHead(bucketName, objectKey string, length int64) ([]byte, error)
+	ReadAttachmentObject(bucketName, objectKey string) (io.ReadCloser, error)
+
+	// resumable upload
+	InsertTusUpload(upload *entities.TusUpload) error
+	FindTusUploadByID(ID string) (*entities.TusUpload, error)
+	AppendTusUpload(upload *entities.TusUpload, body io.Reader, expiresAt time.Time) error
+	OpenTusUpload(ID string) (io.ReadCloser, error)
+	FindExpiredTusUploads(now time.Time) (*[]entities.TusUpload, error)
+	DeleteTusUploadByID(ID string) error
+
+	// files served through the API by the local and memory storage drivers
+	OpenStoredFile(bucketName, objectKey string, query url.Values) (io.ReadCloser, *s3client.ObjectInfo, error)
+	ReceiveStoredFile(bucketName, objectKey, contentType string, query url.Values, body io.Reader) error
+
+	// trash
+	FindAllAttachmentsByDocumentID(documentID string) (*[]entities.Attachment, error)
+	FindDeletedAttachmentsBefore(before time.Time) (*[]entities.Attachment, error)
+	PurgeAttachmentByID(ID string) ([]string, error)
+
+	// image variants
+	FindPendingVariantAttachments(limit int) (*[]entities.Attachment, error)
+	SaveAttachmentVariants(ID string, variants *[]entities.AttachmentVariant) error
+	UpdateAttachmentVariantStatus(ID, status string) error
+
+	// deduplication
+	FindStorageSavings() (*StorageSavings, error)
+	FindMostSharedObjects(limit int) (*[]entities.StoredObject, error)
+
+	// reconciliation
+	ListAttachmentObjects(bucketName string, fn func(objectKey string, info *s3client.ObjectInfo) error) error
+	FindAllAttachmentRecords() (*[]entities.Attachment, error)
+	FindAllAttachmentUploadKeys() ([]string, error)
 }
@@ -2,34 +2,95 @@ package repositories
 
 import (
 	"io"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/s3client"
-	"github.com/isd-sgcu/sucu-backend-2024/utils"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/tusstore"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type attachmentRepository struct {
-	db *gorm.DB
-	s3 s3client.S3Client
+	db  *gorm.DB
+	s3  s3client.S3Client
+	tus tusstore.TusStore
 }
 
-func NewAttachmentRepository(db *gorm.DB, s3 s3client.S3Client) AttachmentRepository {
+func NewAttachmentRepository(db *gorm.DB, s3 s3client.S3Client, tus tusstore.TusStore) AttachmentRepository {
 	return &attachmentRepository{
-		db: db,
-		s3: s3,
+		db:  db,
+		s3:  s3,
+		tus: tus,
 	}
 }
 
+type FindAllAttachmentsArgs struct {
+	Offset       int
+	Limit        int
+	TypeID       string
+	DocumentID   string
+	Organization string
+	Role         string // role of the document's author
+	Visibility   string
+	PublicOnly   bool // only attachments of documents anyone may open
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
 // client side
-func (r *attachmentRepository) FindAllAttachments() (*entities.Attachment, error) {
-	return nil, nil
+
+// FindAllAttachments finds live attachments of live documents uploaded between the start and end time, newest first
+func (r *attachmentRepository) FindAllAttachments(args *FindAllAttachmentsArgs) (*[]entities.Attachment, int64, error) {
+	var attachments []entities.Attachment
+	var total int64
+
+	query := r.db.Model(&entities.Attachment{}).
+		Preload("Variants", orderVariants).
+		Joins("Document").
+		Joins("Document.Author").
+		Where(`"Document".id IS NOT NULL AND "Document".deleted_at IS NULL`).
+		Where("attachments.state = ?", constant.ATTACHMENT_COMMITTED).
+		Where("attachments.created_at BETWEEN ? AND ?", args.StartTime, args.EndTime)
+
+	if args.PublicOnly {
+		query = query.Where(`"Document".status IN ?`, []string{constant.PUBLISHED, constant.ARCHIVED})
+	}
+	if args.TypeID != "" {
+		query = query.Where("attachments.type_id = ?", strings.ToUpper(args.TypeID))
+	}
+	if args.DocumentID != "" {
+		query = query.Where("attachments.document_id = ?", args.DocumentID)
+	}
+	if args.Visibility != "" {
+		query = query.Where("attachments.visibility = ?", args.Visibility)
+	}
+	if args.Organization != "" {
+		query = query.Where(`"Document__Author".role_id LIKE ?`, strings.ToUpper(args.Organization)+"\\_%")
+	}
+	if args.Role != "" {
+		query = query.Where(`"Document__Author".role_id = ?`, strings.ToUpper(args.Role))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("attachments.created_at DESC, attachments.id").Offset(args.Offset).Limit(args.Limit).Find(&attachments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &attachments, total, nil
 }
 
 // back office
+// FindAttachmentByID finds a live, committed attachment with its document and the document's author
 func (r *attachmentRepository) FindAttachmentByID(ID string) (*entities.Attachment, error) {
 	var attachment entities.Attachment
-	if err := r.db.First(&attachment, "id = ?", ID).Error; err != nil {
+	if err := r.db.Preload("Document.Author").Preload("Variants", orderVariants).First(&attachment, "id = ? AND state = ?", ID, constant.ATTACHMENT_COMMITTED).Error; err != nil {
 		return nil, err
 	}
 	return &attachment, nil
@@ -43,18 +104,59 @@ func (r *attachmentRepository) InsertAttachments(attachments *[]entities.Attachm
 	return nil
 }
 
-func (r *attachmentRepository) UploadAttachmentToS3(bucketName string, fileReaders map[string]io.Reader) error {
-	for fileName, file := range fileReaders {
-		buffer, err := utils.ToBytesReader(file)
-		if err != nil {
-			return err
-		}
-		if err := r.s3.UploadFile(bucketName, fileName, buffer); err != nil {
-			return err
+// CommitAttachments marks pending attachments committed once all their files are in storage and their hashes are known.
+// An attachment whose content is stored already is pointed at that object, the keys of the copies it uploaded are returned to be deleted.
+func (r *attachmentRepository) CommitAttachments(attachments *[]entities.Attachment) ([]string, error) {
+	var duplicates []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		duplicates = nil
+		for i := range *attachments {
+			a := &(*attachments)[i]
+			duplicate, err := shareObject(tx, a)
+			if err != nil {
+				return err
+			}
+			if duplicate != "" {
+				duplicates = append(duplicates, duplicate)
+			}
+
+			result := tx.Model(&entities.Attachment{}).Where("id = ? AND state = ?", a.ID, constant.ATTACHMENT_PENDING).Updates(map[string]interface{}{
+				"state":      constant.ATTACHMENT_COMMITTED,
+				"object_key": a.ObjectKey,
+				"hash":       a.Hash,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+			a.State = constant.ATTACHMENT_COMMITTED
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return duplicates, nil
+}
 
-	return nil
+// DeletePendingAttachments removes the rows of attachments whose files never made it to storage
+func (r *attachmentRepository) DeletePendingAttachments(IDs []string) error {
+	return r.db.Unscoped().Where("id IN ? AND state = ?", IDs, constant.ATTACHMENT_PENDING).Delete(&entities.Attachment{}).Error
+}
+
+// UploadAttachmentToS3 streams the file to storage without reading it into memory first
+func (r *attachmentRepository) UploadAttachmentToS3(bucketName, objectKey, contentType string, file io.Reader, public bool) error {
+	return r.s3.UploadFile(bucketName, objectKey, contentType, file, public)
+}
+
+func (r *attachmentRepository) AttachmentURL(bucketName, objectKey string) string {
+	return r.s3.ObjectURL(bucketName, objectKey)
+}
+
+func (r *attachmentRepository) SignAttachmentURL(bucketName, objectKey string, expiry time.Duration) (string, error) {
+	return r.s3.PresignGetURL(bucketName, objectKey, expiry)
 }
 
 func (r *attachmentRepository) DeleteAttachmentByID(ID string) error {
@@ -65,5 +167,340 @@ func (r *attachmentRepository) DeleteAttachmentByID(ID string) error {
 }
 
 func (r *attachmentRepository) DeleteAttachmentFromS3(bucketName, objectKey string) error {
+	return r.s3.DeleteFile(bucketName, objectKey)
+}
+
+// direct upload
+func (r *attachmentRepository) InsertAttachmentUploads(uploads *[]entities.AttachmentUpload) error {
+	return r.db.Create(uploads).Error
+}
+
+// FindAttachmentUploadByID finds an upload with its document and the document's author
+func (r *attachmentRepository) FindAttachmentUploadByID(ID string) (*entities.AttachmentUpload, error) {
+	var upload entities.AttachmentUpload
+	if err := r.db.Preload("Document.Author").First(&upload, "id = ?", ID).Error; err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// CompleteAttachmentUpload turns the upload into the attachment, the upload row is claimed first so it completes only once.
+// When the content is stored already the attachment shares that object, and the key of the uploaded copy is returned to be deleted.
+func (r *attachmentRepository) CompleteAttachmentUpload(upload *entities.AttachmentUpload, attachment *entities.Attachment) (string, error) {
+	var duplicate string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", upload.ID).Delete(&entities.AttachmentUpload{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var err error
+		if duplicate, err = shareObject(tx, attachment); err != nil {
+			return err
+		}
+		return tx.Create(attachment).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return duplicate, nil
+}
+
+func (r *attachmentRepository) FindExpiredAttachmentUploads(now time.Time) (*[]entities.AttachmentUpload, error) {
+	var uploads []entities.AttachmentUpload
+	if err := r.db.Where("expires_at < ?", now).Find(&uploads).Error; err != nil {
+		return nil, err
+	}
+	return &uploads, nil
+}
+
+func (r *attachmentRepository) DeleteAttachmentUploadByID(ID string) error {
+	return r.db.Delete(&entities.AttachmentUpload{}, "id = ?", ID).Error
+}
+
+func (r *attachmentRepository) SignAttachmentUploadURL(bucketName, objectKey, contentType string, public bool, expiry time.Duration) (string, map[string]string, error) {
+	return r.s3.PresignPutURL(bucketName, objectKey, contentType, public, expiry)
+}
+
+func (r *attachmentRepository) StatAttachmentObject(bucketName, objectKey string) (*s3client.ObjectInfo, error) {
+	return r.s3.StatFile(bucketName, objectKey)
+}
+
+func (r *attachmentRepository) ReadAttachmentHead(bucketName, objectKey string, length int64) ([]byte, error) {
+	return r.s3.ReadFileHead(bucketName, objectKey, length)
+}
+
+// ReadAttachmentObject streams a stored object, the caller closes it
+func (r *attachmentRepository) ReadAttachmentObject(bucketName, objectKey string) (io.ReadCloser, error) {
+	return r.s3.ReadFile(bucketName, objectKey)
+}
+
+// resumable upload
+
+// InsertTusUpload saves the upload and creates the empty file its chunks are written to
+func (r *attachmentRepository) InsertTusUpload(upload *entities.TusUpload) error {
+	if err := r.tus.Create(upload.ID); err != nil {
+		return err
+	}
+	if err := r.db.Create(upload).Error; err != nil {
+		r.tus.Remove(upload.ID)
+		return err
+	}
 	return nil
 }
+
+func (r *attachmentRepository) FindTusUploadByID(ID string) (*entities.TusUpload, error) {
+	var upload entities.TusUpload
+	if err := r.db.First(&upload, "id = ?", ID).Error; err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// AppendTusUpload writes a chunk at the upload's offset and saves the new offset, also when the chunk broke off part way
+func (r *attachmentRepository) AppendTusUpload(upload *entities.TusUpload, body io.Reader, expiresAt time.Time) error {
+	written, appendErr := r.tus.Append(upload.ID, upload.Offset, body, upload.Length-upload.Offset)
+	if written == 0 && appendErr != nil {
+		return appendErr
+	}
+
+	offset := upload.Offset + written
+	if err := r.db.Model(&entities.TusUpload{}).Where("id = ?", upload.ID).Updates(map[string]interface{}{
+		"offset":     offset,
+		"expires_at": expiresAt,
+	}).Error; err != nil {
+		return err
+	}
+	upload.Offset = offset
+	upload.ExpiresAt = expiresAt
+	return appendErr
+}
+
+func (r *attachmentRepository) OpenTusUpload(ID string) (io.ReadCloser, error) {
+	return r.tus.Open(ID)
+}
+
+func (r *attachmentRepository) FindExpiredTusUploads(now time.Time) (*[]entities.TusUpload, error) {
+	var uploads []entities.TusUpload
+	if err := r.db.Where("expires_at < ?", now).Find(&uploads).Error; err != nil {
+		return nil, err
+	}
+	return &uploads, nil
+}
+
+// DeleteTusUploadByID removes the file of the upload before its row, so a failure leaves the row to be cleaned up again
+func (r *attachmentRepository) DeleteTusUploadByID(ID string) error {
+	if err := r.tus.Remove(ID); err != nil {
+		return err
+	}
+	return r.db.Delete(&entities.TusUpload{}, "id = ?", ID).Error
+}
+
+// files served through the API
+
+// OpenStoredFile opens a file of a driver that serves its files through the API, other drivers have none
+func (r *attachmentRepository) OpenStoredFile(bucketName, objectKey string, query url.Values) (io.ReadCloser, *s3client.ObjectInfo, error) {
+	server, ok := r.s3.(s3client.FileServer)
+	if !ok {
+		return nil, nil, s3client.ErrObjectNotFound
+	}
+	return server.OpenFile(bucketName, objectKey, query)
+}
+
+func (r *attachmentRepository) ReceiveStoredFile(bucketName, objectKey, contentType string, query url.Values, body io.Reader) error {
+	server, ok := r.s3.(s3client.FileServer)
+	if !ok {
+		return s3client.ErrAccessDenied
+	}
+	return server.ReceiveFile(bucketName, objectKey, contentType, query, body)
+}
+
+// trash
+func (r *attachmentRepository) FindAllAttachmentsByDocumentID(documentID string) (*[]entities.Attachment, error) {
+	var attachments []entities.Attachment
+	if err := r.db.Unscoped().Preload("Variants").Where("document_id = ?", documentID).Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return &attachments, nil
+}
+
+func (r *attachmentRepository) FindDeletedAttachmentsBefore(before time.Time) (*[]entities.Attachment, error) {
+	var attachments []entities.Attachment
+	if err := r.db.Unscoped().Preload("Variants").Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return &attachments, nil
+}
+
+// PurgeAttachmentByID permanently removes the attachment with its variants and releases its stored object.
+// It returns the key of the object when no other attachment refers to it anymore.
+func (r *attachmentRepository) PurgeAttachmentByID(ID string) ([]string, error) {
+	var released []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var attachment entities.Attachment
+		if err := tx.Unscoped().Select("id", "object_key").First(&attachment, "id = ?", ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("attachment_id = ?", ID).Delete(&entities.AttachmentVariant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&entities.Attachment{}, "id = ?", ID).Error; err != nil {
+			return err
+		}
+
+		var err error
+		released, err = releaseObjects(tx, []string{attachment.ObjectKey})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+// image variants
+
+// FindPendingVariantAttachments finds the oldest images still waiting for their variants
+func (r *attachmentRepository) FindPendingVariantAttachments(limit int) (*[]entities.Attachment, error) {
+	var attachments []entities.Attachment
+	if err := r.db.Where("variant_status = ? AND state = ?", constant.VARIANT_PENDING, constant.ATTACHMENT_COMMITTED).Order("created_at").Limit(limit).Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return &attachments, nil
+}
+
+// SaveAttachmentVariants replaces the variants of an attachment and marks them ready
+func (r *attachmentRepository) SaveAttachmentVariants(ID string, variants *[]entities.AttachmentVariant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("attachment_id = ?", ID).Delete(&entities.AttachmentVariant{}).Error; err != nil {
+			return err
+		}
+		if len(*variants) > 0 {
+			if err := tx.Create(variants).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&entities.Attachment{}).Where("id = ?", ID).Update("variant_status", constant.VARIANT_READY).Error
+	})
+}
+
+func (r *attachmentRepository) UpdateAttachmentVariantStatus(ID, status string) error {
+	return r.db.Model(&entities.Attachment{}).Where("id = ?", ID).Update("variant_status", status).Error
+}
+
+// reconciliation
+
+// ListAttachmentObjects calls fn with every object in the bucket
+func (r *attachmentRepository) ListAttachmentObjects(bucketName string, fn func(objectKey string, info *s3client.ObjectInfo) error) error {
+	return r.s3.ListFiles(bucketName, fn)
+}
+
+// FindAllAttachmentRecords finds every attachment with its variants, pending and trashed ones included
+func (r *attachmentRepository) FindAllAttachmentRecords() (*[]entities.Attachment, error) {
+	var attachments []entities.Attachment
+	if err := r.db.Unscoped().Preload("Variants").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return &attachments, nil
+}
+
+// FindAllAttachmentUploadKeys finds the object keys of direct uploads that are not completed yet
+func (r *attachmentRepository) FindAllAttachmentUploadKeys() ([]string, error) {
+	var keys []string
+	if err := r.db.Model(&entities.AttachmentUpload{}).Pluck("object_key", &keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// deduplication
+
+// StorageSavings is how much storage sharing objects between attachments of the same content saves
+type StorageSavings struct {
+	Objects         int64 // stored objects
+	References      int64 // attachments referring to them
+	StoredBytes     int64
+	ReferencedBytes int64 // bytes the attachments would take if each had its own object
+}
+
+func (r *attachmentRepository) FindStorageSavings() (*StorageSavings, error) {
+	var savings StorageSavings
+	if err := r.db.Model(&entities.StoredObject{}).
+		Select("COUNT(*) AS objects, COALESCE(SUM(ref_count), 0) AS \"references\", COALESCE(SUM(size), 0) AS stored_bytes, COALESCE(SUM(size * ref_count), 0) AS referenced_bytes").
+		Scan(&savings).Error; err != nil {
+		return nil, err
+	}
+	return &savings, nil
+}
+
+// FindMostSharedObjects finds the objects shared by more than one attachment that save the most bytes
+func (r *attachmentRepository) FindMostSharedObjects(limit int) (*[]entities.StoredObject, error) {
+	objects := make([]entities.StoredObject, 0)
+	if err := r.db.Where("ref_count > 1").Order("size * (ref_count - 1) DESC, object_key").Limit(limit).Find(&objects).Error; err != nil {
+		return nil, err
+	}
+	return &objects, nil
+}
+
+// shareObject points an attachment with a hash at the stored object of the same content and visibility,
+// which is created with the attachment's own key when there is none yet.
+// It returns the attachment's own key when another object was shared, the copy under that key is no longer needed.
+func shareObject(tx *gorm.DB, a *entities.Attachment) (string, error) {
+	object := entities.StoredObject{
+		ObjectKey:  a.ObjectKey,
+		Hash:       a.Hash,
+		Visibility: a.Visibility,
+		Size:       a.Size,
+		RefCount:   1,
+	}
+	// the upsert takes the row lock, so concurrent uploads of the same content end up sharing one object
+	if err := tx.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "hash"}, {Name: "visibility"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"ref_count": gorm.Expr("stored_objects.ref_count + 1"), "updated_at": time.Now()}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "object_key"}}},
+	).Create(&object).Error; err != nil {
+		return "", err
+	}
+
+	if object.ObjectKey == a.ObjectKey {
+		return "", nil
+	}
+	duplicate := a.ObjectKey
+	a.ObjectKey = object.ObjectKey
+	return duplicate, nil
+}
+
+// releaseObjects drops one reference to each object key, a key appears once for every attachment that was purged.
+// It returns the keys of the objects nothing refers to anymore, their rows are gone and the objects are for the caller to delete.
+func releaseObjects(tx *gorm.DB, objectKeys []string) ([]string, error) {
+	var released []string
+	for _, objectKey := range objectKeys {
+		var object entities.StoredObject
+		result := tx.Model(&object).Clauses(clause.Returning{}).Where("object_key = ?", objectKey).Update("ref_count", gorm.Expr("ref_count - 1"))
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			// attachments uploaded before deduplication, and pending ones, own their object alone
+			released = append(released, objectKey)
+			continue
+		}
+		if object.RefCount > 0 {
+			continue
+		}
+		if err := tx.Delete(&entities.StoredObject{}, "object_key = ?", objectKey).Error; err != nil {
+			return nil, err
+		}
+		released = append(released, objectKey)
+	}
+	return released, nil
+}
+
+func orderVariants(db *gorm.DB) *gorm.DB {
+	return db.Order("attachment_variants.width, attachment_variants.format")
+}
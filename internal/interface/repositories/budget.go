@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+)
+
+type BudgetRepository interface {
+	FindBudgets(args *FindBudgetsArgs) (*[]entities.Budget, int64, error)
+	FindBudgetByDocumentID(documentID string) (*entities.Budget, error)
+	SaveBudget(budget *entities.Budget) error
+	DeleteBudget(documentID string) error
+}
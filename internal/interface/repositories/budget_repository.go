@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"strings"
+
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type budgetRepository struct {
+	db *gorm.DB
+}
+
+func NewBudgetRepository(db *gorm.DB) BudgetRepository {
+	return &budgetRepository{
+		db: db,
+	}
+}
+
+type FindBudgetsArgs struct {
+	Offset       int
+	Limit        int
+	Organization string // organization: sccu, sgcu, every organization when empty
+	FiscalYear   int    // every fiscal year when 0
+}
+
+// FindBudgets finds the budgets of public documents, latest fiscal year first. Items are not loaded.
+func (r *budgetRepository) FindBudgets(args *FindBudgetsArgs) (*[]entities.Budget, int64, error) {
+	var budgets []entities.Budget
+	var total int64
+
+	query := r.db.Model(&entities.Budget{}).
+		Joins("Document").
+		Where(`"Document".deleted_at IS NULL`).
+		Where(`"Document".status IN ?`, []string{constant.PUBLISHED, constant.ARCHIVED})
+
+	if args.Organization != "" {
+		query = query.Where("budgets.organization = ?", strings.ToUpper(args.Organization))
+	}
+	if args.FiscalYear != 0 {
+		query = query.Where("budgets.fiscal_year = ?", args.FiscalYear)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("budgets.fiscal_year DESC, budgets.organization, budgets.document_id").Offset(args.Offset).Limit(args.Limit).Find(&budgets).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &budgets, total, nil
+}
+
+func (r *budgetRepository) FindBudgetByDocumentID(documentID string) (*entities.Budget, error) {
+	var budget entities.Budget
+
+	if err := r.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).First(&budget, "document_id = ?", documentID).Error; err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
+
+// SaveBudget creates or replaces the budget of a document together with every line item
+func (r *budgetRepository) SaveBudget(budget *entities.Budget) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Items", "Document").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "document_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"fiscal_year", "organization", "currency", "total_planned", "total_actual", "updated_by", "updated_at"}),
+		}).Create(budget).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("document_id = ?", budget.DocumentID).Delete(&entities.BudgetItem{}).Error; err != nil {
+			return err
+		}
+		if len(budget.Items) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(budget.Items, 500).Error
+	})
+}
+
+func (r *budgetRepository) DeleteBudget(documentID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("document_id = ?", documentID).Delete(&entities.BudgetItem{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("document_id = ?", documentID).Delete(&entities.Budget{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+)
+
+type DatasetRepository interface {
+	FindDatasetByDocumentID(documentID string) (*entities.Dataset, error)
+	SaveDataset(dataset *entities.Dataset) error
+	DeleteDataset(documentID string) error
+}
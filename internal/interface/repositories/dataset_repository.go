@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type datasetRepository struct {
+	db *gorm.DB
+}
+
+func NewDatasetRepository(db *gorm.DB) DatasetRepository {
+	return &datasetRepository{
+		db: db,
+	}
+}
+
+// FindDatasetByDocumentID loads the dataset with its series and points in order
+func (r *datasetRepository) FindDatasetByDocumentID(documentID string) (*entities.Dataset, error) {
+	var dataset entities.Dataset
+
+	if err := r.db.
+		Preload("Series", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		}).
+		Preload("Points", func(db *gorm.DB) *gorm.DB {
+			return db.Order("series, position")
+		}).
+		First(&dataset, "document_id = ?", documentID).Error; err != nil {
+		return nil, err
+	}
+	return &dataset, nil
+}
+
+// SaveDataset creates or replaces the dataset of a document together with every series and point
+func (r *datasetRepository) SaveDataset(dataset *entities.Dataset) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Series", "Points", "Document").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "document_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"dimensions", "updated_by", "updated_at"}),
+		}).Create(dataset).Error; err != nil {
+			return err
+		}
+
+		for _, child := range []interface{}{&entities.DatasetPoint{}, &entities.DatasetSeries{}} {
+			if err := tx.Where("document_id = ?", dataset.DocumentID).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(dataset.Series) > 0 {
+			if err := tx.Create(dataset.Series).Error; err != nil {
+				return err
+			}
+		}
+		if len(dataset.Points) > 0 {
+			if err := tx.CreateInBatches(dataset.Points, 1000).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *datasetRepository) DeleteDataset(documentID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, child := range []interface{}{&entities.DatasetPoint{}, &entities.DatasetSeries{}} {
+			if err := tx.Where("document_id = ?", documentID).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Where("document_id = ?", documentID).Delete(&entities.Dataset{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
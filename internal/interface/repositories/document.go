@@ -1,15 +1,67 @@
 package repositories
 
-import "github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+import (
+	"time"
+
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+)
 
 type DocumentRepository interface {
 	// client side
 	FindAllDocuments(args *FindAllDocumentsArgs) (*[]entities.Document, error)
 	FindDocumentByID(ID string) (*entities.Document, error)
+	FindDocumentDetailByID(ID string) (*entities.Document, error)
+	FindDocumentBySlug(slug string) (*entities.Document, error)
+	FindOldDocumentSlug(slug string) (*entities.DocumentSlug, error)
 
 	// back office
 	FindDocumentsByRole(args *FindAllDocumentsByRoleArgs) (*[]entities.Document, error)
 	InsertDocument(document *entities.Document) error
-	UpdateDocumentByID(ID string, updateMap interface{}) error
+	UpdateDocumentByID(ID string, updateMap interface{}, editorID string) error
 	DeleteDocumentByID(ID string) error
+	UpdateDocumentTags(ID string, tags *[]entities.Tag) error
+	DocumentIDExists(ID string) (bool, error)
+	SlugExists(slug, exceptDocumentID string) (bool, error)
+	UpdateDocumentSlug(ID, slug string) error
+	UpdateDocumentExpiry(ID string, expiresAt *time.Time) error
+	ArchiveDocumentByID(ID string, now time.Time) error
+	AddDocumentTags(ID string, tags *[]entities.Tag) error
+	Transaction(fn func(repo DocumentRepository) error) error
+
+	// translation
+	FindDocumentTranslations(documentID string) (*[]entities.DocumentTranslation, error)
+	UpsertDocumentTranslation(translation *entities.DocumentTranslation) error
+	DeleteDocumentTranslation(documentID, locale string) error
+
+	// featured
+	FindFeaturedDocuments(scopes []string, activeOnly bool) (*[]entities.FeaturedDocument, error)
+	FindPinnedDocumentIDs(documentIDs []string) ([]string, error)
+	ReplaceFeaturedDocuments(scope string, slots *[]entities.FeaturedDocument) error
+	DeleteFeaturedDocument(scope, documentID string) error
+
+	// link
+	FindDocumentLinks(ID string) (*[]entities.DocumentLink, *[]entities.DocumentLink, error)
+	FindDocumentsByIDs(IDs []string) (*[]entities.Document, error)
+	ReplaceDocumentLinks(ID string, links *[]entities.DocumentLink) error
+	FindSupersedingDocumentIDs(documentIDs []string) (map[string]string, error)
+
+	// review
+	FindPendingDocuments(args *FindPendingDocumentsArgs) (*[]entities.Document, int64, error)
+	FindDocumentReviews(documentID string) (*[]entities.DocumentReview, error)
+	UpdateDocumentStatus(review *entities.DocumentReview) error
+
+	// revision
+	FindDocumentRevisions(documentID string) (*[]entities.DocumentRevision, error)
+	FindDocumentRevision(documentID string, revision int) (*entities.DocumentRevision, error)
+	RestoreDocumentRevision(documentID string, revision int, editorID string) error
+
+	// trash
+	FindDeletedDocuments(args *FindDeletedDocumentsArgs) (*[]entities.Document, int64, error)
+	FindDeletedDocumentByID(ID string) (*entities.Document, error)
+	FindDeletedDocumentIDsBefore(before time.Time) ([]string, error)
+	RestoreDocumentByID(ID string) error
+	PurgeDocumentByID(ID string) ([]string, error)
+
+	// job
+	ArchiveExpiredDocuments(now time.Time) (int64, error)
 }
@@ -1,13 +1,17 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+	"github.com/isd-sgcu/sucu-backend-2024/utils"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
 	"github.com/jinzhu/copier"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type documentRepository struct {
@@ -25,58 +29,141 @@ type FindAllDocumentsArgs struct {
 	Limit        int
 	DocumentType string
 	Organization string
-	Title        string
+	Query        string   // full-text search over title and content
+	Tags         []string // normalized tag names
+	TagMode      string   // tag mode: any, all
+	Locale       string   // only documents readable in this locale
+	Archived     bool     // archived documents instead of the live ones
 	StartTime    time.Time
 	EndTime      time.Time
 }
 
 // client side
 func (r *documentRepository) FindAllDocuments(args *FindAllDocumentsArgs) (*[]entities.Document, error) {
-	var documents []entities.Document
+	query := r.listQuery(args.Query, args.Tags, args.TagMode).
+		Where("users.role_id LIKE ?", fmt.Sprintf("%%%s%%", strings.ToUpper(args.Organization))).
+		Where("documents.created_at BETWEEN ? AND ?", args.StartTime, args.EndTime).
+		Offset(args.Offset).
+		Limit(args.Limit)
 
-	var results []struct {
-		*entities.Document
-		*entities.User
-		DocumentID string
-		AuthorID   string
+	// expired documents count as archived even before the archive job has run
+	now := time.Now()
+	if args.Archived {
+		query = query.Where("(documents.status = ? OR (documents.status = ? AND documents.expires_at <= ?))", constant.ARCHIVED, constant.PUBLISHED, now)
+	} else {
+		query = query.Where("documents.status = ? AND (documents.expires_at IS NULL OR documents.expires_at > ?)", constant.PUBLISHED, now)
 	}
 
-	err := r.db.Raw(`
-		SELECT *, documents.id AS document_id, users.id AS AuthorID 
-		FROM documents INNER JOIN users ON documents.user_id = users.id
-		WHERE users.role_id LIKE ?
-		AND  documents.type_id LIKE ?
-		AND	 LOWER(documents.title) LIKE ?
-		AND  documents.created_at BETWEEN ? AND ?
-		OFFSET ? LIMIT ?`,
-		fmt.Sprintf("%%%s%%", strings.ToUpper(args.Organization)),
-		fmt.Sprintf("%%%s%%", strings.ToUpper(args.DocumentType)),
-		fmt.Sprintf("%%%s%%", strings.ToLower(args.Title)),
-		args.StartTime,
-		args.EndTime,
-		args.Offset,
-		args.Limit).Scan(&results).Error
+	// exact match, one type name may be a prefix of another
+	if args.DocumentType != "" {
+		query = query.Where("documents.type_id = ?", strings.ToUpper(args.DocumentType))
+	}
+	if args.Locale != "" {
+		query = query.Where("(documents.locale = ? OR documents.id IN (?))", args.Locale,
+			r.db.Table("document_translations").Select("document_id").Where("locale = ?", args.Locale))
+	}
 
-	if err != nil {
+	return r.scanDocuments(query)
+}
+
+func (r *documentRepository) FindDocumentByID(ID string) (*entities.Document, error) {
+	var document entities.Document
+	if err := r.db.Preload("Author").First(&document, "id = ?", ID).Error; err != nil {
 		return nil, err
 	}
+	return &document, nil
+}
 
-	for _, result := range results {
-		var d entities.Document
-		copier.Copy(&d, &result)
-		copier.Copy(&d.Author, &result)
+// FindDocumentDetailByID loads a document with everything shown on its page
+func (r *documentRepository) FindDocumentDetailByID(ID string) (*entities.Document, error) {
+	var document entities.Document
+	if err := r.detailQuery().First(&document, "id = ?", ID).Error; err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
 
-		d.ID = result.DocumentID
-		d.Author.ID = result.AuthorID
+func (r *documentRepository) FindDocumentBySlug(slug string) (*entities.Document, error) {
+	var document entities.Document
+	if err := r.detailQuery().First(&document, "slug = ?", slug).Error; err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
 
-		documents = append(documents, d)
+// FindOldDocumentSlug finds a slug a document used before it was renamed
+func (r *documentRepository) FindOldDocumentSlug(slug string) (*entities.DocumentSlug, error) {
+	var documentSlug entities.DocumentSlug
+	if err := r.db.First(&documentSlug, "slug = ?", slug).Error; err != nil {
+		return nil, err
 	}
+	return &documentSlug, nil
+}
 
-	return &documents, nil
+func (r *documentRepository) detailQuery() *gorm.DB {
+	return r.db.
+		Preload("Author").
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("tags.name") }).
+		Preload("Translations", func(db *gorm.DB) *gorm.DB { return db.Order("locale") }).
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+			return db.Where("attachments.state = ?", constant.ATTACHMENT_COMMITTED).Order("attachments.created_at")
+		}).
+		Preload("Attachments.Variants", orderVariants).
+		Preload("Links", func(db *gorm.DB) *gorm.DB { return db.Order("document_links.created_at") }).
+		Preload("Links.Target").
+		Preload("Backlinks", func(db *gorm.DB) *gorm.DB { return db.Order("document_links.created_at") }).
+		Preload("Backlinks.Source")
 }
 
-func (r *documentRepository) FindDocumentByID(ID string) (*entities.Document, error) {
-	return nil, nil
+// DocumentIDExists also looks at trashed documents, whose IDs stay reserved until purged
+func (r *documentRepository) DocumentIDExists(ID string) (bool, error) {
+	var count int64
+	if err := r.db.Unscoped().Model(&entities.Document{}).Where("id = ?", ID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SlugExists reports whether a slug is taken by another document, currently or in the past
+func (r *documentRepository) SlugExists(slug, exceptDocumentID string) (bool, error) {
+	var count int64
+	if err := r.db.Unscoped().Model(&entities.Document{}).Where("slug = ? AND id <> ?", slug, exceptDocumentID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := r.db.Model(&entities.DocumentSlug{}).Where("slug = ? AND document_id <> ?", slug, exceptDocumentID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateDocumentSlug moves a document to a new slug and keeps the current one as a redirect
+func (r *documentRepository) UpdateDocumentSlug(ID, slug string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		document, err := lockDocument(tx, ID)
+		if err != nil {
+			return err
+		}
+		if document.Slug == slug {
+			return nil
+		}
+
+		// going back to a former slug turns it into the current one again
+		if err := tx.Where("slug = ? AND document_id = ?", slug, ID).Delete(&entities.DocumentSlug{}).Error; err != nil {
+			return err
+		}
+
+		if document.Slug != "" {
+			if err := tx.Create(&entities.DocumentSlug{Slug: document.Slug, DocumentID: ID}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(document).UpdateColumn("slug", slug).Error
+	})
 }
 
 type FindAllDocumentsByRoleArgs struct {
@@ -84,7 +171,10 @@ type FindAllDocumentsByRoleArgs struct {
 	Limit        int
 	DocumentType string
 	Organization string
-	Title        string
+	Query        string   // full-text search over title and content
+	Tags         []string // normalized tag names
+	TagMode      string   // tag mode: any, all
+	Locale       string   // only documents readable in this locale
 	StartTime    time.Time
 	EndTime      time.Time
 	Role         string
@@ -92,7 +182,59 @@ type FindAllDocumentsByRoleArgs struct {
 
 // back office
 func (r *documentRepository) FindDocumentsByRole(args *FindAllDocumentsByRoleArgs) (*[]entities.Document, error) {
-	var documents []entities.Document
+	query := r.listQuery(args.Query, args.Tags, args.TagMode).
+		Where("users.role_id LIKE ?", fmt.Sprintf("%%%s%%", strings.ToUpper(args.Organization))).
+		Where("users.role_id = ?", strings.ToUpper(args.Role)).
+		Where("documents.created_at BETWEEN ? AND ?", args.StartTime, args.EndTime).
+		// the listing is public, unreviewed documents stay in the back office and expired ones count as archived
+		Where("documents.status = ? AND (documents.expires_at IS NULL OR documents.expires_at > ?)", constant.PUBLISHED, time.Now()).
+		Offset(args.Offset).
+		Limit(args.Limit)
+
+	// exact match, one type name may be a prefix of another
+	if args.DocumentType != "" {
+		query = query.Where("documents.type_id = ?", strings.ToUpper(args.DocumentType))
+	}
+	if args.Locale != "" {
+		query = query.Where("(documents.locale = ? OR documents.id IN (?))", args.Locale,
+			r.db.Table("document_translations").Select("document_id").Where("locale = ?", args.Locale))
+	}
+
+	return r.scanDocuments(query)
+}
+
+// listQuery selects live documents joined with their author, newest first.
+// A non-empty search narrows the rows to full-text matches ranked by relevance,
+// and tags narrow them to documents carrying any or all of the named tags.
+func (r *documentRepository) listQuery(search string, tags []string, tagMode string) *gorm.DB {
+	query := r.db.Table("documents").
+		Select("*, documents.id AS document_id, users.id AS author_id").
+		Joins("INNER JOIN users ON documents.user_id = users.id").
+		Where("documents.deleted_at IS NULL")
+
+	if len(tags) > 0 {
+		tagged := r.db.Table("document_tags").
+			Select("document_tags.document_id").
+			Joins("INNER JOIN tags ON tags.id = document_tags.tag_id").
+			Where("tags.name IN ?", tags).
+			Group("document_tags.document_id")
+		if tagMode == constant.TAG_MODE_ALL {
+			tagged = tagged.Having("COUNT(DISTINCT tags.id) = ?", len(tags))
+		}
+		query = query.Where("documents.id IN (?)", tagged)
+	}
+
+	if tsquery := utils.SearchQuery(search); tsquery != "" {
+		query = query.
+			Where(constant.DOCUMENT_SEARCH_VECTOR+" @@ ?::tsquery", tsquery).
+			Order(clause.Expr{SQL: "ts_rank(" + constant.DOCUMENT_SEARCH_VECTOR + ", ?::tsquery) DESC", Vars: []interface{}{tsquery}})
+	}
+
+	return query.Order("documents.created_at DESC")
+}
+
+func (r *documentRepository) scanDocuments(query *gorm.DB) (*[]entities.Document, error) {
+	documents := make([]entities.Document, 0)
 
 	var results []struct {
 		*entities.Document
@@ -101,25 +243,7 @@ func (r *documentRepository) FindDocumentsByRole(args *FindAllDocumentsByRoleArg
 		AuthorID   string
 	}
 
-	err := r.db.Raw(`
-		SELECT *, documents.id AS document_id, users.id AS AuthorID 
-		FROM documents INNER JOIN users ON documents.user_id = users.id
-		WHERE documents.type_id LIKE ?
-		AND	 LOWER(documents.title) LIKE ?
-		AND  users.role_id LIKE ?
-		AND  users.role_id = ?
-		AND  documents.created_at BETWEEN ? AND ?
-		OFFSET ? LIMIT ?`,
-		fmt.Sprintf("%%%s%%", strings.ToUpper(args.DocumentType)),
-		fmt.Sprintf("%%%s%%", strings.ToLower(args.Title)),
-		fmt.Sprintf("%%%s%%", strings.ToUpper(args.Organization)),
-		strings.ToUpper(args.Role),
-		args.StartTime,
-		args.EndTime,
-		args.Offset,
-		args.Limit).Scan(&results).Error
-
-	if err != nil {
+	if err := query.Scan(&results).Error; err != nil {
 		return nil, err
 	}
 
@@ -134,25 +258,690 @@ func (r *documentRepository) FindDocumentsByRole(args *FindAllDocumentsByRoleArg
 		documents = append(documents, d)
 	}
 
+	if err := r.loadTags(&documents); err != nil {
+		return nil, err
+	}
+	if err := r.loadTranslations(&documents); err != nil {
+		return nil, err
+	}
+
 	return &documents, nil
 }
 
-func (r *documentRepository) InsertDocument(document *entities.Document) error {
-	return r.db.Create(document).Error
+// loadTags fills the tags of scanned documents with a single query
+func (r *documentRepository) loadTags(documents *[]entities.Document) error {
+	if len(*documents) == 0 {
+		return nil
+	}
+
+	IDs := make([]string, len(*documents))
+	for i, d := range *documents {
+		IDs[i] = d.ID
+	}
+
+	var rows []struct {
+		entities.Tag
+		DocumentID string
+	}
+	if err := r.db.Table("tags").
+		Select("tags.*, document_tags.document_id").
+		Joins("INNER JOIN document_tags ON document_tags.tag_id = tags.id").
+		Where("document_tags.document_id IN ?", IDs).
+		Order("tags.name").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	tags := make(map[string][]entities.Tag)
+	for _, row := range rows {
+		tags[row.DocumentID] = append(tags[row.DocumentID], row.Tag)
+	}
+	for i := range *documents {
+		(*documents)[i].Tags = tags[(*documents)[i].ID]
+	}
+
+	return nil
 }
 
-func (r *documentRepository) UpdateDocumentByID(ID string, updateMap interface{}) error {
-	result := r.db.Model(&entities.Document{}).Where("id = ?", ID).Updates(updateMap)
+// loadTranslations fills the translations of scanned documents with a single query
+func (r *documentRepository) loadTranslations(documents *[]entities.Document) error {
+	if len(*documents) == 0 {
+		return nil
+	}
+
+	IDs := make([]string, len(*documents))
+	for i, d := range *documents {
+		IDs[i] = d.ID
+	}
+
+	var translations []entities.DocumentTranslation
+	if err := r.db.Where("document_id IN ?", IDs).Order("locale").Find(&translations).Error; err != nil {
+		return err
+	}
+
+	byDocument := make(map[string][]entities.DocumentTranslation)
+	for _, t := range translations {
+		byDocument[t.DocumentID] = append(byDocument[t.DocumentID], t)
+	}
+	for i := range *documents {
+		(*documents)[i].Translations = byDocument[(*documents)[i].ID]
+	}
+
+	return nil
+}
+
+func (r *documentRepository) FindDocumentTranslations(documentID string) (*[]entities.DocumentTranslation, error) {
+	var translations []entities.DocumentTranslation
+	if err := r.db.Where("document_id = ?", documentID).Order("locale").Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return &translations, nil
+}
+
+// UpsertDocumentTranslation creates the translation of a locale or overwrites the existing one
+func (r *documentRepository) UpsertDocumentTranslation(translation *entities.DocumentTranslation) error {
+	text := utils.PlainText(translation.Content)
+	translation.AutoExcerpt = utils.Excerpt(text, constant.EXCERPT_SIZE)
+	translation.ReadingTime = utils.ReadingTime(text)
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "document_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "content", "source", "format", "excerpt", "auto_excerpt", "reading_time", "updated_at"}),
+	}).Create(translation).Error
+}
+
+func (r *documentRepository) DeleteDocumentTranslation(documentID, locale string) error {
+	result := r.db.Where("document_id = ? AND locale = ?", documentID, locale).Delete(&entities.DocumentTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
 	if result.RowsAffected == 0 {
 		return gorm.ErrRecordNotFound
 	}
-	return result.Error
+	return nil
 }
 
-func (r *documentRepository) DeleteDocumentByID(ID string) error {
-	result := r.db.Where("id = ?", ID).Delete(&entities.Document{})
+// featured
+
+// FindFeaturedDocuments finds the slots of the given scopes ordered by scope and position.
+// activeOnly skips expired slots and documents the public cannot see.
+func (r *documentRepository) FindFeaturedDocuments(scopes []string, activeOnly bool) (*[]entities.FeaturedDocument, error) {
+	var slots []entities.FeaturedDocument
+
+	query := r.db.Joins("Document").Joins("Document.Author").
+		Where("featured_documents.scope IN ?", scopes).
+		Where(`"Document".id IS NOT NULL AND "Document".deleted_at IS NULL`).
+		Order("featured_documents.scope, featured_documents.position")
+
+	if activeOnly {
+		query = query.
+			Where(`"Document".status = ?`, constant.PUBLISHED).
+			Where(`"Document".expires_at IS NULL OR "Document".expires_at > ?`, time.Now()).
+			Where("featured_documents.expires_at IS NULL OR featured_documents.expires_at > ?", time.Now())
+	}
+
+	if err := query.Find(&slots).Error; err != nil {
+		return nil, err
+	}
+
+	documents := make([]entities.Document, len(slots))
+	for i, slot := range slots {
+		documents[i] = slot.Document
+	}
+	if err := r.loadTranslations(&documents); err != nil {
+		return nil, err
+	}
+	for i := range slots {
+		slots[i].Document = documents[i]
+	}
+
+	return &slots, nil
+}
+
+// FindPinnedDocumentIDs returns the IDs among documentIDs that hold an unexpired featured slot
+func (r *documentRepository) FindPinnedDocumentIDs(documentIDs []string) ([]string, error) {
+	pinned := make([]string, 0)
+	if len(documentIDs) == 0 {
+		return pinned, nil
+	}
+
+	if err := r.db.Model(&entities.FeaturedDocument{}).
+		Distinct("document_id").
+		Where("document_id IN ?", documentIDs).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Pluck("document_id", &pinned).Error; err != nil {
+		return nil, err
+	}
+	return pinned, nil
+}
+
+// ReplaceFeaturedDocuments swaps every slot of a scope for the given ones
+func (r *documentRepository) ReplaceFeaturedDocuments(scope string, slots *[]entities.FeaturedDocument) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("scope = ?", scope).Delete(&entities.FeaturedDocument{}).Error; err != nil {
+			return err
+		}
+		if len(*slots) == 0 {
+			return nil
+		}
+		return tx.Omit("Document").Create(slots).Error
+	})
+}
+
+// DeleteFeaturedDocument unpins a document from a scope and closes the gap it leaves
+func (r *documentRepository) DeleteFeaturedDocument(scope, documentID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var slot entities.FeaturedDocument
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&slot, "scope = ? AND document_id = ?", scope, documentID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&slot).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.FeaturedDocument{}).
+			Where("scope = ? AND position > ?", scope, slot.Position).
+			UpdateColumn("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+// link
+
+// FindDocumentLinks finds the links going out of a document and the backlinks pointing at it, with the other document loaded
+func (r *documentRepository) FindDocumentLinks(ID string) (*[]entities.DocumentLink, *[]entities.DocumentLink, error) {
+	var links, backlinks []entities.DocumentLink
+	if err := r.db.Joins("Target").
+		Where("document_links.source_id = ?", ID).
+		Where(`"Target".id IS NOT NULL AND "Target".deleted_at IS NULL`).
+		Order("document_links.created_at").Find(&links).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := r.db.Joins("Source").
+		Where("document_links.target_id = ?", ID).
+		Where(`"Source".id IS NOT NULL AND "Source".deleted_at IS NULL`).
+		Order("document_links.created_at").Find(&backlinks).Error; err != nil {
+		return nil, nil, err
+	}
+	return &links, &backlinks, nil
+}
+
+// FindDocumentsByIDs finds the documents among IDs that are not trashed
+func (r *documentRepository) FindDocumentsByIDs(IDs []string) (*[]entities.Document, error) {
+	documents := make([]entities.Document, 0)
+	if len(IDs) == 0 {
+		return &documents, nil
+	}
+	if err := r.db.Where("id IN ?", IDs).Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return &documents, nil
+}
+
+// ReplaceDocumentLinks swaps every link going out of a document for the given ones
+func (r *documentRepository) ReplaceDocumentLinks(ID string, links *[]entities.DocumentLink) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("source_id = ?", ID).Delete(&entities.DocumentLink{}).Error; err != nil {
+			return err
+		}
+		if len(*links) == 0 {
+			return nil
+		}
+		return tx.Omit("Source", "Target").Create(links).Error
+	})
+}
+
+// FindSupersedingDocumentIDs maps each of documentIDs that a published document supersedes to the newest such document
+func (r *documentRepository) FindSupersedingDocumentIDs(documentIDs []string) (map[string]string, error) {
+	superseded := make(map[string]string)
+	if len(documentIDs) == 0 {
+		return superseded, nil
+	}
+
+	var links []entities.DocumentLink
+	if err := r.db.Joins("Source").
+		Where("document_links.target_id IN ?", documentIDs).
+		Where("document_links.type = ?", constant.LINK_SUPERSEDES).
+		Where(`"Source".deleted_at IS NULL AND "Source".status IN ?`, []string{constant.PUBLISHED, constant.ARCHIVED}).
+		Order(`"Source".created_at`).
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	// ordered oldest first so the newest source wins
+	for _, link := range links {
+		superseded[link.TargetID] = link.SourceID
+	}
+	return superseded, nil
+}
+
+// UpdateDocumentTags replaces every tag of a document
+func (r *documentRepository) UpdateDocumentTags(ID string, tags *[]entities.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var document entities.Document
+		if err := tx.First(&document, "id = ?", ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&document).Association("Tags").Replace(*tags)
+	})
+}
+
+// AddDocumentTags adds tags to a document and keeps the ones it already has
+func (r *documentRepository) AddDocumentTags(ID string, tags *[]entities.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var document entities.Document
+		if err := tx.First(&document, "id = ?", ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&document).Association("Tags").Append(*tags)
+	})
+}
+
+// Transaction runs fn with a repository bound to a single transaction, which is rolled back when fn returns an error
+func (r *documentRepository) Transaction(fn func(repo DocumentRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&documentRepository{db: tx})
+	})
+}
+
+func (r *documentRepository) InsertDocument(document *entities.Document) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		deriveDocumentColumns(document)
+
+		if err := tx.Create(document).Error; err != nil {
+			return err
+		}
+
+		return insertDocumentRevision(tx, document, document.UserID, nil)
+	})
+}
+
+func (r *documentRepository) UpdateDocumentByID(ID string, updateMap interface{}, editorID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		document, err := lockDocument(tx, ID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(document).Updates(updateMap).Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(document, "id = ?", ID).Error; err != nil {
+			return err
+		}
+
+		if err := refreshDerivedColumns(tx, document); err != nil {
+			return err
+		}
+
+		return insertDocumentRevision(tx, document, editorID, nil)
+	})
+}
+
+// UpdateDocumentExpiry sets or clears the expiry of a document.
+// An archived document that no longer expires in the past is published again.
+func (r *documentRepository) UpdateDocumentExpiry(ID string, expiresAt *time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		document, err := lockDocument(tx, ID)
+		if err != nil {
+			return err
+		}
+
+		columns := map[string]interface{}{"expires_at": expiresAt}
+		if document.Status == constant.ARCHIVED && (expiresAt == nil || expiresAt.After(time.Now())) {
+			columns["status"] = constant.PUBLISHED
+			columns["archived_at"] = nil
+		}
+
+		return tx.Model(document).UpdateColumns(columns).Error
+	})
+}
+
+// ArchiveExpiredDocuments archives every published document whose expiry is not after now
+func (r *documentRepository) ArchiveExpiredDocuments(now time.Time) (int64, error) {
+	result := r.db.Model(&entities.Document{}).
+		Where("status = ? AND expires_at <= ?", constant.PUBLISHED, now).
+		UpdateColumns(map[string]interface{}{
+			"status":      constant.ARCHIVED,
+			"archived_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ArchiveDocumentByID archives a published document before its expiry
+func (r *documentRepository) ArchiveDocumentByID(ID string, now time.Time) error {
+	result := r.db.Model(&entities.Document{}).
+		Where("id = ? AND status = ?", ID, constant.PUBLISHED).
+		UpdateColumns(map[string]interface{}{
+			"status":      constant.ARCHIVED,
+			"archived_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
 	if result.RowsAffected == 0 {
 		return gorm.ErrRecordNotFound
 	}
-	return result.Error
+	return nil
+}
+
+// DeleteDocumentByID moves the document and its live attachments to trash.
+// Both share the same deleted_at so a restore brings back exactly what was cascaded.
+func (r *documentRepository) DeleteDocumentByID(ID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		deletedAt := time.Now().Truncate(time.Microsecond)
+
+		result := tx.Model(&entities.Document{}).Where("id = ?", ID).Update("deleted_at", deletedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(&entities.Attachment{}).Where("document_id = ?", ID).Update("deleted_at", deletedAt).Error
+	})
+}
+
+type FindPendingDocumentsArgs struct {
+	Offset       int
+	Limit        int
+	Organization string
+}
+
+// review
+func (r *documentRepository) FindPendingDocuments(args *FindPendingDocumentsArgs) (*[]entities.Document, int64, error) {
+	var documents []entities.Document
+	var total int64
+
+	query := r.db.Model(&entities.Document{}).
+		Joins("Author").
+		Where("documents.status = ?", constant.PENDING).
+		Where(`"Author".role_id LIKE ?`, fmt.Sprintf("%s\\_%%", strings.ToUpper(args.Organization)))
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// oldest submission first so the queue is reviewed in order
+	if err := query.Order("documents.updated_at ASC").Offset(args.Offset).Limit(args.Limit).Find(&documents).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &documents, total, nil
+}
+
+func (r *documentRepository) FindDocumentReviews(documentID string) (*[]entities.DocumentReview, error) {
+	var reviews []entities.DocumentReview
+	if err := r.db.Preload("Actor").Where("document_id = ?", documentID).Order("created_at ASC").Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return &reviews, nil
+}
+
+func (r *documentRepository) UpdateDocumentStatus(review *entities.DocumentReview) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// only move the document if nobody has transitioned it in the meantime
+		result := tx.Model(&entities.Document{}).
+			Where("id = ? AND status = ?", review.DocumentID, review.FromStatus).
+			Update("status", review.ToStatus)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(review).Error
+	})
+}
+
+// revision
+func (r *documentRepository) FindDocumentRevisions(documentID string) (*[]entities.DocumentRevision, error) {
+	var revisions []entities.DocumentRevision
+	if err := r.db.Preload("Editor").Where("document_id = ?", documentID).Order("revision DESC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return &revisions, nil
+}
+
+func (r *documentRepository) FindDocumentRevision(documentID string, revision int) (*entities.DocumentRevision, error) {
+	var documentRevision entities.DocumentRevision
+	if err := r.db.Preload("Editor").First(&documentRevision, "document_id = ? AND revision = ?", documentID, revision).Error; err != nil {
+		return nil, err
+	}
+	return &documentRevision, nil
+}
+
+func (r *documentRepository) RestoreDocumentRevision(documentID string, revision int, editorID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		document, err := lockDocument(tx, documentID)
+		if err != nil {
+			return err
+		}
+
+		var snapshot entities.DocumentRevision
+		if err := tx.First(&snapshot, "document_id = ? AND revision = ?", documentID, revision).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(document).Updates(map[string]interface{}{
+			"title":   snapshot.Title,
+			"content": snapshot.Content,
+			"source":  snapshot.Source,
+			"format":  snapshot.Format,
+			"banner":  snapshot.Banner,
+			"cover":   snapshot.Cover,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(document, "id = ?", documentID).Error; err != nil {
+			return err
+		}
+
+		if err := refreshDerivedColumns(tx, document); err != nil {
+			return err
+		}
+
+		return insertDocumentRevision(tx, document, editorID, &revision)
+	})
+}
+
+// lockDocument loads the document row FOR UPDATE so concurrent edits get sequential revision numbers.
+// Documents created before revisions existed get their current state recorded as revision 1 first,
+// so the text being overwritten is never lost.
+func lockDocument(tx *gorm.DB, ID string) (*entities.Document, error) {
+	var document entities.Document
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&document, "id = ?", ID).Error; err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := tx.Model(&entities.DocumentRevision{}).Where("document_id = ?", ID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		if err := insertDocumentRevision(tx, &document, document.UserID, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return &document, nil
+}
+
+// deriveDocumentColumns fills the columns computed from the title and content
+func deriveDocumentColumns(document *entities.Document) {
+	text := utils.PlainText(document.Content)
+	document.SearchTitle = utils.SearchText(document.Title)
+	document.SearchBody = utils.SearchText(text)
+	document.AutoExcerpt = utils.Excerpt(text, constant.EXCERPT_SIZE)
+	document.ReadingTime = utils.ReadingTime(text)
+}
+
+// refreshDerivedColumns recomputes and saves the derived columns of a document after its text changed
+func refreshDerivedColumns(tx *gorm.DB, document *entities.Document) error {
+	deriveDocumentColumns(document)
+	return tx.Model(document).UpdateColumns(map[string]interface{}{
+		"search_title": document.SearchTitle,
+		"search_body":  document.SearchBody,
+		"auto_excerpt": document.AutoExcerpt,
+		"reading_time": document.ReadingTime,
+	}).Error
+}
+
+func insertDocumentRevision(tx *gorm.DB, document *entities.Document, editorID string, restoredFrom *int) error {
+	var latest entities.DocumentRevision
+	err := tx.Where("document_id = ?", document.ID).Order("revision DESC").First(&latest).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	next := 1
+	if err == nil {
+		// an update that changed nothing does not need a new revision
+		if restoredFrom == nil && isSameSnapshot(&latest, document) {
+			return nil
+		}
+		next = latest.Revision + 1
+	}
+
+	return tx.Create(&entities.DocumentRevision{
+		ID:           fmt.Sprintf("REV-%v", utils.GenerateRandomString("0123456789", 8)),
+		DocumentID:   document.ID,
+		Revision:     next,
+		Title:        document.Title,
+		Content:      document.Content,
+		Source:       document.Source,
+		Format:       document.Format,
+		Banner:       document.Banner,
+		Cover:        document.Cover,
+		EditorID:     editorID,
+		RestoredFrom: restoredFrom,
+	}).Error
+}
+
+func isSameSnapshot(revision *entities.DocumentRevision, document *entities.Document) bool {
+	return revision.Title == document.Title &&
+		revision.Content == document.Content &&
+		revision.Source == document.Source &&
+		revision.Format == document.Format &&
+		utils.EqualStringPtr(revision.Banner, document.Banner) &&
+		utils.EqualStringPtr(revision.Cover, document.Cover)
+}
+
+type FindDeletedDocumentsArgs struct {
+	Offset       int
+	Limit        int
+	Organization string
+}
+
+// trash
+func (r *documentRepository) FindDeletedDocuments(args *FindDeletedDocumentsArgs) (*[]entities.Document, int64, error) {
+	var documents []entities.Document
+	var total int64
+
+	query := r.db.Unscoped().Model(&entities.Document{}).
+		Joins("Author").
+		Where("documents.deleted_at IS NOT NULL").
+		Where(`"Author".role_id LIKE ?`, fmt.Sprintf("%s\\_%%", strings.ToUpper(args.Organization)))
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("documents.deleted_at DESC").Offset(args.Offset).Limit(args.Limit).Find(&documents).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return &documents, total, nil
+}
+
+func (r *documentRepository) FindDeletedDocumentByID(ID string) (*entities.Document, error) {
+	var document entities.Document
+	if err := r.db.Unscoped().Preload("Author").First(&document, "id = ? AND deleted_at IS NOT NULL", ID).Error; err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+func (r *documentRepository) FindDeletedDocumentIDsBefore(before time.Time) ([]string, error) {
+	var IDs []string
+	if err := r.db.Unscoped().Model(&entities.Document{}).Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Pluck("id", &IDs).Error; err != nil {
+		return nil, err
+	}
+	return IDs, nil
+}
+
+func (r *documentRepository) RestoreDocumentByID(ID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var document entities.Document
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&document, "id = ? AND deleted_at IS NOT NULL", ID).Error; err != nil {
+			return err
+		}
+
+		// only bring back the attachments that were trashed together with the document
+		if err := tx.Unscoped().Model(&entities.Attachment{}).
+			Where("document_id = ? AND deleted_at = ?", ID, document.DeletedAt.Time).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&entities.Document{}).Where("id = ?", ID).Update("deleted_at", nil).Error
+	})
+}
+
+// PurgeDocumentByID permanently removes the document with its attachments, uploads, reviews, revisions, links, budget and dataset.
+// It returns the keys of the stored objects no other attachment refers to anymore.
+func (r *documentRepository) PurgeDocumentByID(ID string) ([]string, error) {
+	var released []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM document_tags WHERE document_id = ?", ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("source_id = ? OR target_id = ?", ID, ID).Delete(&entities.DocumentLink{}).Error; err != nil {
+			return err
+		}
+		for _, child := range []interface{}{&entities.DocumentSlug{}, &entities.DocumentTranslation{}, &entities.FeaturedDocument{}, &entities.BudgetItem{}, &entities.Budget{}, &entities.DatasetPoint{}, &entities.DatasetSeries{}, &entities.Dataset{}, &entities.AttachmentUpload{}} {
+			if err := tx.Where("document_id = ?", ID).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+
+		attachmentIDs := tx.Unscoped().Model(&entities.Attachment{}).Select("id").Where("document_id = ?", ID)
+		if err := tx.Where("attachment_id IN (?)", attachmentIDs).Delete(&entities.AttachmentVariant{}).Error; err != nil {
+			return err
+		}
+		var objectKeys []string
+		if err := tx.Unscoped().Model(&entities.Attachment{}).Where("document_id = ?", ID).Pluck("object_key", &objectKeys).Error; err != nil {
+			return err
+		}
+
+		for _, child := range []interface{}{&entities.Attachment{}, &entities.DocumentReview{}, &entities.DocumentRevision{}} {
+			if err := tx.Unscoped().Where("document_id = ?", ID).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if released, err = releaseObjects(tx, objectKeys); err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("id = ?", ID).Delete(&entities.Document{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
 }
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+)
+
+type DocumentTypeRepository interface {
+	FindAllDocumentTypes() (*[]entities.DocumentType, error)
+	FindDocumentTypeByID(ID string) (*entities.DocumentType, error)
+	CountDocumentsByType(ID string) (int64, error)
+	InsertDocumentType(documentType *entities.DocumentType) error
+	UpdateDocumentTypeByID(ID string, updateMap interface{}) error
+	DeleteDocumentTypeByID(ID string) error
+}
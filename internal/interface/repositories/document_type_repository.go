@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+	"gorm.io/gorm"
+)
+
+type documentTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentTypeRepository(db *gorm.DB) DocumentTypeRepository {
+	return &documentTypeRepository{
+		db: db,
+	}
+}
+
+func (r *documentTypeRepository) FindAllDocumentTypes() (*[]entities.DocumentType, error) {
+	var documentTypes []entities.DocumentType
+
+	if err := r.db.Order("sort_order, id").Find(&documentTypes).Error; err != nil {
+		return nil, err
+	}
+	return &documentTypes, nil
+}
+
+func (r *documentTypeRepository) FindDocumentTypeByID(ID string) (*entities.DocumentType, error) {
+	var documentType entities.DocumentType
+
+	if err := r.db.First(&documentType, "id = ?", ID).Error; err != nil {
+		return nil, err
+	}
+	return &documentType, nil
+}
+
+// CountDocumentsByType counts every document of a type, including the ones in the trash
+func (r *documentTypeRepository) CountDocumentsByType(ID string) (int64, error) {
+	var count int64
+
+	if err := r.db.Unscoped().Model(&entities.Document{}).Where("type_id = ?", ID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *documentTypeRepository) InsertDocumentType(documentType *entities.DocumentType) error {
+	return r.db.Create(documentType).Error
+}
+
+func (r *documentTypeRepository) UpdateDocumentTypeByID(ID string, updateMap interface{}) error {
+	result := r.db.Model(&entities.DocumentType{}).Where("id = ?", ID).Updates(updateMap)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteDocumentTypeByID removes the row for good so the ID can be reused later
+func (r *documentTypeRepository) DeleteDocumentTypeByID(ID string) error {
+	result := r.db.Unscoped().Where("id = ?", ID).Delete(&entities.DocumentType{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
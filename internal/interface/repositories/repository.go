@@ -4,4 +4,8 @@ type Repository interface {
 	User() UserRepository
 	Attachment() AttachmentRepository
 	Document() DocumentRepository
+	Tag() TagRepository
+	DocumentType() DocumentTypeRepository
+	Budget() BudgetRepository
+	Dataset() DatasetRepository
 }
@@ -3,21 +3,30 @@ package repositories
 import (
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/s3client"
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/tusstore"
 
 	"gorm.io/gorm"
 )
 
 type repository struct {
-	UserRepository       UserRepository
-	AttachmentRepository AttachmentRepository
-	DocumentRepository   DocumentRepository
+	UserRepository         UserRepository
+	AttachmentRepository   AttachmentRepository
+	DocumentRepository     DocumentRepository
+	TagRepository          TagRepository
+	DocumentTypeRepository DocumentTypeRepository
+	BudgetRepository       BudgetRepository
+	DatasetRepository      DatasetRepository
 }
 
-func NewRepository(cfg config.Config, db *gorm.DB, s3 s3client.S3Client) Repository {
+func NewRepository(cfg config.Config, db *gorm.DB, s3 s3client.S3Client, tus tusstore.TusStore) Repository {
 	return &repository{
-		UserRepository:       NewUserRepository(db),
-		AttachmentRepository: NewAttachmentRepository(db, s3),
-		DocumentRepository:   NewDocumentRepository(db),
+		UserRepository:         NewUserRepository(db),
+		AttachmentRepository:   NewAttachmentRepository(db, s3, tus),
+		DocumentRepository:     NewDocumentRepository(db),
+		TagRepository:          NewTagRepository(db),
+		DocumentTypeRepository: NewDocumentTypeRepository(db),
+		BudgetRepository:       NewBudgetRepository(db),
+		DatasetRepository:      NewDatasetRepository(db),
 	}
 }
 
@@ -32,3 +41,19 @@ func (r *repository) Attachment() AttachmentRepository {
 func (r *repository) Document() DocumentRepository {
 	return r.DocumentRepository
 }
+
+func (r *repository) Tag() TagRepository {
+	return r.TagRepository
+}
+
+func (r *repository) DocumentType() DocumentTypeRepository {
+	return r.DocumentTypeRepository
+}
+
+func (r *repository) Budget() BudgetRepository {
+	return r.BudgetRepository
+}
+
+func (r *repository) Dataset() DatasetRepository {
+	return r.DatasetRepository
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+)
+
+type TagRepository interface {
+	FindAllTags() (*[]entities.Tag, error)
+	FindTagByID(ID string) (*entities.Tag, error)
+	FindTagByName(name string) (*entities.Tag, error)
+	FindTagsByIDs(IDs []string) (*[]entities.Tag, error)
+	FindTagUsage(organization string) (*[]TagUsage, error)
+	InsertTag(tag *entities.Tag) error
+	UpdateTagByID(ID string, updateMap interface{}) error
+	DeleteTagByID(ID string) error
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/isd-sgcu/sucu-backend-2024/internal/domain/entities"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+	"gorm.io/gorm"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+func (r *tagRepository) FindAllTags() (*[]entities.Tag, error) {
+	var tags []entities.Tag
+
+	if err := r.db.Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return &tags, nil
+}
+
+func (r *tagRepository) FindTagByID(ID string) (*entities.Tag, error) {
+	var tag entities.Tag
+
+	if err := r.db.First(&tag, "id = ?", ID).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) FindTagByName(name string) (*entities.Tag, error) {
+	var tag entities.Tag
+
+	if err := r.db.First(&tag, "name = ?", name).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) FindTagsByIDs(IDs []string) (*[]entities.Tag, error) {
+	tags := make([]entities.Tag, 0)
+
+	if len(IDs) == 0 {
+		return &tags, nil
+	}
+	if err := r.db.Where("id IN ?", IDs).Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return &tags, nil
+}
+
+// TagUsage is the number of public documents of one organization carrying a tag
+type TagUsage struct {
+	TagID        string
+	Name         string
+	Organization string
+	Count        int64
+}
+
+// FindTagUsage counts published documents per tag and organization.
+// An empty organization counts every organization.
+func (r *tagRepository) FindTagUsage(organization string) (*[]TagUsage, error) {
+	usage := make([]TagUsage, 0)
+
+	query := r.db.Table("tags").
+		Select("tags.id AS tag_id, tags.name, split_part(users.role_id, '_', 1) AS organization, COUNT(documents.id) AS count").
+		Joins("INNER JOIN document_tags ON document_tags.tag_id = tags.id").
+		Joins("INNER JOIN documents ON documents.id = document_tags.document_id").
+		Joins("INNER JOIN users ON documents.user_id = users.id").
+		Where("documents.deleted_at IS NULL").
+		Where("documents.status = ?", constant.PUBLISHED).
+		Group("tags.id, tags.name, organization").
+		Order("tags.name, organization")
+
+	if organization != "" {
+		query = query.Where("users.role_id LIKE ?", fmt.Sprintf("%s\\_%%", strings.ToUpper(organization)))
+	}
+
+	if err := query.Scan(&usage).Error; err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func (r *tagRepository) InsertTag(tag *entities.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+func (r *tagRepository) UpdateTagByID(ID string, updateMap interface{}) error {
+	result := r.db.Model(&entities.Tag{}).Where("id = ?", ID).Updates(updateMap)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteTagByID removes the tag from every document before deleting it
+func (r *tagRepository) DeleteTagByID(ID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM document_tags WHERE tag_id = ?", ID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", ID).Delete(&entities.Tag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -29,6 +29,18 @@ func NotFoundError(message string) *AppError {
 	return &AppError{message, http.StatusNotFound}
 }
 
+func ConflictError(message string) *AppError {
+	return &AppError{message, http.StatusConflict}
+}
+
+func GoneError(message string) *AppError {
+	return &AppError{message, http.StatusGone}
+}
+
+func RequestEntityTooLargeError(message string) *AppError {
+	return &AppError{message, http.StatusRequestEntityTooLarge}
+}
+
 func InternalServerError(message string) *AppError {
 	return &AppError{message, http.StatusInternalServerError}
 }
@@ -5,6 +5,8 @@ type Config interface {
 	GetDb() Db
 	GetJwt() Jwt
 	GetAws() Aws
+	GetStorage() Storage
+	GetJob() Job
 }
 
 type Server struct {
@@ -38,4 +40,21 @@ type Aws struct {
 	AccessKeyId     string `mapstructure:"aws_access_key_id"`
 	SecretAccessKey string `mapstructure:"aws_secret_access_key"`
 	Region          string `mapstructure:"aws_region"`
+	Endpoint        string `mapstructure:"aws_endpoint"`   // S3-compatible API, e.g. a MinIO server
+	PathStyle       bool   `mapstructure:"aws_path_style"` // address objects as endpoint/bucket/key instead of bucket.endpoint/key
+}
+
+type Storage struct {
+	Driver     string `mapstructure:"storage_driver"`      // driver: s3, local, memory
+	LocalDir   string `mapstructure:"storage_local_dir"`   // where the local driver keeps files
+	SigningKey string `mapstructure:"storage_signing_key"` // signs URLs of files served through the API, random when empty
+	TusDir     string `mapstructure:"storage_tus_dir"`     // where resumable uploads are kept until their last chunk arrives
+}
+
+type Job struct {
+	TrashRetentionDays    int `mapstructure:"job_trash_retention_days"`    // days a deleted item stays in trash before it is purged
+	TrashPurgeInterval    int `mapstructure:"job_trash_purge_interval"`    // seconds between trash purge runs
+	ArchiveInterval       int `mapstructure:"job_archive_interval"`        // seconds between archiving expired documents
+	UploadCleanupInterval int `mapstructure:"job_upload_cleanup_interval"` // seconds between removing unfinished uploads
+	ImageVariantInterval  int `mapstructure:"job_image_variant_interval"`  // seconds between generating resized images
 }
@@ -4,16 +4,19 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/spf13/viper"
 )
 
 type viperConfig struct {
-	Server `mapstructure:",squash"`
-	Db     `mapstructure:",squash"`
-	Jwt    `mapstructure:",squash"`
-	Aws    `mapstructure:",squash"`
+	Server  `mapstructure:",squash"`
+	Db      `mapstructure:",squash"`
+	Jwt     `mapstructure:",squash"`
+	Aws     `mapstructure:",squash"`
+	Storage `mapstructure:",squash"`
+	Job     `mapstructure:",squash"`
 }
 
 var (
@@ -21,6 +24,22 @@ var (
 	instance Config
 )
 
+const (
+	defaultTrashRetentionDays    = 30
+	defaultTrashPurgeInterval    = 60 * 60 // hourly
+	defaultArchiveInterval       = 5 * 60  // every 5 minutes
+	defaultUploadCleanupInterval = 10 * 60 // every 10 minutes
+	defaultImageVariantInterval  = 30      // every 30 seconds
+)
+
+const (
+	defaultAwsEndpoint     = "https://storage.googleapis.com"
+	defaultAwsPathStyle    = true
+	defaultStorageDriver   = "s3"
+	defaultStorageLocalDir = "./storage"
+	defaultStorageTusDir   = "./tus"
+)
+
 func NewViperConfig() Config {
 	once.Do(func() {
 		serverEnv := os.Getenv("SERVER_ENV")
@@ -88,6 +107,21 @@ func loadProductionEnv() {
 			AccessKeyId:     os.Getenv("AWS_ACCESS_KEY_ID"),
 			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
 			Region:          os.Getenv("AWS_REGION"),
+			Endpoint:        getEnvString("AWS_ENDPOINT", defaultAwsEndpoint),
+			PathStyle:       getEnvBool("AWS_PATH_STYLE", defaultAwsPathStyle),
+		},
+		Storage: Storage{
+			Driver:     getEnvString("STORAGE_DRIVER", defaultStorageDriver),
+			LocalDir:   getEnvString("STORAGE_LOCAL_DIR", defaultStorageLocalDir),
+			SigningKey: os.Getenv("STORAGE_SIGNING_KEY"),
+			TusDir:     getEnvString("STORAGE_TUS_DIR", defaultStorageTusDir),
+		},
+		Job: Job{
+			TrashRetentionDays:    getEnvInt("JOB_TRASH_RETENTION_DAYS", defaultTrashRetentionDays),
+			TrashPurgeInterval:    getEnvInt("JOB_TRASH_PURGE_INTERVAL", defaultTrashPurgeInterval),
+			ArchiveInterval:       getEnvInt("JOB_ARCHIVE_INTERVAL", defaultArchiveInterval),
+			UploadCleanupInterval: getEnvInt("JOB_UPLOAD_CLEANUP_INTERVAL", defaultUploadCleanupInterval),
+			ImageVariantInterval:  getEnvInt("JOB_IMAGE_VARIANT_INTERVAL", defaultImageVariantInterval),
 		},
 	}
 }
@@ -97,10 +131,33 @@ func loadDefaultEnv() {
 	v.SetConfigFile(".env")
 	v.AutomaticEnv()
 
+	defaults := map[string]interface{}{
+		"aws_endpoint":                defaultAwsEndpoint,
+		"aws_path_style":              defaultAwsPathStyle,
+		"storage_driver":              defaultStorageDriver,
+		"storage_local_dir":           defaultStorageLocalDir,
+		"storage_tus_dir":             defaultStorageTusDir,
+		"job_trash_retention_days":    defaultTrashRetentionDays,
+		"job_trash_purge_interval":    defaultTrashPurgeInterval,
+		"job_archive_interval":        defaultArchiveInterval,
+		"job_upload_cleanup_interval": defaultUploadCleanupInterval,
+		"job_image_variant_interval":  defaultImageVariantInterval,
+	}
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
 	if err := v.ReadInConfig(); err != nil {
 		log.Fatalf("Error reading configs file: %s", err)
 	}
 
+	// an empty key in .env is unset, as it is for the environment in production
+	for key, value := range defaults {
+		if v.GetString(key) == "" {
+			v.Set(key, value)
+		}
+	}
+
 	cfg := &viperConfig{}
 	if err := v.Unmarshal(cfg); err != nil {
 		log.Fatalf("Unable to decode into struct, %v", err)
@@ -108,6 +165,42 @@ func loadDefaultEnv() {
 	instance = cfg
 }
 
+// getEnvInt reads an optional integer variable, falling back to def when it is unset
+func getEnvInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		panic("error while loading " + strings.ToLower(key))
+	}
+	return result
+}
+
+// getEnvString reads an optional variable, falling back to def when it is unset
+func getEnvString(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// getEnvBool reads an optional boolean variable, falling back to def when it is unset
+func getEnvBool(key string, def bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		panic("error while loading " + strings.ToLower(key))
+	}
+	return result
+}
+
 func GetConfig() Config {
 	if instance == nil {
 		instance = NewViperConfig()
@@ -130,3 +223,11 @@ func (c *viperConfig) GetJwt() Jwt {
 func (c *viperConfig) GetAws() Aws {
 	return c.Aws
 }
+
+func (c *viperConfig) GetStorage() Storage {
+	return c.Storage
+}
+
+func (c *viperConfig) GetJob() Job {
+	return c.Job
+}
@@ -28,12 +28,150 @@ func main() {
 	if err := db.AutoMigrate(entities.User{}); err != nil {
 		panic("Error while migrating users table: " + err.Error())
 	}
+	if err := db.AutoMigrate(entities.Tag{}); err != nil {
+		panic("Error while migrating tags table: " + err.Error())
+	}
 	if err := db.AutoMigrate(entities.Document{}); err != nil {
 		panic("Error while migrating documents table: " + err.Error())
 	}
 	if err := db.AutoMigrate(entities.Attachment{}); err != nil {
 		panic("Error while migrating attachments table: " + err.Error())
 	}
+	if err := db.AutoMigrate(entities.AttachmentVariant{}); err != nil {
+		panic("Error while migrating attachment_variants table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.AttachmentUpload{}); err != nil {
+		panic("Error while migrating attachment_uploads table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.TusUpload{}); err != nil {
+		panic("Error while migrating tus_uploads table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.StoredObject{}); err != nil {
+		panic("Error while migrating stored_objects table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.DocumentReview{}); err != nil {
+		panic("Error while migrating document_reviews table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.DocumentRevision{}); err != nil {
+		panic("Error while migrating document_revisions table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.DocumentSlug{}); err != nil {
+		panic("Error while migrating document_slugs table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.DocumentTranslation{}); err != nil {
+		panic("Error while migrating document_translations table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.FeaturedDocument{}); err != nil {
+		panic("Error while migrating featured_documents table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.DocumentLink{}); err != nil {
+		panic("Error while migrating document_links table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.Budget{}); err != nil {
+		panic("Error while migrating budgets table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.BudgetItem{}); err != nil {
+		panic("Error while migrating budget_items table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.Dataset{}); err != nil {
+		panic("Error while migrating datasets table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.DatasetSeries{}); err != nil {
+		panic("Error while migrating dataset_series table: " + err.Error())
+	}
+	if err := db.AutoMigrate(entities.DatasetPoint{}); err != nil {
+		panic("Error while migrating dataset_points table: " + err.Error())
+	}
+
+	// content written before the rich text pipeline was stored raw, it is kept as the
+	// HTML source and served sanitized from now on
+	var unrendered []entities.Document
+	if err := db.Unscoped().Where("source = ''").Find(&unrendered).Error; err != nil {
+		panic("Error while reading documents to render: " + err.Error())
+	}
+	for _, d := range unrendered {
+		content := utils.SanitizeHTML(d.Content)
+		text := utils.PlainText(content)
+		if err := db.Unscoped().Model(&d).UpdateColumns(map[string]interface{}{
+			"source":       d.Content,
+			"format":       constant.CONTENT_HTML,
+			"content":      content,
+			"auto_excerpt": utils.Excerpt(text, constant.EXCERPT_SIZE),
+			"reading_time": utils.ReadingTime(text),
+			"search_title": utils.SearchText(d.Title),
+			"search_body":  utils.SearchText(text),
+		}).Error; err != nil {
+			panic("Error while rendering documents: " + err.Error())
+		}
+	}
+	var unrenderedTranslations []entities.DocumentTranslation
+	if err := db.Where("source = ''").Find(&unrenderedTranslations).Error; err != nil {
+		panic("Error while reading document translations to render: " + err.Error())
+	}
+	for _, t := range unrenderedTranslations {
+		content := utils.SanitizeHTML(t.Content)
+		text := utils.PlainText(content)
+		if err := db.Model(&t).UpdateColumns(map[string]interface{}{
+			"source":       t.Content,
+			"format":       constant.CONTENT_HTML,
+			"content":      content,
+			"auto_excerpt": utils.Excerpt(text, constant.EXCERPT_SIZE),
+			"reading_time": utils.ReadingTime(text),
+		}).Error; err != nil {
+			panic("Error while rendering document translations: " + err.Error())
+		}
+	}
+	if err := db.Exec("UPDATE document_revisions SET source = content, format = ? WHERE source = ''", constant.CONTENT_HTML).Error; err != nil {
+		panic("Error while migrating document revisions content: " + err.Error())
+	}
+
+	// attachments uploaded before deduplication keep their object under their own ID, unshared
+	if err := db.Exec("UPDATE attachments SET object_key = id WHERE object_key = ''").Error; err != nil {
+		panic("Error while migrating attachment object keys: " + err.Error())
+	}
+
+	// full-text search
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_documents_search ON documents USING GIN (" + constant.DOCUMENT_SEARCH_VECTOR + ")").Error; err != nil {
+		panic("Error while creating documents search index: " + err.Error())
+	}
+	var unindexed []entities.Document
+	if err := db.Unscoped().Where("search_title = '' AND search_body = ''").Find(&unindexed).Error; err != nil {
+		panic("Error while reading documents to index: " + err.Error())
+	}
+	for _, d := range unindexed {
+		if err := db.Unscoped().Model(&d).UpdateColumns(map[string]interface{}{
+			"search_title": utils.SearchText(d.Title),
+			"search_body":  utils.SearchText(utils.PlainText(d.Content)),
+		}).Error; err != nil {
+			panic("Error while indexing documents: " + err.Error())
+		}
+	}
+
+	// slugs for documents created before permalinks existed
+	var unslugged []entities.Document
+	if err := db.Unscoped().Where("slug IS NULL OR slug = ''").Order("created_at").Find(&unslugged).Error; err != nil {
+		panic("Error while reading documents without slug: " + err.Error())
+	}
+	for _, d := range unslugged {
+		base := utils.Slugify(d.Title)
+		if base == "" {
+			base = constant.DEFAULT_SLUG
+		}
+		for n := 1; ; n++ {
+			slug := utils.SlugCandidate(base, n)
+			var count int64
+			if err := db.Unscoped().Model(&entities.Document{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+				panic("Error while generating document slug: " + err.Error())
+			}
+			if count > 0 {
+				continue
+			}
+			if err := db.Unscoped().Model(&d).UpdateColumn("slug", slug).Error; err != nil {
+				panic("Error while generating document slug: " + err.Error())
+			}
+			break
+		}
+	}
 
 	// init data
 	var roles []entities.Role = []entities.Role{
@@ -44,9 +182,9 @@ func main() {
 	}
 
 	var documentTypes []entities.DocumentType = []entities.DocumentType{
-		{ID: constant.ANNOUNCEMENT},
-		{ID: constant.BUDGET},
-		{ID: constant.STATISTIC},
+		{ID: constant.ANNOUNCEMENT, NameTH: "ประกาศ", NameEN: "Announcement", SortOrder: 1, RequiresReview: true},
+		{ID: constant.BUDGET, NameTH: "งบประมาณ", NameEN: "Budget", SortOrder: 2, RequiresReview: true},
+		{ID: constant.STATISTIC, NameTH: "สถิติ", NameEN: "Statistic", SortOrder: 3},
 	}
 
 	var attachmentTypes []entities.AttachmentType = []entities.AttachmentType{
@@ -69,14 +207,23 @@ func main() {
 	}
 
 	var document entities.Document = entities.Document{
-		ID:      fmt.Sprintf("DOC-%v", utils.GenerateRandomString("0123456789", 8)),
-		Title:   "Title",
-		Content: "lorem lorem lorem lorem lorem lorem lorem",
-		Banner:  nil,
-		Cover:   nil,
-		UserID:  user.ID,
-		TypeID:  constant.ANNOUNCEMENT,
+		ID:     fmt.Sprintf("DOC-%v", utils.GenerateRandomString("0123456789", 8)),
+		Title:  "Title",
+		Slug:   "title",
+		Locale: constant.DEFAULT_LOCALE,
+		Source: "lorem lorem lorem lorem lorem lorem lorem",
+		Format: constant.CONTENT_MARKDOWN,
+		Banner: nil,
+		Cover:  nil,
+		UserID: user.ID,
+		TypeID: constant.ANNOUNCEMENT,
+		Status: constant.PUBLISHED,
 	}
+	document.Content = utils.RenderContent(document.Format, document.Source)
+	document.AutoExcerpt = utils.Excerpt(utils.PlainText(document.Content), constant.EXCERPT_SIZE)
+	document.ReadingTime = utils.ReadingTime(utils.PlainText(document.Content))
+	document.SearchTitle = utils.SearchText(document.Title)
+	document.SearchBody = utils.SearchText(utils.PlainText(document.Content))
 
 	// migrate init data
 	if err := db.Table("roles").Create(&roles).Error; err != nil {
@@ -94,6 +241,18 @@ func main() {
 	if err := db.Table("documents").Create(&document).Error; err != nil {
 		panic("Error while migrating documents data: " + err.Error())
 	}
+	if err := db.Table("document_revisions").Create(&entities.DocumentRevision{
+		ID:         fmt.Sprintf("REV-%v", utils.GenerateRandomString("0123456789", 8)),
+		DocumentID: document.ID,
+		Revision:   1,
+		Title:      document.Title,
+		Content:    document.Content,
+		Source:     document.Source,
+		Format:     document.Format,
+		EditorID:   user.ID,
+	}).Error; err != nil {
+		panic("Error while migrating document_revisions data: " + err.Error())
+	}
 
 	fmt.Println("migration successful")
 }
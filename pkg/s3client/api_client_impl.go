@@ -0,0 +1,245 @@
+package s3client
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+)
+
+// filesPath is where the API serves the files of the local and memory drivers
+const filesPath = "/api/v1/files"
+
+// objectMeta is what a blob store keeps next to the content of an object
+type objectMeta struct {
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"content_type"`
+	Public       bool      `json:"public"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+func (m *objectMeta) info() *ObjectInfo {
+	return &ObjectInfo{Size: m.Size, ContentType: m.ContentType, LastModified: m.LastModified}
+}
+
+// blobStore keeps the objects of a driver whose files are served through the API
+type blobStore interface {
+	put(bucketName, objectKey string, body io.Reader, meta objectMeta) error
+	open(bucketName, objectKey string) (io.ReadCloser, *objectMeta, error)
+	stat(bucketName, objectKey string) (*objectMeta, error)
+	remove(bucketName, objectKey string) error
+	list(bucketName string, fn func(objectKey string, meta *objectMeta) error) error
+}
+
+// apiClient implements S3Client on top of a blob store, presigned URLs point to the API and carry an HMAC signature
+type apiClient struct {
+	store   blobStore
+	baseURL string
+	key     []byte
+}
+
+func newAPIClient(cfg config.Config, store blobStore) *apiClient {
+	key := []byte(cfg.GetStorage().SigningKey)
+	if len(key) == 0 {
+		// signed URLs stop working on restart, which is fine for URLs that expire within the hour
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(fmt.Sprintf("Failed to create storage signing key: %v", err))
+		}
+	}
+
+	return &apiClient{
+		store:   store,
+		baseURL: strings.TrimSuffix(cfg.GetServer().Url, "/") + filesPath,
+		key:     key,
+	}
+}
+
+func (c *apiClient) UploadFile(bucketName, objectKey, contentType string, body io.Reader, public bool) error {
+	if err := validateObjectKey(bucketName, objectKey); err != nil {
+		return err
+	}
+	if err := c.store.put(bucketName, objectKey, body, objectMeta{ContentType: contentType, Public: public, LastModified: time.Now()}); err != nil {
+		return fmt.Errorf("failed to upload file, %v", err)
+	}
+	return nil
+}
+
+func (c *apiClient) DownloadFile(bucketName, objectKey, filePath string) error {
+	body, _, err := c.openFile(bucketName, objectKey)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s, %v", filePath, err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, body); err != nil {
+		return fmt.Errorf("failed to write file, %v", err)
+	}
+	return nil
+}
+
+// DeleteFile removes an object, one that is already gone counts as deleted
+func (c *apiClient) DeleteFile(bucketName, objectKey string) error {
+	if err := validateObjectKey(bucketName, objectKey); err != nil {
+		return err
+	}
+	if err := c.store.remove(bucketName, objectKey); err != nil && !errors.Is(err, ErrObjectNotFound) {
+		return fmt.Errorf("failed to delete object, %v", err)
+	}
+	return nil
+}
+
+func (c *apiClient) StatFile(bucketName, objectKey string) (*ObjectInfo, error) {
+	if err := validateObjectKey(bucketName, objectKey); err != nil {
+		return nil, err
+	}
+	meta, err := c.store.stat(bucketName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return meta.info(), nil
+}
+
+func (c *apiClient) ReadFileHead(bucketName, objectKey string, length int64) ([]byte, error) {
+	body, _, err := c.openFile(bucketName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	head, err := io.ReadAll(io.LimitReader(body, length))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object, %v", err)
+	}
+	return head, nil
+}
+
+func (c *apiClient) ReadFile(bucketName, objectKey string) (io.ReadCloser, error) {
+	body, _, err := c.openFile(bucketName, objectKey)
+	return body, err
+}
+
+func (c *apiClient) ListFiles(bucketName string, fn func(objectKey string, info *ObjectInfo) error) error {
+	return c.store.list(bucketName, func(objectKey string, meta *objectMeta) error {
+		return fn(objectKey, meta.info())
+	})
+}
+
+// ObjectURL is where the API serves a public object
+func (c *apiClient) ObjectURL(bucketName, objectKey string) string {
+	return fmt.Sprintf("%s/%s/%s", c.baseURL, url.PathEscape(bucketName), escapeObjectKey(objectKey))
+}
+
+func (c *apiClient) PresignGetURL(bucketName, objectKey string, expiry time.Duration) (string, error) {
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"signature": {c.sign("GET", bucketName, objectKey, "", "", expires)},
+	}
+	return c.ObjectURL(bucketName, objectKey) + "?" + query.Encode(), nil
+}
+
+func (c *apiClient) PresignPutURL(bucketName, objectKey, contentType string, public bool, expiry time.Duration) (string, map[string]string, error) {
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"public":    {strconv.FormatBool(public)},
+		"signature": {c.sign("PUT", bucketName, objectKey, contentType, strconv.FormatBool(public), expires)},
+	}
+	headers := map[string]string{"Content-Type": contentType}
+	return c.ObjectURL(bucketName, objectKey) + "?" + query.Encode(), headers, nil
+}
+
+func (c *apiClient) OpenFile(bucketName, objectKey string, query url.Values) (io.ReadCloser, *ObjectInfo, error) {
+	if err := validateObjectKey(bucketName, objectKey); err != nil {
+		return nil, nil, err
+	}
+	meta, err := c.store.stat(bucketName, objectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !meta.Public && !c.verify(query, "GET", bucketName, objectKey, "", "") {
+		return nil, nil, ErrAccessDenied
+	}
+	return c.openFile(bucketName, objectKey)
+}
+
+func (c *apiClient) ReceiveFile(bucketName, objectKey, contentType string, query url.Values, body io.Reader) error {
+	if err := validateObjectKey(bucketName, objectKey); err != nil {
+		return err
+	}
+	public := query.Get("public")
+	if !c.verify(query, "PUT", bucketName, objectKey, contentType, public) {
+		return ErrAccessDenied
+	}
+	return c.UploadFile(bucketName, objectKey, contentType, body, public == "true")
+}
+
+func (c *apiClient) openFile(bucketName, objectKey string) (io.ReadCloser, *ObjectInfo, error) {
+	if err := validateObjectKey(bucketName, objectKey); err != nil {
+		return nil, nil, err
+	}
+	body, meta, err := c.store.open(bucketName, objectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, meta.info(), nil
+}
+
+func (c *apiClient) sign(parts ...string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks the signature of a presigned URL and that it has not expired
+func (c *apiClient) verify(query url.Values, method, bucketName, objectKey, contentType, public string) bool {
+	expires := query.Get("expires")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+	expected := c.sign(method, bucketName, objectKey, contentType, public, expires)
+	return hmac.Equal([]byte(expected), []byte(query.Get("signature")))
+}
+
+// validateObjectKey keeps keys inside their bucket, they become paths of the local driver
+func validateObjectKey(bucketName, objectKey string) error {
+	if bucketName == "" || strings.HasPrefix(bucketName, ".") || strings.ContainsAny(bucketName, "/\\\x00") {
+		return ErrInvalidObjectKey
+	}
+	if objectKey == "" || strings.ContainsAny(objectKey, "\\\x00") {
+		return ErrInvalidObjectKey
+	}
+	for _, segment := range strings.Split(objectKey, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidObjectKey
+		}
+	}
+	return nil
+}
+
+// escapeObjectKey escapes each segment of a key, keeping the slashes between them
+func escapeObjectKey(objectKey string) string {
+	segments := strings.Split(objectKey, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
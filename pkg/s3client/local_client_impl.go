@@ -0,0 +1,156 @@
+package s3client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+)
+
+// NewLocalClient keeps files in a directory on disk and serves them through the API
+func NewLocalClient(cfg config.Config) *apiClient {
+	dir := cfg.GetStorage().LocalDir
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		panic(fmt.Sprintf("Failed to create storage directory: %v", err))
+	}
+
+	return newAPIClient(cfg, &localStore{dir: dir})
+}
+
+// localStore keeps an object at dir/bucket/key and its metadata at dir/.meta/bucket/key.json
+type localStore struct {
+	dir string
+}
+
+func (s *localStore) put(bucketName, objectKey string, body io.Reader, meta objectMeta) error {
+	path := s.path(bucketName, objectKey)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// write next to the target and rename, so a reader never sees half a file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	meta.Size = size
+	if err := s.writeMeta(bucketName, objectKey, &meta); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *localStore) open(bucketName, objectKey string) (io.ReadCloser, *objectMeta, error) {
+	meta, err := s.stat(bucketName, objectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(s.path(bucketName, objectKey))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, ErrObjectNotFound
+		}
+		return nil, nil, err
+	}
+	return file, meta, nil
+}
+
+func (s *localStore) stat(bucketName, objectKey string) (*objectMeta, error) {
+	data, err := os.ReadFile(s.metaPath(bucketName, objectKey))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	var meta objectMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// remove deletes the metadata first, the object is gone as soon as it has no metadata
+func (s *localStore) remove(bucketName, objectKey string) error {
+	metaErr := os.Remove(s.metaPath(bucketName, objectKey))
+	if metaErr != nil && !errors.Is(metaErr, fs.ErrNotExist) {
+		return metaErr
+	}
+
+	fileErr := os.Remove(s.path(bucketName, objectKey))
+	if fileErr != nil && !errors.Is(fileErr, fs.ErrNotExist) {
+		return fileErr
+	}
+
+	if metaErr != nil && fileErr != nil {
+		return ErrObjectNotFound
+	}
+	return nil
+}
+
+// list walks the metadata of the bucket, every object has exactly one metadata file
+func (s *localStore) list(bucketName string, fn func(objectKey string, meta *objectMeta) error) error {
+	root := filepath.Join(s.dir, ".meta", bucketName)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, strings.TrimSuffix(path, ".json"))
+		if err != nil {
+			return err
+		}
+		objectKey := filepath.ToSlash(rel)
+		meta, err := s.stat(bucketName, objectKey)
+		if err != nil {
+			return err
+		}
+		return fn(objectKey, meta)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *localStore) writeMeta(bucketName, objectKey string, meta *objectMeta) error {
+	path := s.metaPath(bucketName, objectKey)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (s *localStore) path(bucketName, objectKey string) string {
+	return filepath.Join(s.dir, bucketName, filepath.FromSlash(objectKey))
+}
+
+func (s *localStore) metaPath(bucketName, objectKey string) string {
+	return filepath.Join(s.dir, ".meta", bucketName, filepath.FromSlash(objectKey)+".json")
+}
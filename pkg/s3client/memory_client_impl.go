@@ -0,0 +1,92 @@
+package s3client
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+)
+
+// NewMemoryClient keeps files in memory and serves them through the API, for tests and trying the server out
+func NewMemoryClient(cfg config.Config) *apiClient {
+	return newAPIClient(cfg, &memoryStore{objects: make(map[string]*memoryObject)})
+}
+
+type memoryObject struct {
+	data []byte
+	meta objectMeta
+}
+
+type memoryStore struct {
+	mu      sync.RWMutex
+	objects map[string]*memoryObject // by bucket/key
+}
+
+func (s *memoryStore) put(bucketName, objectKey string, body io.Reader, meta objectMeta) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	meta.Size = int64(len(data))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[bucketName+"/"+objectKey] = &memoryObject{data: data, meta: meta}
+	return nil
+}
+
+func (s *memoryStore) open(bucketName, objectKey string) (io.ReadCloser, *objectMeta, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	object, ok := s.objects[bucketName+"/"+objectKey]
+	if !ok {
+		return nil, nil, ErrObjectNotFound
+	}
+	meta := object.meta
+	return io.NopCloser(bytes.NewReader(object.data)), &meta, nil
+}
+
+func (s *memoryStore) stat(bucketName, objectKey string) (*objectMeta, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	object, ok := s.objects[bucketName+"/"+objectKey]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	meta := object.meta
+	return &meta, nil
+}
+
+func (s *memoryStore) remove(bucketName, objectKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objects[bucketName+"/"+objectKey]; !ok {
+		return ErrObjectNotFound
+	}
+	delete(s.objects, bucketName+"/"+objectKey)
+	return nil
+}
+
+func (s *memoryStore) list(bucketName string, fn func(objectKey string, meta *objectMeta) error) error {
+	s.mu.RLock()
+	metas := make(map[string]objectMeta)
+	for key, object := range s.objects {
+		if objectKey, ok := strings.CutPrefix(key, bucketName+"/"); ok {
+			metas[objectKey] = object.meta
+		}
+	}
+	s.mu.RUnlock()
+
+	// fn runs without the lock, so it may call back into the store
+	for objectKey, meta := range metas {
+		if err := fn(objectKey, &meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,9 +1,67 @@
 package s3client
 
-import "bytes"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+)
+
+// ErrObjectNotFound is returned by StatFile for an object the bucket does not have
+var ErrObjectNotFound = errors.New("object not found")
+
+var (
+	ErrAccessDenied     = errors.New("access denied")
+	ErrInvalidObjectKey = errors.New("invalid object key")
+)
+
+// storage drivers
+const (
+	DRIVER_S3     = "s3"     // any S3-compatible service
+	DRIVER_LOCAL  = "local"  // files on disk, served through the API
+	DRIVER_MEMORY = "memory" // files in memory, served through the API, lost on restart
+)
 
 type S3Client interface {
-	UploadFile(bucketName, objectKey string, buffer *bytes.Reader) error
+	UploadFile(bucketName, objectKey, contentType string, body io.Reader, public bool) error
 	DownloadFile(bucketName, objectKey, filePath string) error
 	DeleteFile(bucketName, objectKey string) error
+	StatFile(bucketName, objectKey string) (*ObjectInfo, error)
+	ReadFileHead(bucketName, objectKey string, length int64) ([]byte, error)
+	ReadFile(bucketName, objectKey string) (io.ReadCloser, error)
+	ListFiles(bucketName string, fn func(objectKey string, info *ObjectInfo) error) error
+	ObjectURL(bucketName, objectKey string) string
+	PresignGetURL(bucketName, objectKey string, expiry time.Duration) (string, error)
+	PresignPutURL(bucketName, objectKey, contentType string, public bool, expiry time.Duration) (string, map[string]string, error)
+}
+
+// FileServer is implemented by drivers whose files are served through the API instead of by the storage itself
+type FileServer interface {
+	// OpenFile opens a public object, or a private one with a presigned GET query
+	OpenFile(bucketName, objectKey string, query url.Values) (io.ReadCloser, *ObjectInfo, error)
+	// ReceiveFile stores an object uploaded with a presigned PUT query
+	ReceiveFile(bucketName, objectKey, contentType string, query url.Values, body io.Reader) error
+}
+
+type ObjectInfo struct {
+	Size         int64
+	ContentType  string // empty when listed
+	LastModified time.Time
+}
+
+// NewStorage creates the client of the configured storage driver
+func NewStorage(cfg config.Config) S3Client {
+	switch cfg.GetStorage().Driver {
+	case DRIVER_S3:
+		return NewS3Client(cfg)
+	case DRIVER_LOCAL:
+		return NewLocalClient(cfg)
+	case DRIVER_MEMORY:
+		return NewMemoryClient(cfg)
+	default:
+		panic(fmt.Sprintf("Unknown storage driver: %s", cfg.GetStorage().Driver))
+	}
 }
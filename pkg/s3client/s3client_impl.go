@@ -1,24 +1,40 @@
 package s3client
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// uploads larger than one part are sent as a multipart upload,
+// at most partSize * uploadConcurrency bytes of a body are held in memory at once
+const (
+	partSize          int64 = 8 * 1024 * 1024
+	uploadConcurrency int   = 2
 )
 
 type s3Client struct {
-	client *s3.S3
+	client    *s3.S3
+	uploader  *s3manager.Uploader
+	endpoint  string
+	pathStyle bool
 }
 
-// NewS3Client initializes a new S3 client using AWS SDK v1
+// NewS3Client initializes a new client of an S3-compatible service using AWS SDK v1
 func NewS3Client(cfg config.Config) *s3Client {
 	// Get credentials from environment variables
 	accessKey := cfg.GetAws().AccessKeyId
@@ -36,8 +52,8 @@ func NewS3Client(cfg config.Config) *s3Client {
 	sess, err := session.NewSession(&aws.Config{
 		Region:           aws.String(region),
 		Credentials:      creds,
-		Endpoint:         aws.String("https://storage.googleapis.com"),
-		S3ForcePathStyle: aws.Bool(true),
+		Endpoint:         aws.String(cfg.GetAws().Endpoint),
+		S3ForcePathStyle: aws.Bool(cfg.GetAws().PathStyle),
 	})
 	if err != nil {
 		panic(fmt.Sprintf("Failed to create session: %v", err))
@@ -46,23 +62,62 @@ func NewS3Client(cfg config.Config) *s3Client {
 	// Create the S3 client
 	client := s3.New(sess)
 
+	uploader := s3manager.NewUploaderWithClient(client, func(u *s3manager.Uploader) {
+		u.PartSize = partSize
+		u.Concurrency = uploadConcurrency
+	})
+
 	return &s3Client{
-		client: client,
+		client:    client,
+		uploader:  uploader,
+		endpoint:  strings.TrimSuffix(cfg.GetAws().Endpoint, "/"),
+		pathStyle: cfg.GetAws().PathStyle,
+	}
+}
+
+// UploadFile streams an object to the bucket, a public one is readable by anyone at ObjectURL and a private one keeps the bucket's default ACL.
+// A failed multipart upload is aborted so no parts are left behind.
+func (c *s3Client) UploadFile(bucketName, objectKey, contentType string, body io.Reader, public bool) error {
+	input := &s3manager.UploadInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(objectKey),
+		ContentType: aws.String(contentType),
+		Body:        body,
+	}
+	if public {
+		input.ACL = aws.String(s3.ObjectCannedACLPublicRead)
+	}
+
+	if _, err := c.uploader.Upload(input); err != nil {
+		return fmt.Errorf("failed to upload file, %v", err)
 	}
+
+	return nil
 }
 
-func (c *s3Client) UploadFile(bucketName, objectKey string, buffer *bytes.Reader) error {
-	_, err := c.client.PutObject(&s3.PutObjectInput{
+// ObjectURL is the stable URL of a public object
+func (c *s3Client) ObjectURL(bucketName, objectKey string) string {
+	if c.pathStyle {
+		return fmt.Sprintf("%s/%s/%s", c.endpoint, url.PathEscape(bucketName), escapeObjectKey(objectKey))
+	}
+
+	scheme, host, _ := strings.Cut(c.endpoint, "://")
+	return fmt.Sprintf("%s://%s.%s/%s", scheme, bucketName, host, escapeObjectKey(objectKey))
+}
+
+// PresignGetURL signs a URL that downloads the object until expiry has passed, whatever its ACL
+func (c *s3Client) PresignGetURL(bucketName, objectKey string, expiry time.Duration) (string, error) {
+	req, _ := c.client.GetObjectRequest(&s3.GetObjectInput{
 		Bucket: aws.String(bucketName),
 		Key:    aws.String(objectKey),
-		Body:   buffer,
-		ACL:    aws.String(s3.ObjectCannedACLPublicRead),
 	})
+
+	signed, err := req.Presign(expiry)
 	if err != nil {
-		return fmt.Errorf("failed to upload file, %v", err)
+		return "", fmt.Errorf("failed to presign url, %v", err)
 	}
 
-	return nil
+	return signed, nil
 }
 
 func (c *s3Client) DownloadFile(bucketName, objectKey, filePath string) error {
@@ -89,14 +144,129 @@ func (c *s3Client) DownloadFile(bucketName, objectKey, filePath string) error {
 	return nil
 }
 
+// DeleteFile removes an object, one that is already gone counts as deleted
 func (c *s3Client) DeleteFile(bucketName, objectKey string) error {
 	_, err := c.client.DeleteObject(&s3.DeleteObjectInput{
 		Bucket: aws.String(bucketName),
 		Key:    aws.String(objectKey),
 	})
-	if err != nil {
+	if err != nil && !isNotFound(err) {
 		return fmt.Errorf("failed to delete object, %v", err)
 	}
 
 	return nil
 }
+
+// StatFile reads the size and content type of a stored object
+func (c *s3Client) StatFile(bucketName, objectKey string) (*ObjectInfo, error) {
+	result, err := c.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to stat object, %v", err)
+	}
+
+	return &ObjectInfo{
+		Size:         aws.Int64Value(result.ContentLength),
+		ContentType:  aws.StringValue(result.ContentType),
+		LastModified: aws.TimeValue(result.LastModified),
+	}, nil
+}
+
+// ReadFileHead reads up to length leading bytes of a stored object
+func (c *s3Client) ReadFileHead(bucketName, objectKey string, length int64) ([]byte, error) {
+	result, err := c.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", length-1)),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get object, %v", err)
+	}
+	defer result.Body.Close()
+
+	head, err := io.ReadAll(io.LimitReader(result.Body, length))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object, %v", err)
+	}
+
+	return head, nil
+}
+
+// ReadFile streams a whole object, the caller closes it
+func (c *s3Client) ReadFile(bucketName, objectKey string) (io.ReadCloser, error) {
+	result, err := c.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get object, %v", err)
+	}
+	return result.Body, nil
+}
+
+// ListFiles calls fn with every object in the bucket, stopping at the first error fn returns
+func (c *s3Client) ListFiles(bucketName string, fn func(objectKey string, info *ObjectInfo) error) error {
+	var fnErr error
+	err := c.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			info := &ObjectInfo{Size: aws.Int64Value(object.Size), LastModified: aws.TimeValue(object.LastModified)}
+			if fnErr = fn(aws.StringValue(object.Key), info); fnErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list objects, %v", err)
+	}
+	return nil
+}
+
+// PresignPutURL signs a URL that uploads the object until expiry has passed.
+// The client has to send the returned headers with the upload, they are part of the signature.
+func (c *s3Client) PresignPutURL(bucketName, objectKey, contentType string, public bool, expiry time.Duration) (string, map[string]string, error) {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(objectKey),
+		ContentType: aws.String(contentType),
+	}
+	if public {
+		input.ACL = aws.String(s3.ObjectCannedACLPublicRead)
+	}
+
+	req, _ := c.client.PutObjectRequest(input)
+	signed, signedHeaders, err := req.PresignRequest(expiry)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to presign url, %v", err)
+	}
+
+	headers := make(map[string]string, len(signedHeaders))
+	for name := range signedHeaders {
+		if name != "Host" {
+			headers[name] = signedHeaders.Get(name)
+		}
+	}
+
+	return signed, headers, nil
+}
+
+func isNotFound(err error) bool {
+	var reqErr awserr.RequestFailure
+	return errors.As(err, &reqErr) && reqErr.StatusCode() == http.StatusNotFound
+}
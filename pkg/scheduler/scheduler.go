@@ -0,0 +1,8 @@
+package scheduler
+
+import "time"
+
+type Scheduler interface {
+	Every(name string, interval time.Duration, job func())
+	Stop()
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type scheduler struct {
+	logger *zap.Logger
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler that runs background jobs on fixed intervals
+func NewScheduler(logger *zap.Logger) Scheduler {
+	return &scheduler{
+		logger: logger,
+		quit:   make(chan struct{}),
+	}
+}
+
+// Every runs job once right away and then every interval until Stop is called.
+// Runs of the same job never overlap.
+func (s *scheduler) Every(name string, interval time.Duration, job func()) {
+	if interval <= 0 {
+		s.logger.Named(name).Warn("Job disabled: interval must be positive", zap.Duration("interval", interval))
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.run(name, job)
+
+			select {
+			case <-ticker.C:
+			case <-s.quit:
+				return
+			}
+		}
+	}()
+
+	s.logger.Named(name).Info("Job scheduled", zap.Duration("interval", interval))
+}
+
+func (s *scheduler) run(name string, job func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Named(name).Error("Job panicked", zap.Any("panic", r))
+		}
+	}()
+
+	job()
+}
+
+// Stop signals every job to exit and waits for running jobs to finish
+func (s *scheduler) Stop() {
+	close(s.quit)
+	s.wg.Wait()
+}
@@ -0,0 +1,22 @@
+package tusstore
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+var (
+	ErrUploadNotFound = errors.New("upload not found")
+	ErrChunkTooLarge  = errors.New("chunk goes past the end of the upload")
+)
+
+// TusStore keeps the bytes of resumable uploads on the server's disk until the last chunk arrives
+type TusStore interface {
+	Create(ID string) error
+	// Append writes body at offset, dropping anything an interrupted chunk left past it.
+	// It keeps at most limit bytes and returns how many it kept, also when the body breaks off part way.
+	Append(ID string, offset int64, body io.Reader, limit int64) (int64, error)
+	Open(ID string) (*os.File, error)
+	Remove(ID string) error
+}
@@ -0,0 +1,105 @@
+package tusstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/isd-sgcu/sucu-backend-2024/pkg/config"
+)
+
+type tusStore struct {
+	dir string
+}
+
+// NewTusStore keeps uploads in the configured directory, one file per upload
+func NewTusStore(cfg config.Config) TusStore {
+	dir := cfg.GetStorage().TusDir
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		panic(fmt.Sprintf("Failed to create tus upload directory: %v", err))
+	}
+
+	return &tusStore{dir: dir}
+}
+
+func (s *tusStore) Create(ID string) error {
+	path, err := s.path(ID)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func (s *tusStore) Append(ID string, offset int64, body io.Reader, limit int64) (int64, error) {
+	path, err := s.path(ID)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, ErrUploadNotFound
+		}
+		return 0, err
+	}
+	defer file.Close()
+
+	if err := file.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	// one byte more than allowed tells a chunk that is too large
+	written, err := io.Copy(file, io.LimitReader(body, limit+1))
+	if written > limit {
+		if err := file.Truncate(offset + limit); err != nil {
+			return 0, err
+		}
+		return limit, ErrChunkTooLarge
+	}
+	return written, err
+}
+
+func (s *tusStore) Open(ID string) (*os.File, error) {
+	path, err := s.path(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrUploadNotFound
+	}
+	return file, err
+}
+
+// Remove deletes an upload, one that is already gone counts as removed
+func (s *tusStore) Remove(ID string) error {
+	path, err := s.path(ID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *tusStore) path(ID string) (string, error) {
+	if ID == "" || ID != filepath.Base(ID) || ID[0] == '.' {
+		return "", ErrUploadNotFound
+	}
+	return filepath.Join(s.dir, ID), nil
+}
@@ -1,11 +1,83 @@
 package constant
 
+import "time"
+
 const (
 	IMAGE         string = "IMAGE"
 	DOCS          string = "DOCS"
 	MAX_FILE_SIZE int64  = 32 * 1024 * 1024 // max 32 MB per file
+
+	SNIFF_SIZE   int64 = 512             // leading bytes read to recognize a file
+	MAX_SVG_SIZE int64 = 2 * 1024 * 1024 // svgs are read whole to be checked for script
+
+	MAX_BODY_SIZE           int = 4 * 1024 * 1024                                 // requests other than uploads
+	MAX_UPLOAD_REQUEST_SIZE int = MAX_UPLOAD_FILES*int(MAX_FILE_SIZE) + 1024*1024 // a full multipart upload with room for the form fields
+)
+
+// attachment visibility
+const (
+	ATTACHMENT_PUBLIC  string = "PUBLIC"  // world-readable object with a stable URL
+	ATTACHMENT_PRIVATE string = "PRIVATE" // only reachable through a presigned URL
+
+	ATTACHMENT_URL_EXPIRY time.Duration = 15 * time.Minute // lifetime of a presigned URL
+)
+
+// attachment state, a row is pending while its object is being uploaded
+const (
+	ATTACHMENT_PENDING   string = "PENDING"
+	ATTACHMENT_COMMITTED string = "COMMITTED"
+
+	RECONCILE_GRACE_PERIOD time.Duration = time.Hour // objects and pending rows younger than this may belong to an upload in progress
+)
+
+// resumable upload, tus 1.0
+const (
+	TUS_VERSION       string        = "1.0.0"
+	TUS_EXTENSIONS    string        = "creation,expiration"
+	TUS_CONTENT_TYPE  string        = "application/offset+octet-stream" // content type of a chunk
+	TUS_UPLOAD_EXPIRY time.Duration = 24 * time.Hour                    // an upload that gets no chunk for this long is removed
+)
+
+// deduplication
+const (
+	MOST_SHARED_OBJECTS int = 10 // shared objects listed in the storage savings report
+)
+
+// direct upload
+const (
+	ATTACHMENT_UPLOAD_EXPIRY time.Duration = time.Hour // time to upload and complete before the upload is cleaned up
+	MAX_UPLOAD_FILES         int           = 10        // files per upload request
 )
 
+// image variants
+const (
+	VARIANT_NONE    string = "NONE"    // not an image that variants are made of
+	VARIANT_PENDING string = "PENDING" // waiting for the variant job
+	VARIANT_READY   string = "READY"
+	VARIANT_FAILED  string = "FAILED"
+
+	VARIANT_BATCH_SIZE        int   = 10         // images resized per job run
+	MAX_VARIANT_SOURCE_PIXELS int64 = 50_000_000 // larger images are not decoded
+	VARIANT_JPEG_QUALITY      int   = 85
+
+	VARIANT_WEBP         string  = "webp" // format every image also gets variants in
+	VARIANT_WEBP_QUALITY float32 = 80
+)
+
+// ImageVariantWidths are the widths an image is resized to, widths not smaller than the original are skipped
+var ImageVariantWidths = [...]int{320, 768, 1600}
+
+// VariantContentTypes are the images variants are made of, each width comes in the original format and in WebP
+var VariantContentTypes = [...]string{
+	"image/jpeg",
+	"image/png",
+}
+
+var AttachmentVisibilities = [...]string{
+	ATTACHMENT_PUBLIC,
+	ATTACHMENT_PRIVATE,
+}
+
 var AllowedImageFileTypes = [...]string{
 	".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp", ".tiff", ".svg",
 }
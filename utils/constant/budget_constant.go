@@ -0,0 +1,36 @@
+package constant
+
+// budget currency, ISO 4217 codes
+const (
+	THB string = "THB"
+	USD string = "USD"
+	EUR string = "EUR"
+
+	DEFAULT_BUDGET_CURRENCY  string = THB
+	BUDGET_CURRENCY_DECIMALS int    = 2 // amounts are stored in minor units, e.g. satang
+)
+
+var BudgetCurrencies = [...]string{
+	THB,
+	USD,
+	EUR,
+}
+
+// budget limits
+const (
+	MIN_FISCAL_YEAR int = 2500 // Buddhist era
+	MAX_FISCAL_YEAR int = 2700
+
+	MAX_BUDGET_ITEMS       int   = 1000
+	MAX_BUDGET_AMOUNT      int64 = 1_000_000_000_000_000 // minor units per amount, keeps every total within int64
+	MAX_BUDGET_IMPORT_SIZE int64 = 2 * 1024 * 1024       // bytes of an imported CSV or XLSX file
+	MAX_SHEET_PART_SIZE    int64 = 64 * 1024 * 1024      // unzipped bytes read from one part of an XLSX file
+)
+
+// BudgetImportColumns are the header names of an imported budget file, actual may be left out
+var BudgetImportColumns = [...]string{
+	"category",
+	"description",
+	"planned",
+	"actual",
+}
@@ -0,0 +1,21 @@
+package constant
+
+// format of document content as sent by the client, see utils.RenderContent
+const (
+	CONTENT_MARKDOWN string = "markdown"
+	CONTENT_HTML     string = "html" // whitelisted subset, anything else is stripped
+
+	DEFAULT_CONTENT_FORMAT string = CONTENT_MARKDOWN
+)
+
+var ContentFormats = [...]string{
+	CONTENT_MARKDOWN,
+	CONTENT_HTML,
+}
+
+// text derived from the rendered content
+const (
+	EXCERPT_SIZE             int = 200 // runes in a generated excerpt
+	READING_WORDS_PER_MINUTE int = 200
+	THAI_RUNES_PER_WORD      int = 6 // Thai has no spaces to count words by
+)
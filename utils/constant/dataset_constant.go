@@ -0,0 +1,45 @@
+package constant
+
+// dataset limits
+const (
+	MAX_DATASET_SERIES      int   = 50
+	MAX_DATASET_POINTS      int   = 50_000 // over every series
+	MAX_DATASET_DIMENSIONS  int   = 10
+	MAX_DATASET_IMPORT_SIZE int64 = 2 * 1024 * 1024 // bytes of an imported CSV or XLSX file
+
+	DEFAULT_DATASET_SERIES string = "value" // name of the series of an import without a series column
+)
+
+// columns of an imported dataset file with a meaning of their own, every other column is a dimension
+const (
+	DATASET_SERIES string = "series"
+	DATASET_LABEL  string = "label" // can also be grouped and filtered by like a dimension
+	DATASET_VALUE  string = "value"
+	DATASET_UNIT   string = "unit"
+)
+
+var DatasetReservedColumns = [...]string{
+	DATASET_SERIES,
+	DATASET_LABEL,
+	DATASET_VALUE,
+	DATASET_UNIT,
+}
+
+// dataset aggregation
+const (
+	AGGREGATE_SUM   string = "sum"
+	AGGREGATE_AVG   string = "avg"
+	AGGREGATE_MIN   string = "min"
+	AGGREGATE_MAX   string = "max"
+	AGGREGATE_COUNT string = "count"
+
+	DEFAULT_AGGREGATE string = AGGREGATE_SUM
+)
+
+var DatasetAggregates = [...]string{
+	AGGREGATE_SUM,
+	AGGREGATE_AVG,
+	AGGREGATE_MIN,
+	AGGREGATE_MAX,
+	AGGREGATE_COUNT,
+}
@@ -1,7 +1,135 @@
 package constant
 
+// document type
 const (
 	ANNOUNCEMENT string = "ANNOUNCEMENT"
 	BUDGET       string = "BUDGET"
 	STATISTIC    string = "STATISTIC"
+
+	MAX_DOC_TYPE_ID_LENGTH int = 100
+)
+
+// document ID and slug
+const (
+	MAX_ID_ATTEMPTS   int    = 5          // random document IDs tried before giving up
+	MAX_SLUG_LENGTH   int    = 80         // runes kept from the title
+	MAX_SLUG_ATTEMPTS int    = 50         // numbered suffixes tried before falling back to a random one
+	DEFAULT_SLUG      string = "document" // used when a title has nothing to keep
+)
+
+// document status
+const (
+	DRAFT             string = "DRAFT"
+	PENDING           string = "PENDING"
+	PUBLISHED         string = "PUBLISHED"
+	REJECTED          string = "REJECTED"
+	CHANGES_REQUESTED string = "CHANGES_REQUESTED"
+	ARCHIVED          string = "ARCHIVED" // published document past its expiry, see documentUsecase.ArchiveExpiredDocuments
+)
+
+// document review action
+const (
+	SUBMIT          string = "SUBMIT"
+	APPROVE         string = "APPROVE"
+	REJECT          string = "REJECT"
+	REQUEST_CHANGES string = "REQUEST_CHANGES"
+)
+
+// DocumentTransitions maps review action -> current status -> next status
+var DocumentTransitions = map[string]map[string]string{
+	SUBMIT: {
+		DRAFT:             PENDING,
+		REJECTED:          PENDING,
+		CHANGES_REQUESTED: PENDING,
+	},
+	APPROVE: {
+		PENDING: PUBLISHED,
+	},
+	REJECT: {
+		PENDING: REJECTED,
+	},
+	REQUEST_CHANGES: {
+		PENDING: CHANGES_REQUESTED,
+	},
+}
+
+// document link type
+const (
+	LINK_AMENDS     string = "AMENDS"
+	LINK_SUPERSEDES string = "SUPERSEDES"
+	LINK_RELATED    string = "RELATED"
+	LINK_FOLLOW_UP  string = "FOLLOW_UP"
+
+	MAX_DOCUMENT_LINKS int = 20 // outgoing links per document
+)
+
+var DocumentLinkTypes = [...]string{
+	LINK_AMENDS,
+	LINK_SUPERSEDES,
+	LINK_RELATED,
+	LINK_FOLLOW_UP,
+}
+
+// DocumentLinkInverse names a link type as seen from the linked document
+var DocumentLinkInverse = map[string]string{
+	LINK_AMENDS:     "AMENDED_BY",
+	LINK_SUPERSEDES: "SUPERSEDED_BY",
+	LINK_RELATED:    LINK_RELATED,
+	LINK_FOLLOW_UP:  "FOLLOWED_UP_BY",
+}
+
+// bulk document operation
+const (
+	BULK_DELETE      string = "delete"
+	BULK_RESTORE     string = "restore"
+	BULK_PUBLISH     string = "publish"
+	BULK_ARCHIVE     string = "archive"
+	BULK_CHANGE_TYPE string = "change_type"
+	BULK_ADD_TAG     string = "add_tag"
+
+	BULK_ATOMIC       string = "atomic"      // every item or none
+	BULK_BEST_EFFORT  string = "best_effort" // every item that can be
+	DEFAULT_BULK_MODE string = BULK_ATOMIC
+
+	MAX_BULK_DOCUMENTS int = 100
+)
+
+var BulkOperations = [...]string{
+	BULK_DELETE,
+	BULK_RESTORE,
+	BULK_PUBLISH,
+	BULK_ARCHIVE,
+	BULK_CHANGE_TYPE,
+	BULK_ADD_TAG,
+}
+
+var BulkModes = [...]string{
+	BULK_ATOMIC,
+	BULK_BEST_EFFORT,
+}
+
+// built-in document types that the code relies on, they cannot be deleted
+var CoreDocTypes = [...]string{
+	ANNOUNCEMENT,
+	BUDGET,
+	STATISTIC,
+}
+
+// revision diff
+const (
+	DIFF_EQUAL  string = "equal"
+	DIFF_INSERT string = "insert"
+	DIFF_DELETE string = "delete"
+
+	MAX_DIFF_CELLS int = 4_000_000 // upper bound of the line LCS table
+)
+
+// full-text search
+const (
+	// DOCUMENT_SEARCH_VECTOR is the indexed tsvector of a document. Lexemes are produced by
+	// utils.SearchTokens, so they are taken as-is instead of going through a text search parser.
+	DOCUMENT_SEARCH_VECTOR string = "(setweight(array_to_tsvector(string_to_array(documents.search_title, ' ')), 'A') || array_to_tsvector(string_to_array(documents.search_body, ' ')))"
+
+	SNIPPET_SIZE       int = 160 // runes in a highlighted snippet
+	SNIPPET_LEAD_RATIO int = 4   // part of the snippet shown before the first match
 )
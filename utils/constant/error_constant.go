@@ -24,11 +24,195 @@ var (
 	ErrInsertDocumentFailed = "failed to insert document"
 	ErrUpdateDocumentFailed = "failed to update document"
 	ErrDeleteDocumentFailed = "failed to delete document"
+	ErrNotDocumentEditor    = "only the author or a superadmin of the author's organization can edit this document"
+	ErrGenerateDocumentID   = "failed to generate a unique document ID"
+	ErrInvalidContentFormat = "invalid content format"
+	ErrEmptyContent         = "content is empty once unsafe markup is removed"
+
+	// document slug error
+	ErrInvalidSlug              = "invalid slug"
+	ErrSlugAlreadyExists        = "slug is already used by another document"
+	ErrGenerateSlugFailed       = "failed to generate document slug"
+	ErrUpdateDocumentSlugFailed = "failed to update document slug"
+
+	// document type error
+	ErrDocumentTypeNotFound      = "document type not found"
+	ErrDocumentTypeAlreadyExists = "document type already exists"
+	ErrInvalidDocTypeID          = "document type ID must start with a letter and only contain letters, digits and underscores"
+	ErrDocumentTypeInUse         = "document type is still used by documents"
+	ErrCoreDocumentType          = "built-in document type cannot be deleted"
+	ErrGetDocumentTypeFailed     = "failed to get document type"
+	ErrInsertDocumentTypeFailed  = "failed to insert document type"
+	ErrUpdateDocumentTypeFailed  = "failed to update document type"
+	ErrDeleteDocumentTypeFailed  = "failed to delete document type"
+
+	// document translation error
+	ErrInvalidLocale           = "invalid locale"
+	ErrTranslationNotFound     = "translation not found"
+	ErrTranslationIsBaseLocale = "the base locale is edited through the document itself"
+	ErrGetTranslationFailed    = "failed to get document translations"
+	ErrUpdateTranslationFailed = "failed to update document translation"
+	ErrDeleteTranslationFailed = "failed to delete document translation"
+
+	// featured document error
+	ErrInvalidFeaturedScope         = "invalid featured scope"
+	ErrTooManyFeaturedSlots         = "too many featured documents"
+	ErrDuplicateFeaturedDocument    = "a document can only be featured once per scope"
+	ErrFeaturedDocumentNotPublished = "only published documents can be featured"
+	ErrFeaturedDocumentOtherOrg     = "document belongs to another organization"
+	ErrFeaturedExpiryPassed         = "featured expiry must be in the future"
+	ErrNotFeaturedScopeAdmin        = "only a superadmin of this organization can manage its featured documents"
+	ErrFeaturedDocumentNotFound     = "featured document not found"
+	ErrGetFeaturedDocumentFailed    = "failed to get featured documents"
+	ErrUpdateFeaturedDocumentFailed = "failed to update featured documents"
+	ErrDeleteFeaturedDocumentFailed = "failed to delete featured document"
+
+	// document review error
+	ErrInvalidReviewAction     = "invalid review action"
+	ErrInvalidStatusTransition = "document cannot be moved to the requested status"
+	ErrDocumentStatusChanged   = "document status has been changed by someone else"
+	ErrNotDocumentAuthor       = "only the author can submit this document"
+	ErrDocumentUnderReview     = "document cannot be edited by its author while it is under review"
+	ErrNotDocumentReviewer     = "only a superadmin of the author's organization can review this document"
+	ErrReviewCommentRequired   = "review comment is required"
+	ErrReviewDocumentFailed    = "failed to review document"
+	ErrGetDocumentReviewFailed = "failed to get document reviews"
+
+	// document revision error
+	ErrInvalidRevision           = "invalid revision"
+	ErrRevisionNotFound          = "revision not found"
+	ErrGetDocumentRevisionFailed = "failed to get document revisions"
+	ErrRestoreRevisionFailed     = "failed to restore document revision"
+
+	// document expiry error
+	ErrDocumentExpiryPassed       = "document expiry must be in the future"
+	ErrNotArchivableDocument      = "only published or archived documents can expire"
+	ErrUpdateDocumentExpiryFailed = "failed to update document expiry"
+	ErrArchiveDocumentFailed      = "failed to archive expired documents"
+
+	// document link error
+	ErrInvalidDocumentLinkType   = "invalid document link type"
+	ErrSelfDocumentLink          = "a document cannot link to itself"
+	ErrDuplicateDocumentLink     = "a document can only be linked once"
+	ErrDocumentAlreadyLinked     = "the linked document already links to this document"
+	ErrTooManyDocumentLinks      = "too many document links"
+	ErrLinkedDocumentNotFound    = "linked document not found"
+	ErrGetDocumentLinksFailed    = "failed to get document links"
+	ErrUpdateDocumentLinksFailed = "failed to update document links"
+
+	// bulk document error
+	ErrInvalidBulkOperation      = "invalid bulk operation"
+	ErrInvalidBulkMode           = "invalid bulk mode"
+	ErrTooManyBulkDocuments      = "too many documents in one bulk operation"
+	ErrDuplicateBulkDocument     = "a document can only be listed once"
+	ErrBulkTypeRequired          = "type_id is required to change the type"
+	ErrBulkTagsRequired          = "tag_ids is required to add tags"
+	ErrArchiveNotPublished       = "only published documents can be archived"
+	ErrBulkItemRolledBack        = "not applied because another document failed"
+	ErrBulkUpdateDocumentsFailed = "failed to update documents"
+
+	// trash error
+	ErrDocumentNotInTrash    = "document not found in trash"
+	ErrRestoreDocumentFailed = "failed to restore document"
+	ErrPurgeDocumentFailed   = "failed to purge document"
+
+	// tag error
+	ErrTagNotFound              = "tag not found"
+	ErrTagAlreadyExists         = "tag already exists"
+	ErrInvalidTagName           = "invalid tag name"
+	ErrInvalidTagMode           = "invalid tag mode"
+	ErrGetTagFailed             = "failed to get tag"
+	ErrInsertTagFailed          = "failed to insert tag"
+	ErrUpdateTagFailed          = "failed to update tag"
+	ErrDeleteTagFailed          = "failed to delete tag"
+	ErrGetTagUsageFailed        = "failed to get tag usage"
+	ErrUpdateDocumentTagsFailed = "failed to update document tags"
+
+	// budget error
+	ErrNotBudgetDocument         = "only documents of type BUDGET can have a budget"
+	ErrBudgetNotFound            = "budget not found"
+	ErrInvalidFiscalYear         = "invalid fiscal year"
+	ErrInvalidCurrency           = "invalid currency"
+	ErrInvalidBudgetAmount       = "invalid budget amount"
+	ErrTooManyBudgetItems        = "too many budget items"
+	ErrBudgetTotalMismatch       = "budget totals do not match the sum of the line items"
+	ErrInvalidBudgetImport       = "invalid budget import file"
+	ErrBudgetImportTooLarge      = "budget import file is too large"
+	ErrUnsupportedBudgetImport   = "budget import file must be csv or xlsx"
+	ErrMissingBudgetImportColumn = "budget import file is missing a required column"
+	ErrGetBudgetFailed           = "failed to get budget"
+	ErrUpdateBudgetFailed        = "failed to update budget"
+	ErrDeleteBudgetFailed        = "failed to delete budget"
+
+	// dataset error
+	ErrNotStatisticDocument       = "only documents of type STATISTIC can have a dataset"
+	ErrDatasetNotFound            = "dataset not found"
+	ErrInvalidDatasetDimension    = "invalid dataset dimension"
+	ErrTooManyDatasetDimensions   = "too many dataset dimensions"
+	ErrInvalidDatasetSeries       = "invalid dataset series"
+	ErrTooManyDatasetSeries       = "too many dataset series"
+	ErrTooManyDatasetPoints       = "too many dataset points"
+	ErrInvalidDatasetPoint        = "invalid dataset point"
+	ErrInvalidDatasetImport       = "invalid dataset import file"
+	ErrDatasetImportTooLarge      = "dataset import file is too large"
+	ErrUnsupportedDatasetImport   = "dataset import file must be csv or xlsx"
+	ErrMissingDatasetImportColumn = "dataset import file is missing the value column"
+	ErrInvalidAggregate           = "invalid aggregate"
+	ErrInvalidDatasetFilter       = "invalid dataset filter"
+	ErrGetDatasetFailed           = "failed to get dataset"
+	ErrUpdateDatasetFailed        = "failed to update dataset"
+	ErrDeleteDatasetFailed        = "failed to delete dataset"
 
 	// attachment error
-	ErrAttachmentNotFound     = "attachment not found"
-	ErrDeleteAttachmentFailed = "failed to delete attachment"
-	ErrFindAttachmentByID     = "failed to find attachment by ID"
+	ErrAttachmentNotFound          = "attachment not found"
+	ErrDeleteAttachmentFailed      = "failed to delete attachment"
+	ErrFindAttachmentByID          = "failed to find attachment by ID"
+	ErrGetAttachmentFailed         = "failed to get attachment"
+	ErrDeleteAttachmentFromS3      = "failed to delete attachment from s3"
+	ErrPurgeAttachmentFailed       = "failed to purge attachment"
+	ErrInvalidAttachmentType       = "invalid attachment type"
+	ErrNotAttachmentOrg            = "only members of this organization can list its attachments"
+	ErrInvalidAttachmentVisibility = "invalid attachment visibility"
+	ErrNotAttachmentViewer         = "only the author or a superadmin of the author's organization can open a private attachment"
+	ErrSignAttachmentURLFailed     = "failed to sign attachment url"
+	ErrAttachmentTooLarge          = "file size exceeds the allowed limit"
+	ErrTooManyUploadFiles          = "too many files in one upload"
+	ErrInvalidUploadSize           = "file size must be positive"
+	ErrContentTypeMismatch         = "content type does not match the file extension"
+	ErrUploadNotFound              = "upload not found"
+	ErrNotUploadOwner              = "only the user who started the upload can complete it"
+	ErrUploadExpired               = "upload has expired"
+	ErrUploadedFileNotFound        = "file has not been uploaded yet"
+	ErrUploadedFileMismatch        = "uploaded file does not match the upload"
+	ErrCreateUploadFailed          = "failed to create upload"
+	ErrCompleteUploadFailed        = "failed to complete upload"
+	ErrCleanupUploadFailed         = "failed to clean up upload"
+	ErrFileContentMismatch         = "file content does not match its extension"
+	ErrUnsafeSvg                   = "svg contains script, event handlers or external content"
+	ErrInvalidSvg                  = "svg is not well-formed"
+	ErrSvgTooLarge                 = "svg file exceeds the allowed limit"
+	ErrReadAttachmentFailed        = "failed to read attachment"
+	ErrVariantNotFound             = "attachment has no variant of this width"
+	ErrGenerateVariantsFailed      = "failed to generate image variants"
+	ErrCommitAttachmentsFailed     = "failed to commit attachments"
+	ErrReconcileStorageFailed      = "failed to reconcile storage with attachments"
+	ErrGetStorageSavingsFailed     = "failed to get storage savings"
+	ErrTusVersionUnsupported       = "unsupported tus version, use 1.0.0"
+	ErrInvalidUploadMetadata       = "upload metadata must name the file"
+	ErrInvalidUploadOffset         = "invalid upload offset"
+	ErrUploadOffsetMismatch        = "upload offset does not match the bytes received"
+	ErrInvalidChunkContentType     = "chunks must be sent as application/offset+octet-stream"
+	ErrChunkTooLarge               = "chunk goes past the end of the upload"
+	ErrUploadBusy                  = "another chunk of this upload is being received"
+	ErrAppendUploadFailed          = "failed to save upload chunk"
+	// stored file error
+	ErrStoredFileNotFound   = "file not found"
+	ErrStoredFileForbidden  = "file link is invalid or has expired"
+	ErrInvalidStoredFileKey = "invalid file path"
+	ErrStoreFileFailed      = "failed to store file"
 	// pagination error
 	ErrInvalidPageSize = "invalid page size"
+	// request error
+	ErrRequestBodyTooLarge       = "request body is too large"
+	ErrRequestBodyLengthRequired = "request body must be sent with a Content-Length"
 )
@@ -0,0 +1,8 @@
+package constant
+
+// featured document scope, besides the organizations
+const (
+	FEATURED_GLOBAL string = "GLOBAL" // shown on the homepage of every organization
+
+	MAX_FEATURED_SLOTS int = 10 // slots per scope
+)
@@ -0,0 +1,21 @@
+package constant
+
+// content locale
+const (
+	TH string = "th"
+	EN string = "en"
+
+	DEFAULT_LOCALE string = TH // locale of documents written before translations existed
+)
+
+var SupportedLocales = [...]string{
+	TH,
+	EN,
+}
+
+// LocaleFallbacks is tried in order after the requested locale,
+// a document in none of them is served in its own base locale
+var LocaleFallbacks = [...]string{
+	TH,
+	EN,
+}
@@ -0,0 +1,7 @@
+package constant
+
+// tag filter mode of document listings
+const (
+	TAG_MODE_ANY string = "any" // documents having at least one of the tags
+	TAG_MODE_ALL string = "all" // documents having every tag
+)
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+type DiffLine struct {
+	Op   string // op: equal, insert, delete
+	Text string
+}
+
+// DiffLines returns a line-level diff turning a into b
+func DiffLines(a, b string) []DiffLine {
+	x := splitLines(a)
+	y := splitLines(b)
+
+	// strip common prefix and suffix so the LCS table only covers the changed region
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]DiffLine, 0, len(x)+len(y))
+	for _, line := range x[:prefix] {
+		diff = append(diff, DiffLine{Op: constant.DIFF_EQUAL, Text: line})
+	}
+	diff = append(diff, diffMiddle(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, line := range x[len(x)-suffix:] {
+		diff = append(diff, DiffLine{Op: constant.DIFF_EQUAL, Text: line})
+	}
+
+	return diff
+}
+
+func diffMiddle(x, y []string) []DiffLine {
+	diff := make([]DiffLine, 0, len(x)+len(y))
+
+	// the table is quadratic, fall back to replacing the whole region on huge edits
+	if len(x)*len(y) > constant.MAX_DIFF_CELLS {
+		for _, line := range x {
+			diff = append(diff, DiffLine{Op: constant.DIFF_DELETE, Text: line})
+		}
+		for _, line := range y {
+			diff = append(diff, DiffLine{Op: constant.DIFF_INSERT, Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			diff = append(diff, DiffLine{Op: constant.DIFF_EQUAL, Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: constant.DIFF_DELETE, Text: x[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: constant.DIFF_INSERT, Text: y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		diff = append(diff, DiffLine{Op: constant.DIFF_DELETE, Text: x[i]})
+	}
+	for ; j < len(y); j++ {
+		diff = append(diff, DiffLine{Op: constant.DIFF_INSERT, Text: y[j]})
+	}
+
+	return diff
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+func EqualStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
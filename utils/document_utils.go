@@ -7,17 +7,50 @@ import (
 	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
 )
 
-func GetDocType(docType string) (string, error) {
-	switch strings.ToUpper(docType) {
-	case constant.ANNOUNCEMENT:
-		docType = constant.ANNOUNCEMENT
-	case constant.BUDGET:
-		docType = constant.BUDGET
-	case constant.STATISTIC:
-		docType = constant.STATISTIC
-	default:
-		return "", errors.New(constant.ErrInvalidDocType)
+// NormalizeDocTypeID turns a document type ID from a request into its stored form
+func NormalizeDocTypeID(docType string) string {
+	return strings.ToUpper(strings.TrimSpace(docType))
+}
+
+// ValidDocTypeID reports whether a normalized document type ID only uses A-Z, 0-9 and
+// underscores and starts with a letter, so it stays usable in URLs and query strings
+func ValidDocTypeID(docType string) bool {
+	if docType == "" || len(docType) > constant.MAX_DOC_TYPE_ID_LENGTH || docType[0] < 'A' || docType[0] > 'Z' {
+		return false
 	}
+	for _, c := range docType {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func IsCoreDocType(docType string) bool {
+	for _, t := range constant.CoreDocTypes {
+		if t == docType {
+			return true
+		}
+	}
+	return false
+}
+
+func GetNextDocStatus(status, action string) (string, error) {
+	transitions, ok := constant.DocumentTransitions[action]
+	if !ok {
+		return "", errors.New(constant.ErrInvalidReviewAction)
+	}
+
+	next, ok := transitions[status]
+	if !ok {
+		return "", errors.New(constant.ErrInvalidStatusTransition)
+	}
+
+	return next, nil
+}
 
-	return docType, nil
+// RequiresReview reports whether a document of a type with the given review flag,
+// written by authorRole, has to be approved before going public
+func RequiresReview(typeRequiresReview bool, authorRole string) bool {
+	return typeRequiresReview && !IsSuperAdmin(authorRole)
 }
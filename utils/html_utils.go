@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"html"
+	"net/url"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// htmlAllowedTags maps each tag kept by SanitizeHTML to the attributes it may carry
+var htmlAllowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil, "sub": nil, "sup": nil,
+	"blockquote": nil, "pre": nil, "code": nil,
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil, "th": {"colspan", "rowspan"}, "td": {"colspan", "rowspan"},
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "title", "width", "height"},
+}
+
+// htmlDroppedTags are removed together with everything inside them
+var htmlDroppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "noscript": true,
+	"template": true, "textarea": true, "select": true, "svg": true, "math": true, "head": true, "title": true,
+}
+
+var htmlVoidTags = map[string]bool{
+	"br": true, "hr": true, "img": true,
+}
+
+// htmlBlockTags end a line of the plain text of a document
+var htmlBlockTags = map[string]bool{
+	"p": true, "br": true, "hr": true, "div": true, "li": true, "tr": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "table": true, "ul": true, "ol": true,
+}
+
+// SanitizeHTML keeps only whitelisted tags and attributes of src and returns well-formed HTML.
+// Links may only point to http(s), mailto, tel or relative URLs and images to http(s) or relative
+// URLs. Unknown tags are unwrapped, script-like tags are dropped with their content.
+func SanitizeHTML(src string) string {
+	var b strings.Builder
+	open := make([]string, 0)
+	dropping := ""
+
+	z := xhtml.NewTokenizer(strings.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+		token := z.Token()
+
+		if dropping != "" {
+			if tt == xhtml.EndTagToken && token.Data == dropping {
+				dropping = ""
+			}
+			continue
+		}
+
+		switch tt {
+		case xhtml.TextToken:
+			b.WriteString(html.EscapeString(token.Data))
+
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			if htmlDroppedTags[token.Data] {
+				if tt == xhtml.StartTagToken {
+					dropping = token.Data
+				}
+				continue
+			}
+			attrs, ok := htmlAllowedTags[token.Data]
+			if !ok {
+				continue
+			}
+			if token.Data == "img" && sanitizeURL(attrValue(token, "src"), false) == "" {
+				continue
+			}
+
+			b.WriteString("<" + token.Data)
+			for _, name := range attrs {
+				value := attrValue(token, name)
+				switch name {
+				case "href":
+					value = sanitizeURL(value, true)
+				case "src":
+					value = sanitizeURL(value, false)
+				}
+				if value != "" {
+					b.WriteString(" " + name + "=\"" + html.EscapeString(value) + "\"")
+				}
+			}
+			if token.Data == "a" && isAbsoluteURL(sanitizeURL(attrValue(token, "href"), true)) {
+				b.WriteString(" rel=\"nofollow noopener noreferrer\"")
+			}
+			b.WriteString(">")
+
+			if !htmlVoidTags[token.Data] && tt == xhtml.StartTagToken {
+				open = append(open, token.Data)
+			}
+
+		case xhtml.EndTagToken:
+			// close everything opened after the matching tag, a stray end tag is ignored
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != token.Data {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+
+	return b.String()
+}
+
+// PlainText returns the text of an HTML fragment, one line per block with spaces collapsed
+func PlainText(src string) string {
+	lines := make([]string, 0)
+	var line strings.Builder
+
+	flush := func() {
+		if text := strings.Join(strings.Fields(line.String()), " "); text != "" {
+			lines = append(lines, text)
+		}
+		line.Reset()
+	}
+
+	z := xhtml.NewTokenizer(strings.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+		token := z.Token()
+
+		switch tt {
+		case xhtml.TextToken:
+			line.WriteString(token.Data)
+		case xhtml.StartTagToken, xhtml.EndTagToken, xhtml.SelfClosingTagToken:
+			if htmlBlockTags[token.Data] {
+				flush()
+			} else if token.Data == "td" || token.Data == "th" {
+				line.WriteString(" ")
+			}
+		}
+	}
+	flush()
+
+	return strings.Join(lines, "\n")
+}
+
+func attrValue(token xhtml.Token, name string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == name {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+	return ""
+}
+
+// sanitizeURL returns raw when it is safe to use as a link (isLink) or an image source,
+// otherwise an empty string. The tokenizer has already decoded entities, so an encoded
+// javascript: scheme is seen as such.
+func sanitizeURL(raw string, isLink bool) string {
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https":
+		return raw
+	case "mailto", "tel":
+		if isLink {
+			return raw
+		}
+	}
+	return ""
+}
+
+func isAbsoluteURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return raw != "" && err == nil && (u.Scheme != "" || u.Host != "")
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+// NormalizeLocale maps a language tag like "en-US" to a supported locale, or "" when unsupported
+func NormalizeLocale(tag string) string {
+	primary := strings.ToLower(strings.TrimSpace(strings.SplitN(tag, "-", 2)[0]))
+	for _, l := range constant.SupportedLocales {
+		if l == primary {
+			return l
+		}
+	}
+	return ""
+}
+
+// ResolveLocale picks the locale a client asked for.
+// An explicit ?lang= wins over Accept-Language, whose entries are tried by quality.
+// Returns "" when neither names a supported locale.
+func ResolveLocale(lang, acceptLanguage string) string {
+	if l := NormalizeLocale(lang); l != "" {
+		return l
+	}
+
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	candidates := make([]weighted, 0)
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if l := NormalizeLocale(fields[0]); l != "" && q > 0 {
+			candidates = append(candidates, weighted{l, q})
+		}
+	}
+
+	// stable, so equally weighted languages keep the client's order
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	if len(candidates) > 0 {
+		return candidates[0].locale
+	}
+	return ""
+}
+
+// PickLocale returns the first available locale in fallback order: the requested one,
+// then constant.LocaleFallbacks, then the first available (the document's base locale)
+func PickLocale(requested string, available []string) string {
+	has := func(locale string) bool {
+		for _, l := range available {
+			if l == locale {
+				return true
+			}
+		}
+		return false
+	}
+
+	if requested != "" && has(requested) {
+		return requested
+	}
+	for _, l := range constant.LocaleFallbacks {
+		if has(l) {
+			return l
+		}
+	}
+	if len(available) > 0 {
+		return available[0]
+	}
+	return ""
+}
@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RenderMarkdown converts the Markdown subset used for document content into HTML.
+// Supported blocks are paragraphs, ATX headings, fenced code, block quotes, flat lists
+// and thematic breaks; inline code, links, images, emphasis, strikethrough and hard
+// line breaks are supported inside them. Raw HTML is escaped, not passed through.
+func RenderMarkdown(source string) string {
+	lines := strings.Split(strings.ReplaceAll(strings.ReplaceAll(source, "\r\n", "\n"), "\r", "\n"), "\n")
+
+	var b strings.Builder
+	renderBlocks(&b, lines)
+	return b.String()
+}
+
+var (
+	mdHeading     = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)[ \t#]*$`)
+	mdBreak       = regexp.MustCompile(`^[ \t]*(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdFence       = regexp.MustCompile("^[ \t]*(```|~~~)")
+	mdQuote       = regexp.MustCompile(`^[ \t]*>[ ]?`)
+	mdBullet      = regexp.MustCompile(`^[ \t]*[-*+][ \t]+`)
+	mdOrdered     = regexp.MustCompile(`^[ \t]*(\d{1,9})[.)][ \t]+`)
+	mdContinuance = regexp.MustCompile(`^[ \t]{2,}\S`)
+)
+
+func renderBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case mdFence.MatchString(line):
+			fence := mdFence.FindStringSubmatch(line)[1]
+			code := make([]string, 0)
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			i++ // closing fence, an unclosed block runs to the end
+			b.WriteString("<pre><code>")
+			b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			b.WriteString("</code></pre>\n")
+
+		case mdHeading.MatchString(line):
+			m := mdHeading.FindStringSubmatch(line)
+			fmt.Fprintf(b, "<h%d>%s</h%d>\n", len(m[1]), renderInline(m[2]), len(m[1]))
+			i++
+
+		case mdBreak.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+
+		case mdQuote.MatchString(line):
+			quoted := make([]string, 0)
+			for ; i < len(lines) && mdQuote.MatchString(lines[i]); i++ {
+				quoted = append(quoted, mdQuote.ReplaceAllString(lines[i], ""))
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+
+		case mdBullet.MatchString(line), mdOrdered.MatchString(line):
+			i = renderList(b, lines, i)
+
+		default:
+			paragraph := make([]string, 0)
+			for ; i < len(lines) && !startsBlock(lines[i]); i++ {
+				paragraph = append(paragraph, lines[i])
+			}
+			b.WriteString("<p>")
+			b.WriteString(renderLines(paragraph))
+			b.WriteString("</p>\n")
+		}
+	}
+}
+
+// renderList writes the list starting at lines[i] and returns the index after it.
+// Items are kept flat, an indented line continues the item above it.
+func renderList(b *strings.Builder, lines []string, i int) int {
+	ordered := mdOrdered.MatchString(lines[i])
+	marker := mdBullet
+	if ordered {
+		marker = mdOrdered
+		if start, _ := strconv.Atoi(mdOrdered.FindStringSubmatch(lines[i])[1]); start != 1 {
+			fmt.Fprintf(b, "<ol start=\"%d\">\n", start)
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+
+	for i < len(lines) && marker.MatchString(lines[i]) {
+		item := []string{marker.ReplaceAllString(lines[i], "")}
+		for i++; i < len(lines) && mdContinuance.MatchString(lines[i]) && !marker.MatchString(lines[i]); i++ {
+			item = append(item, lines[i])
+		}
+		b.WriteString("<li>")
+		b.WriteString(renderLines(item))
+		b.WriteString("</li>\n")
+	}
+
+	if ordered {
+		b.WriteString("</ol>\n")
+	} else {
+		b.WriteString("</ul>\n")
+	}
+	return i
+}
+
+func startsBlock(line string) bool {
+	return strings.TrimSpace(line) == "" ||
+		mdFence.MatchString(line) ||
+		mdHeading.MatchString(line) ||
+		mdBreak.MatchString(line) ||
+		mdQuote.MatchString(line) ||
+		mdBullet.MatchString(line) ||
+		mdOrdered.MatchString(line)
+}
+
+// renderLines joins the lines of one block, a line ending in two spaces or a backslash breaks
+func renderLines(lines []string) string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		hard := i < len(lines)-1 && (strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\"))
+		line = strings.TrimSpace(line)
+		if hard {
+			line = strings.TrimSuffix(line, "\\")
+		}
+		out[i] = renderInline(line)
+		if hard {
+			out[i] += "<br>"
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+var (
+	mdLink     = regexp.MustCompile(`^(!?)\[([^\]]*)\]\(\s*<?([^\s()<>]*)>?(?:\s+"([^"]*)")?\s*\)`)
+	mdAutolink = regexp.MustCompile(`^<((?:https?://|mailto:)[^\s<>]+)>`)
+)
+
+// mdEmphasis lists inline delimiters, longest first so ** is not read as two *
+var mdEmphasis = []struct {
+	delim string
+	tag   string
+}{
+	{"**", "strong"},
+	{"__", "strong"},
+	{"~~", "del"},
+	{"*", "em"},
+	{"_", "em"},
+}
+
+func renderInline(text string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+
+		// backslash escapes ASCII punctuation
+		if rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_{}[]()#+-.!~<>|\"'", rune(rest[1])) {
+			b.WriteString(html.EscapeString(rest[1:2]))
+			i += 2
+			continue
+		}
+
+		if rest[0] == '`' {
+			ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+			if end := strings.Index(rest[ticks:], rest[:ticks]); end >= 0 {
+				b.WriteString("<code>")
+				b.WriteString(html.EscapeString(strings.TrimSpace(rest[ticks : ticks+end])))
+				b.WriteString("</code>")
+				i += ticks + end + ticks
+				continue
+			}
+		}
+
+		if m := mdLink.FindStringSubmatch(rest); m != nil {
+			title := ""
+			if m[4] != "" {
+				title = fmt.Sprintf(" title=\"%s\"", html.EscapeString(m[4]))
+			}
+			if m[1] == "!" {
+				fmt.Fprintf(&b, "<img src=\"%s\" alt=\"%s\"%s>", html.EscapeString(m[3]), html.EscapeString(m[2]), title)
+			} else {
+				fmt.Fprintf(&b, "<a href=\"%s\"%s>%s</a>", html.EscapeString(m[3]), title, renderInline(m[2]))
+			}
+			i += len(m[0])
+			continue
+		}
+
+		if m := mdAutolink.FindStringSubmatch(rest); m != nil {
+			fmt.Fprintf(&b, "<a href=\"%s\">%s</a>", html.EscapeString(m[1]), html.EscapeString(m[1]))
+			i += len(m[0])
+			continue
+		}
+
+		// underscores inside a word, as in snake_case, are kept as they are
+		if rest[0] == '_' && i > 0 && isWordByte(text[i-1]) {
+			run := len(rest) - len(strings.TrimLeft(rest, "_"))
+			b.WriteString(rest[:run])
+			i += run
+			continue
+		}
+
+		if n, ok := renderEmphasis(&b, rest); ok {
+			i += n
+			continue
+		}
+
+		b.WriteString(html.EscapeString(rest[:1]))
+		i++
+	}
+
+	return b.String()
+}
+
+// renderEmphasis writes an emphasis span opening at the start of text and reports its length.
+// A span needs non-space text right inside both delimiters.
+func renderEmphasis(b *strings.Builder, text string) (int, bool) {
+	for _, e := range mdEmphasis {
+		if !strings.HasPrefix(text, e.delim) {
+			continue
+		}
+		inner := text[len(e.delim):]
+		end := strings.Index(inner, e.delim)
+		if end <= 0 || strings.TrimSpace(inner[:1]) == "" || strings.TrimSpace(inner[end-1:end]) == "" {
+			continue
+		}
+		if after := end + len(e.delim); e.delim[0] == '_' && after < len(inner) && isWordByte(inner[after]) {
+			continue
+		}
+		fmt.Fprintf(b, "<%s>%s</%s>", e.tag, renderInline(inner[:end]), e.tag)
+		return len(e.delim) + end + len(e.delim), true
+	}
+	return 0, false
+}
+
+func isWordByte(c byte) bool {
+	// any byte of a multi-byte rune counts, so Thai text is treated as word characters
+	return c >= 0x80 || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
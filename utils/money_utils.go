@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseAmount reads a decimal amount such as "12,500.50" into minor units, where decimals is the
+// number of digits after the decimal point of the currency. The conversion is exact: an amount
+// with more decimals than the currency has is an error rather than being rounded.
+func ParseAmount(s string, decimals int) (int64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("%q is not a decimal amount", s)
+	}
+	if len(fraction) > decimals {
+		return 0, fmt.Errorf("%q has more than %d decimals", s, decimals)
+	}
+
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is out of range", s)
+	}
+	if negative {
+		minor = -minor
+	}
+	return minor, nil
+}
+
+// FormatAmount writes minor units as a plain decimal amount, e.g. 1250050 with 2 decimals is "12500.50"
+func FormatAmount(minor int64, decimals int) string {
+	sign := ""
+	digits := strconv.FormatInt(minor, 10)
+	if minor < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if decimals == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-decimals] + "." + digits[len(digits)-decimals:]
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/chai2010/webp"
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+// DecodedImage is a decoded JPEG or PNG with the EXIF orientation a JPEG has to be turned upright by
+type DecodedImage struct {
+	Image       image.Image
+	Format      string // format: jpeg, png
+	Orientation int    // 1 to 8 as in EXIF, 1 is upright
+}
+
+// Width and Height are the dimensions the image is shown at, after it is turned upright
+func (d *DecodedImage) Width() int {
+	if d.swapsAxes() {
+		return d.Image.Bounds().Dy()
+	}
+	return d.Image.Bounds().Dx()
+}
+
+func (d *DecodedImage) Height() int {
+	if d.swapsAxes() {
+		return d.Image.Bounds().Dx()
+	}
+	return d.Image.Bounds().Dy()
+}
+
+func (d *DecodedImage) swapsAxes() bool {
+	return d.Orientation >= 5 && d.Orientation <= 8
+}
+
+// DecodeImage decodes a JPEG or PNG, images with too many pixels are refused before they are decoded
+func DecodeImage(data []byte) (*DecodedImage, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, errors.New("unsupported image format")
+	}
+	if int64(config.Width)*int64(config.Height) > constant.MAX_VARIANT_SOURCE_PIXELS {
+		return nil, errors.New("image has too many pixels")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	return &DecodedImage{Image: img, Format: format, Orientation: orientation}, nil
+}
+
+// Resize scales the image down to the width it is shown at, keeping its aspect ratio, and turns it upright
+func (d *DecodedImage) Resize(width int) *image.RGBA {
+	height := d.Height() * width / d.Width()
+	if height < 1 {
+		height = 1
+	}
+	if d.swapsAxes() {
+		return orient(resize(d.Image, height, width), d.Orientation)
+	}
+	return orient(resize(d.Image, width, height), d.Orientation)
+}
+
+// EncodeImage encodes an image as jpeg, png or webp
+func EncodeImage(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case constant.VARIANT_WEBP:
+		err = webp.Encode(&buf, img, &webp.Options{Quality: constant.VARIANT_WEBP_QUALITY})
+	default:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: constant.VARIANT_JPEG_QUALITY})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resize averages every source pixel that falls in a destination pixel, which keeps detail when shrinking
+func resize(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := max((y+1)*srcH/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := max((x+1)*srcW/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// orient flips and rotates an image as its EXIF orientation says
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored
+				sx, sy = w-1-x, y
+			case 3: // upside down
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored upside down
+				sx, sy = x, h-1-y
+			case 5: // mirrored, turned left
+				sx, sy = y, x
+			case 6: // turned left, shown turned right
+				sx, sy = y, h-1-x
+			case 7: // mirrored, turned right
+				sx, sy = w-1-y, h-1-x
+			case 8: // turned right, shown turned left
+				sx, sy = w-1-y, x
+			}
+			dst.SetRGBA(x, y, src.RGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the orientation tag from the EXIF segment of a JPEG, 1 when there is none
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if marker == 0xDA || length < 2 || i+2+length > len(data) { // image data starts, no EXIF before it
+			return 1
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for e := 0; e < entries; e++ {
+		entry := ifd + 2 + e*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
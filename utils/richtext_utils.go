@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+// RenderContent turns content sent in format into the safe HTML that is stored and served.
+// Markdown is rendered first and the result goes through the same sanitizer as HTML input.
+func RenderContent(format, source string) string {
+	if format == constant.CONTENT_MARKDOWN {
+		source = RenderMarkdown(source)
+	}
+	return SanitizeHTML(source)
+}
+
+// Excerpt shortens plain text to at most size runes, preferring to cut between words.
+// Text without a space near the cut, such as Thai, is cut mid run.
+func Excerpt(text string, size int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= size {
+		return string(runes)
+	}
+
+	cut := size
+	for i := size; i > size*3/4; i-- {
+		if runes[i] == ' ' {
+			cut = i
+			break
+		}
+	}
+
+	return strings.TrimSpace(string(runes[:cut])) + "…"
+}
+
+// ReadingTime estimates the minutes needed to read plain text, at least one for any text
+func ReadingTime(text string) int {
+	words, thai := 0, 0
+	for _, field := range strings.Fields(text) {
+		latin := false
+		for _, r := range field {
+			switch {
+			case isThai(r):
+				if !unicode.IsMark(r) {
+					thai++
+				}
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				latin = true
+			}
+		}
+		if latin {
+			words++
+		}
+	}
+	words += (thai + constant.THAI_RUNES_PER_WORD - 1) / constant.THAI_RUNES_PER_WORD
+
+	if words == 0 {
+		return 0
+	}
+	return (words + constant.READING_WORDS_PER_MINUTE - 1) / constant.READING_WORDS_PER_MINUTE
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+// SearchTokens splits text into index terms.
+// Thai is written without spaces between words, so each Thai run is indexed as
+// overlapping character bigrams; any other script is split into lowercased words.
+func SearchTokens(text string) []string {
+	seen := make(map[string]bool)
+	tokens := make([]string, 0)
+
+	add := func(token string) {
+		if token != "" && !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, run := range searchRuns(text) {
+		if !isThai(run[0]) {
+			add(string(run))
+			continue
+		}
+
+		if len(run) == 1 {
+			add(string(run))
+			continue
+		}
+		for i := 0; i+1 < len(run); i++ {
+			add(string(run[i : i+2]))
+		}
+	}
+
+	return tokens
+}
+
+// SearchText joins the index terms of text into the space separated form stored on a row
+func SearchText(text string) string {
+	return strings.Join(SearchTokens(text), " ")
+}
+
+// SearchQuery builds a tsquery that requires every term of q.
+// Latin terms match as prefixes so partially typed words still find results.
+// Returns an empty string when q has no searchable terms.
+func SearchQuery(q string) string {
+	tokens := SearchTokens(q)
+	terms := make([]string, len(tokens))
+	for i, token := range tokens {
+		// tokens only hold letters, marks and digits, so quoting them is enough
+		if isThai([]rune(token)[0]) {
+			terms[i] = fmt.Sprintf("'%s'", token)
+		} else {
+			terms[i] = fmt.Sprintf("'%s':*", token)
+		}
+	}
+	return strings.Join(terms, " & ")
+}
+
+// HighlightSnippet returns an HTML-escaped window of text around the first match of q,
+// with every match wrapped in <mark>. Text without a match yields its leading runes.
+func HighlightSnippet(text, q string, size int) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// find every non overlapping occurrence of the query runs
+	needles := searchRuns(q)
+	type match struct{ start, end int }
+	matches := make([]match, 0)
+	for i := 0; i < len(lower); {
+		matched := 0
+		for _, needle := range needles {
+			if len(needle) > matched && hasRunePrefix(lower[i:], needle) {
+				matched = len(needle)
+			}
+		}
+		if matched == 0 {
+			i++
+			continue
+		}
+		matches = append(matches, match{i, i + matched})
+		i += matched
+	}
+
+	start := 0
+	if len(matches) > 0 {
+		// keep a little lead before the match but never leave the window short near the end
+		start = max(0, min(matches[0].start-size/constant.SNIPPET_LEAD_RATIO, len(runes)-size))
+	}
+	end := min(len(runes), start+size)
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, m := range matches {
+		if m.start < start || m.end > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:m.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[m.start:m.end])))
+		b.WriteString("</mark>")
+		pos = m.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+// searchRuns splits text into lowercased runs of letters, marks and digits,
+// breaking wherever the script switches between Thai and anything else
+func searchRuns(text string) [][]rune {
+	runs := make([][]rune, 0)
+	current := make([]rune, 0)
+
+	flush := func() {
+		if len(current) > 0 {
+			runs = append(runs, current)
+			current = make([]rune, 0)
+		}
+	}
+
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if len(current) > 0 && isThai(current[0]) != isThai(r) {
+			flush()
+		}
+		current = append(current, unicode.ToLower(r))
+	}
+	flush()
+
+	return runs
+}
+
+func isThai(r rune) bool {
+	return unicode.Is(unicode.Thai, r)
+}
+
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+var (
+	ErrSheetTooLarge    = errors.New("file is too large")
+	ErrUnsupportedSheet = errors.New("file must be csv or xlsx")
+)
+
+// ReadSheetFile reads the rows of an uploaded CSV or XLSX file of at most maxSize bytes, the format is told by the extension
+func ReadSheetFile(file *multipart.FileHeader, maxSize int64) ([][]string, error) {
+	if file.Size > maxSize {
+		return nil, ErrSheetTooLarge
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext != ".csv" && ext != ".xlsx" {
+		return nil, ErrUnsupportedSheet
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s, %v", file.Filename, err)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s, %v", file.Filename, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrSheetTooLarge
+	}
+
+	if ext == ".xlsx" {
+		return ReadXLSXRows(data)
+	}
+	return ReadCSVRows(data)
+}
+
+// IsBlankRow reports whether every cell of a sheet row is blank
+func IsBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadCSVRows reads every record of a CSV file, a leading UTF-8 byte order mark as written by Excel is skipped
+func ReadCSVRows(data []byte) ([][]string, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	return r.ReadAll()
+}
+
+// ReadXLSXRows reads the cells of the first worksheet of an XLSX file as text, one slice per row.
+// Rows and cells left out of the file come back empty so indexes match the sheet. Numbers are
+// rounded to the 15 significant digits Excel shows, which drops binary float artifacts.
+func ReadXLSXRows(data []byte) ([][]string, error) {
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not an xlsx file, %v", err)
+	}
+
+	sheetPath, err := firstSheetPath(z)
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedStrings xlsxSharedStrings
+	if err := readXLSXPart(z, "xl/sharedStrings.xml", &sharedStrings); err != nil && !errors.Is(err, errXLSXPartNotFound) {
+		return nil, err
+	}
+
+	var sheet xlsxSheet
+	if err := readXLSXPart(z, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		// row numbers start at 1 and may skip empty rows
+		for row.R > len(rows)+1 {
+			rows = append(rows, []string{})
+		}
+
+		cells := make([]string, 0, len(row.Cells))
+		for _, cell := range row.Cells {
+			if col := xlsxColumn(cell.R); col > len(cells) {
+				cells = append(cells, make([]string, col-len(cells))...)
+			}
+
+			value := cell.V
+			switch cell.T {
+			case "s":
+				i, err := strconv.Atoi(cell.V)
+				if err != nil || i < 0 || i >= len(sharedStrings.Items) {
+					return nil, fmt.Errorf("cell %s refers to a missing shared string", cell.R)
+				}
+				value = sharedStrings.Items[i].String()
+			case "inlineStr":
+				value = cell.IS.String()
+			case "", "n":
+				value = xlsxNumber(cell.V)
+			}
+			cells = append(cells, value)
+		}
+		rows = append(rows, cells)
+	}
+
+	return rows, nil
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText is plain text or a list of formatted runs
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	s := t.T
+	for _, run := range t.Runs {
+		s += run.T
+	}
+	return s
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R  string   `xml:"r,attr"` // e.g. B3
+			T  string   `xml:"t,attr"` // type: s, inlineStr, str, b, e, n
+			V  string   `xml:"v"`
+			IS xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// errXLSXPartNotFound is returned by readXLSXPart for a part the file does not have
+var errXLSXPartNotFound = errors.New("part not found")
+
+// firstSheetPath follows the workbook relationships to the part holding the first worksheet
+func firstSheetPath(z *zip.Reader) (string, error) {
+	var workbook xlsxWorkbook
+	if err := readXLSXPart(z, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("workbook has no sheets")
+	}
+
+	var relationships xlsxRelationships
+	if err := readXLSXPart(z, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return "", err
+	}
+
+	for _, rel := range relationships.Items {
+		if rel.ID != workbook.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", errors.New("first sheet not found")
+}
+
+func readXLSXPart(z *zip.Reader, name string, v interface{}) error {
+	for _, f := range z.File {
+		if f.Name != name {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s, %v", name, err)
+		}
+		defer rc.Close()
+
+		// a small file can unzip into a huge one
+		data, err := io.ReadAll(io.LimitReader(rc, constant.MAX_SHEET_PART_SIZE+1))
+		if err != nil {
+			return fmt.Errorf("failed to read %s, %v", name, err)
+		}
+		if int64(len(data)) > constant.MAX_SHEET_PART_SIZE {
+			return fmt.Errorf("%s is too large", name)
+		}
+
+		if err := xml.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("failed to parse %s, %v", name, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s: %w", name, errXLSXPartNotFound)
+}
+
+// xlsxColumn turns the letters of a cell reference into a 0-based column, -1 when there are none
+func xlsxColumn(ref string) int {
+	col := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int(c-'A'+1)
+	}
+	return col - 1
+}
+
+func xlsxNumber(v string) string {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return v
+	}
+	f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'g', 15, 64), 64)
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
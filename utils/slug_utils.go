@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+// Slugify turns a title into a URL path segment.
+// Letters, marks and digits of any script are kept, so Thai titles stay readable,
+// everything else collapses into single dashes. Returns an empty string when nothing is kept.
+func Slugify(title string) string {
+	var b strings.Builder
+	count := 0
+	dash := false
+
+	for _, r := range strings.ToLower(title) {
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r) {
+			dash = true
+			continue
+		}
+		// let combining marks finish the last character instead of cutting it off
+		if count >= constant.MAX_SLUG_LENGTH && !unicode.IsMark(r) {
+			break
+		}
+		if dash && b.Len() > 0 {
+			b.WriteRune('-')
+		}
+		b.WriteRune(r)
+		count++
+		dash = false
+	}
+
+	return b.String()
+}
+
+// SlugCandidate returns the n-th slug to try for base: base itself, then base-2, base-3, ...
+func SlugCandidate(base string, n int) string {
+	if n <= 1 {
+		return base
+	}
+	return fmt.Sprintf("%s-%d", base, n)
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
+)
+
+// fileSignature is what the content of a file with an extension has to look like
+type fileSignature struct {
+	contentType string
+	match       func(head []byte) bool
+}
+
+var (
+	zipMagic = []byte("PK\x03\x04")
+	oleMagic = []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1") // legacy office documents
+)
+
+var fileSignatures = map[string]fileSignature{
+	".jpg":      {"image/jpeg", sniffedAs("image/jpeg")},
+	".jpeg":     {"image/jpeg", sniffedAs("image/jpeg")},
+	".png":      {"image/png", sniffedAs("image/png")},
+	".gif":      {"image/gif", sniffedAs("image/gif")},
+	".bmp":      {"image/bmp", sniffedAs("image/bmp")},
+	".webp":     {"image/webp", sniffedAs("image/webp")},
+	".tiff":     {"image/tiff", startsWith([]byte("II*\x00"), []byte("MM\x00*"))},
+	".svg":      {"image/svg+xml", isText},
+	".pdf":      {"application/pdf", startsWith([]byte("%PDF-"))},
+	".doc":      {"application/msword", startsWith(oleMagic)},
+	".xls":      {"application/vnd.ms-excel", startsWith(oleMagic)},
+	".ppt":      {"application/vnd.ms-powerpoint", startsWith(oleMagic)},
+	".docx":     {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", startsWith(zipMagic)},
+	".xlsx":     {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", startsWith(zipMagic)},
+	".pptx":     {"application/vnd.openxmlformats-officedocument.presentationml.presentation", startsWith(zipMagic)},
+	".odt":      {"application/vnd.oasis.opendocument.text", startsWith(zipMagic)},
+	".rtf":      {"application/rtf", startsWith([]byte("{\\rtf"))},
+	".txt":      {"text/plain; charset=utf-8", isText},
+	".csv":      {"text/csv; charset=utf-8", isText},
+	".md":       {"text/markdown; charset=utf-8", isText},
+	".markdown": {"text/markdown; charset=utf-8", isText},
+}
+
+// SniffFile checks the content of a file against its extension and returns the MIME type to store it with.
+// Only the leading bytes are read, except for an svg which is read whole and rejected if it could run script.
+func SniffFile(fileName string, size int64, r io.Reader) (string, error) {
+	if !IsSvg(fileName) {
+		head, err := io.ReadAll(io.LimitReader(r, constant.SNIFF_SIZE))
+		if err != nil {
+			return "", err
+		}
+		return detectContentType(fileName, head)
+	}
+
+	if size > constant.MAX_SVG_SIZE {
+		return "", errors.New(constant.ErrSvgTooLarge)
+	}
+	content, err := io.ReadAll(io.LimitReader(r, constant.MAX_SVG_SIZE+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(content)) > constant.MAX_SVG_SIZE {
+		return "", errors.New(constant.ErrSvgTooLarge)
+	}
+
+	contentType, err := detectContentType(fileName, content)
+	if err != nil {
+		return "", err
+	}
+	if err := checkSvg(content); err != nil {
+		return "", err
+	}
+	return contentType, nil
+}
+
+// ContentTypeByExtension is the MIME type a file with an allowed extension is stored with
+func ContentTypeByExtension(fileName string) string {
+	return fileSignatures[strings.ToLower(filepath.Ext(fileName))].contentType
+}
+
+// IsSvg reports whether the whole file has to be read to check it
+func IsSvg(fileName string) bool {
+	return strings.ToLower(filepath.Ext(fileName)) == ".svg"
+}
+
+// detectContentType checks the leading bytes of a file against its extension
+func detectContentType(fileName string, head []byte) (string, error) {
+	signature, ok := fileSignatures[strings.ToLower(filepath.Ext(fileName))]
+	if !ok {
+		return "", errors.New("invalid file type")
+	}
+	if !signature.match(head) {
+		return "", errors.New(constant.ErrFileContentMismatch)
+	}
+	return signature.contentType, nil
+}
+
+// checkSvg rejects an svg that is not well-formed or could run script when it is opened in a browser
+func checkSvg(content []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	decoder.Strict = true
+
+	root := true
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.New(constant.ErrInvalidSvg)
+		}
+
+		switch t := token.(type) {
+		case xml.Directive:
+			// a DOCTYPE may declare entities that pull in other files
+			if bytes.Contains(bytes.ToUpper(t), []byte("ENTITY")) {
+				return errors.New(constant.ErrUnsafeSvg)
+			}
+		case xml.ProcInst:
+			if t.Target == "xml-stylesheet" {
+				return errors.New(constant.ErrUnsafeSvg)
+			}
+		case xml.StartElement:
+			if root && !strings.EqualFold(t.Name.Local, "svg") {
+				return errors.New(constant.ErrInvalidSvg)
+			}
+			root = false
+			if !isSafeSvgElement(t) {
+				return errors.New(constant.ErrUnsafeSvg)
+			}
+		case xml.CharData:
+			if hasScriptURL(string(t)) {
+				return errors.New(constant.ErrUnsafeSvg)
+			}
+		}
+	}
+
+	if root {
+		return errors.New(constant.ErrInvalidSvg)
+	}
+	return nil
+}
+
+func isSafeSvgElement(element xml.StartElement) bool {
+	switch strings.ToLower(element.Name.Local) {
+	case "script", "foreignobject", "iframe", "embed", "object":
+		return false
+	}
+
+	for _, attr := range element.Attr {
+		name := strings.ToLower(attr.Name.Local)
+		if strings.HasPrefix(name, "on") || hasScriptURL(attr.Value) {
+			return false
+		}
+		// links may only point inside the document or to images
+		if name == "href" && !strings.HasPrefix(strings.TrimSpace(attr.Value), "#") && !strings.HasPrefix(strings.TrimSpace(attr.Value), "data:image/") {
+			return false
+		}
+	}
+	return true
+}
+
+func hasScriptURL(value string) bool {
+	value = strings.ToLower(strings.Join(strings.Fields(value), ""))
+	return strings.Contains(value, "javascript:") || strings.Contains(value, "vbscript:") || strings.Contains(value, "data:text/html")
+}
+
+func sniffedAs(contentType string) func(head []byte) bool {
+	return func(head []byte) bool {
+		return http.DetectContentType(head) == contentType
+	}
+}
+
+func startsWith(magics ...[]byte) func(head []byte) bool {
+	return func(head []byte) bool {
+		for _, magic := range magics {
+			if bytes.HasPrefix(head, magic) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isText accepts plain text, a binary disguised as text sniffs as something else
+func isText(head []byte) bool {
+	return strings.HasPrefix(http.DetectContentType(head), "text/")
+}
@@ -0,0 +1,23 @@
+package utils
+
+import "strings"
+
+// NormalizeTagName lowercases a tag name and collapses its whitespace,
+// so "Sports  Day" and "sports day" are the same tag
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// ParseTagNames splits a comma separated tag query into distinct normalized names
+func ParseTagNames(query string) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, name := range strings.Split(query, ",") {
+		name = NormalizeTagName(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
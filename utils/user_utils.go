@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/isd-sgcu/sucu-backend-2024/utils/constant"
 )
@@ -22,3 +23,7 @@ func GetRole(role string) (string, error) {
 func IsSuperAdmin(role string) bool {
 	return role == constant.SGCU_SUPERADMIN || role == constant.SCCU_SUPERADMIN
 }
+
+func GetOrg(role string) string {
+	return strings.Split(strings.ToUpper(role), "_")[0]
+}
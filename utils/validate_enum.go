@@ -19,16 +19,6 @@ func validate(word string, words []string) bool {
 	return false
 }
 
-func ValidateDocType(docType string) bool {
-	docs := []string{
-		constant.ANNOUNCEMENT,
-		constant.STATISTIC,
-		constant.BUDGET,
-	}
-
-	return validate(strings.ToUpper(docType), docs)
-}
-
 func ValidateOrg(org string) bool {
 	orgs := []string{
 		constant.SCCU,
@@ -48,3 +38,53 @@ func ValidateRole(role string) bool {
 
 	return validate(strings.ToUpper(role), roles)
 }
+
+func ValidateAttachmentType(typeID string) bool {
+	types := []string{
+		constant.IMAGE,
+		constant.DOCS,
+	}
+
+	return validate(strings.ToUpper(typeID), types)
+}
+
+func ValidateTagMode(mode string) bool {
+	modes := []string{
+		constant.TAG_MODE_ANY,
+		constant.TAG_MODE_ALL,
+	}
+
+	return validate(strings.ToLower(mode), modes)
+}
+
+func ValidateLocale(locale string) bool {
+	return validate(strings.ToLower(locale), constant.SupportedLocales[:])
+}
+
+func ValidateContentFormat(format string) bool {
+	return validate(strings.ToLower(format), constant.ContentFormats[:])
+}
+
+func ValidateBudgetCurrency(currency string) bool {
+	return validate(strings.ToUpper(currency), constant.BudgetCurrencies[:])
+}
+
+func ValidateAggregate(aggregate string) bool {
+	return validate(strings.ToLower(aggregate), constant.DatasetAggregates[:])
+}
+
+func ValidateDocumentLinkType(linkType string) bool {
+	return validate(strings.ToUpper(linkType), constant.DocumentLinkTypes[:])
+}
+
+func ValidateBulkOperation(operation string) bool {
+	return validate(strings.ToLower(operation), constant.BulkOperations[:])
+}
+
+func ValidateBulkMode(mode string) bool {
+	return validate(strings.ToLower(mode), constant.BulkModes[:])
+}
+
+func ValidateAttachmentVisibility(visibility string) bool {
+	return validate(strings.ToUpper(visibility), constant.AttachmentVisibilities[:])
+}